@@ -0,0 +1,463 @@
+package checker
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/tkuchiki/alp/options"
+	"github.com/tkuchiki/alp/stats"
+)
+
+type Checker struct {
+	outWriter io.Writer
+	errWriter io.Writer
+	printer   *Printer
+	options   *options.Options
+	rules     []*rule
+}
+
+// Result is the result of a limit of a rule for an endpoint
+type Result struct {
+	Rule   string
+	Method string
+	Uri    string
+	Metric string
+	// Value is the value of the metric, or the change from the baseline in percent if Change is true
+	Value  float64
+	Change bool
+	Limit  string
+	Passed bool
+	// Skipped reports whether the limit is not checked, e.g. the endpoint is not in the baseline
+	Skipped bool
+	// Reason is the reason why the result is skipped or failed without the value
+	Reason string
+}
+
+type rule struct {
+	name  string
+	re    *regexp.Regexp
+	value metricFunc
+	// missing is the reason why the stat has no data of the metric
+	missing string
+	*options.CheckRule
+}
+
+func NewChecker(outw, errw io.Writer, opts *options.Options) *Checker {
+	return &Checker{
+		outWriter: outw,
+		errWriter: errw,
+		printer:   NewPrinter(outw, opts.Format),
+		options:   opts,
+	}
+}
+
+// Init compiles the rules of the options
+func (c *Checker) Init() error {
+	if len(c.options.Check.Rules) == 0 {
+		return fmt.Errorf("no check rules are found in the configuration file")
+	}
+
+	if err := c.printer.Validate(); err != nil {
+		return err
+	}
+
+	rules := make([]*rule, 0, len(c.options.Check.Rules))
+	for i, r := range c.options.Check.Rules {
+		name := r.Name
+		if name == "" {
+			name = fmt.Sprintf("rule%d", i+1)
+		}
+
+		value, missing, err := metricValue(r.Metric)
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+
+		if r.Max == nil && r.Min == nil && r.MaxIncrease == nil && r.MaxDecrease == nil {
+			return fmt.Errorf("%s: one of max, min, max_increase and max_decrease is required", name)
+		}
+
+		var re *regexp.Regexp
+		if r.Uri != "" {
+			re, err = regexp.Compile(r.Uri)
+			if err != nil {
+				return fmt.Errorf("%s: %w", name, err)
+			}
+		}
+
+		rules = append(rules, &rule{
+			name:      name,
+			re:        re,
+			value:     value,
+			missing:   missing,
+			CheckRule: r,
+		})
+	}
+
+	c.rules = rules
+
+	return nil
+}
+
+// metricFunc returns the value of the metric of s in sts, and false if sts has no data of the metric
+type metricFunc func(sts *stats.HTTPStats, s *stats.HTTPStat) (float64, bool)
+
+// always returns the metricFunc of the value that every stat has
+func always(value func(s *stats.HTTPStat) float64) metricFunc {
+	return func(_ *stats.HTTPStats, s *stats.HTTPStat) (float64, bool) {
+		return value(s), true
+	}
+}
+
+// ifHas returns the metricFunc of the value that the stat has only if has returns true
+func ifHas(value func(s *stats.HTTPStat) float64, has func(s *stats.HTTPStat) bool) metricFunc {
+	return func(_ *stats.HTTPStats, s *stats.HTTPStat) (float64, bool) {
+		return value(s), has(s)
+	}
+}
+
+const (
+	noResponseTimeSamples     = "no samples of the response times"
+	noRequestBodyBytesSamples = "no samples of the request body bytes"
+	noResponseBodySamples     = "no samples of the response body bytes"
+)
+
+// metricValue returns the function that returns the value of the metric, and the reason why the data of the metric is missing.
+// The metrics are the keywords of --output that have the numeric values.
+func metricValue(metric string) (metricFunc, string, error) {
+	hasTime := func(s *stats.HTTPStat) bool { return !s.FirstTime.IsZero() }
+
+	switch metric {
+	case "count":
+		return always(func(s *stats.HTTPStat) float64 { return float64(s.Cnt) }), "", nil
+	case "1xx":
+		return always(func(s *stats.HTTPStat) float64 { return float64(s.Status1xx) }), "", nil
+	case "2xx":
+		return always(func(s *stats.HTTPStat) float64 { return float64(s.Status2xx) }), "", nil
+	case "3xx":
+		return always(func(s *stats.HTTPStat) float64 { return float64(s.Status3xx) }), "", nil
+	case "4xx":
+		return always(func(s *stats.HTTPStat) float64 { return float64(s.Status4xx) }), "", nil
+	case "5xx":
+		return always(func(s *stats.HTTPStat) float64 { return float64(s.Status5xx) }), "", nil
+	case "4xx_rate":
+		return always((*stats.HTTPStat).Status4xxRate), "", nil
+	case "5xx_rate":
+		return always((*stats.HTTPStat).Status5xxRate), "", nil
+	case "rps":
+		return ifHas((*stats.HTTPStat).RequestsPerSecond, hasTime), "no time of the logs", nil
+	case "apdex":
+		value := func(sts *stats.HTTPStats, s *stats.HTTPStat) (float64, bool) {
+			return s.Apdex(), sts.CountsApdex()
+		}
+
+		return value, "the Apdex score is not counted, see --apdex-threshold", nil
+	case "min":
+		return always((*stats.HTTPStat).MinResponseTime), "", nil
+	case "max":
+		return always((*stats.HTTPStat).MaxResponseTime), "", nil
+	case "sum":
+		return always((*stats.HTTPStat).SumResponseTime), "", nil
+	case "avg":
+		return always((*stats.HTTPStat).AvgResponseTime), "", nil
+	case "stddev":
+		return ifHas((*stats.HTTPStat).StddevResponseTime, (*stats.HTTPStat).HasResponseTimeSamples), noResponseTimeSamples, nil
+	case "min_body":
+		return always((*stats.HTTPStat).MinResponseBodyBytes), "", nil
+	case "max_body":
+		return always((*stats.HTTPStat).MaxResponseBodyBytes), "", nil
+	case "sum_body":
+		return always((*stats.HTTPStat).SumResponseBodyBytes), "", nil
+	case "avg_body":
+		return always((*stats.HTTPStat).AvgResponseBodyBytes), "", nil
+	case "stddev_body":
+		return ifHas((*stats.HTTPStat).StddevResponseBodyBytes, (*stats.HTTPStat).HasResponseBodyBytesSamples), noResponseBodySamples, nil
+	case "min_req_body":
+		return always((*stats.HTTPStat).MinRequestBodyBytes), "", nil
+	case "max_req_body":
+		return always((*stats.HTTPStat).MaxRequestBodyBytes), "", nil
+	case "sum_req_body":
+		return always((*stats.HTTPStat).SumRequestBodyBytes), "", nil
+	case "avg_req_body":
+		return always((*stats.HTTPStat).AvgRequestBodyBytes), "", nil
+	case "stddev_req_body":
+		return ifHas((*stats.HTTPStat).StddevRequestBodyBytes, (*stats.HTTPStat).HasRequestBodyBytesSamples), noRequestBodyBytesSamples, nil
+	}
+
+	if code, err := strconv.Atoi(metric); err == nil && code >= 100 && code <= 599 {
+		// the status code that is not counted is not in the map, as the status code that does not occur
+		value := func(sts *stats.HTTPStats, s *stats.HTTPStat) (float64, bool) {
+			cnt, ok := s.StatusCodes[code]
+			return float64(cnt), ok || sts.CountsStatusCode(code)
+		}
+
+		return value, fmt.Sprintf("status code %d is not counted, see --status-codes", code), nil
+	}
+
+	n, suffix, ok := stats.ParsePercentileKeyword(metric)
+	if !ok || n < 0 || n > 100 {
+		return nil, "", fmt.Errorf("invalid metric: '%s'", metric)
+	}
+
+	switch suffix {
+	case "_body":
+		return ifHas(func(s *stats.HTTPStat) float64 { return s.PNResponseBodyBytes(n) }, (*stats.HTTPStat).HasResponseBodyBytesSamples), noResponseBodySamples, nil
+	case "_req_body":
+		return ifHas(func(s *stats.HTTPStat) float64 { return s.PNRequestBodyBytes(n) }, (*stats.HTTPStat).HasRequestBodyBytesSamples), noRequestBodyBytesSamples, nil
+	}
+
+	return ifHas(func(s *stats.HTTPStat) float64 { return s.PNResponseTime(n) }, (*stats.HTTPStat).HasResponseTimeSamples), noResponseTimeSamples, nil
+}
+
+func (r *rule) match(s *stats.HTTPStat) bool {
+	if r.re != nil && !r.re.MatchString(s.Uri) {
+		return false
+	}
+
+	if len(r.Methods) == 0 {
+		return true
+	}
+
+	for _, method := range r.Methods {
+		if method == s.Method {
+			return true
+		}
+	}
+
+	return false
+}
+
+// check checks the limits of the rule for s of sts, and base is the stat of baseline or nil.
+// The limits fail if s has no data of the metric, because the value of the missing data is 0.
+func (r *rule) check(sts *stats.HTTPStats, s *stats.HTTPStat, baseline *stats.HTTPStats, base *stats.HTTPStat) []*Result {
+	results := make([]*Result, 0)
+	newResult := func(value float64, change bool, limit string, passed bool) *Result {
+		return &Result{
+			Rule:   r.name,
+			Method: s.Method,
+			Uri:    s.Uri,
+			Metric: r.Metric,
+			Value:  value,
+			Change: change,
+			Limit:  limit,
+			Passed: passed,
+		}
+	}
+
+	v, ok := r.value(sts, s)
+	if !ok {
+		for _, limit := range r.limits() {
+			result := newResult(0, false, limit, false)
+			result.Reason = r.missing
+			results = append(results, result)
+		}
+
+		return results
+	}
+
+	if r.Max != nil {
+		results = append(results, newResult(v, false, maxLimit(*r.Max), v <= *r.Max))
+	}
+
+	if r.Min != nil {
+		results = append(results, newResult(v, false, minLimit(*r.Min), v >= *r.Min))
+	}
+
+	if r.MaxIncrease == nil && r.MaxDecrease == nil {
+		return results
+	}
+
+	// the change cannot be calculated if the endpoint is not in the baseline, or the value of the baseline is 0
+	var reason string
+	var change float64
+	if base == nil {
+		reason = "not in the baseline"
+	} else if bv, ok := r.value(baseline, base); !ok {
+		reason = fmt.Sprintf("%s in the baseline", r.missing)
+	} else if bv == 0 {
+		reason = "the baseline is 0"
+	} else {
+		change = (v - bv) / bv * 100
+	}
+
+	changeResult := func(limit string, passed bool) *Result {
+		result := newResult(change, true, limit, passed)
+		if reason != "" {
+			result.Passed = false
+			result.Skipped = true
+			result.Reason = reason
+		}
+
+		return result
+	}
+
+	if r.MaxIncrease != nil {
+		results = append(results, changeResult(increaseLimit(*r.MaxIncrease), change <= *r.MaxIncrease))
+	}
+
+	if r.MaxDecrease != nil {
+		results = append(results, changeResult(decreaseLimit(*r.MaxDecrease), -change <= *r.MaxDecrease))
+	}
+
+	return results
+}
+
+func maxLimit(v float64) string {
+	return fmt.Sprintf("<= %.3f", v)
+}
+
+func minLimit(v float64) string {
+	return fmt.Sprintf(">= %.3f", v)
+}
+
+func increaseLimit(v float64) string {
+	return fmt.Sprintf("increase <= %.3f%%", v)
+}
+
+func decreaseLimit(v float64) string {
+	return fmt.Sprintf("decrease <= %.3f%%", v)
+}
+
+// limits returns the descriptions of the limits of the rule
+func (r *rule) limits() []string {
+	limits := make([]string, 0, 4)
+	if r.Max != nil {
+		limits = append(limits, maxLimit(*r.Max))
+	}
+	if r.Min != nil {
+		limits = append(limits, minLimit(*r.Min))
+	}
+	if r.MaxIncrease != nil {
+		limits = append(limits, increaseLimit(*r.MaxIncrease))
+	}
+	if r.MaxDecrease != nil {
+		limits = append(limits, decreaseLimit(*r.MaxDecrease))
+	}
+
+	return limits
+}
+
+// unmatched returns the failed result of the rule that matches no endpoint
+func (r *rule) unmatched() *Result {
+	return &Result{
+		Rule:   r.name,
+		Method: strings.Join(r.Methods, ","),
+		Uri:    r.Uri,
+		Metric: r.Metric,
+		Limit:  "matches any endpoint",
+		Reason: "no endpoint matches the rule",
+	}
+}
+
+// Check checks the rules for each stat of sts, and baseline is the stats to compare with or nil.
+// The rule that matches no endpoint fails, because the typo of the URI or the methods would pass every check.
+func (c *Checker) Check(sts, baseline *stats.HTTPStats) []*Result {
+	results := make([]*Result, 0)
+	for _, r := range c.rules {
+		matched := false
+		for _, s := range sts.Stats() {
+			if !r.match(s) {
+				continue
+			}
+			matched = true
+
+			var base *stats.HTTPStat
+			if baseline != nil {
+				base = baseline.Find(s)
+			}
+
+			results = append(results, r.check(sts, s, baseline, base)...)
+		}
+
+		if !matched {
+			results = append(results, r.unmatched())
+		}
+	}
+
+	return results
+}
+
+// Violations returns the number of the results that are neither passed nor skipped
+func Violations(results []*Result) int {
+	var n int
+	for _, r := range results {
+		if !r.Passed && !r.Skipped {
+			n++
+		}
+	}
+
+	return n
+}
+
+func loadStats(filename string) (*stats.HTTPStats, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	sts := stats.NewHTTPStats(true, false, false)
+	if err = sts.LoadStats(f); err != nil {
+		return nil, err
+	}
+
+	return sts, nil
+}
+
+// ProfileOptions returns the copy of the options to profile the logs with,
+// which output the metrics of the rules so that the stats count them
+func (c *Checker) ProfileOptions() *options.Options {
+	opts := *c.options
+	opts.StatusCodes = slices.Clone(c.options.StatusCodes)
+
+	metrics := make([]string, 0, len(c.rules))
+	for _, r := range c.rules {
+		if slices.Contains(metrics, r.Metric) {
+			continue
+		}
+		metrics = append(metrics, r.Metric)
+
+		if code, err := strconv.Atoi(r.Metric); err == nil && !slices.Contains(opts.StatusCodes, code) {
+			opts.StatusCodes = append(opts.StatusCodes, code)
+		}
+	}
+	opts.Output = strings.Join(metrics, ",")
+
+	return &opts
+}
+
+// CheckAndPrint checks the rules for the dumped stats, and returns the error if there are violations
+func (c *Checker) CheckAndPrint(filename string) error {
+	sts, err := loadStats(filename)
+	if err != nil {
+		return err
+	}
+
+	return c.CheckStatsAndPrint(sts)
+}
+
+// CheckStatsAndPrint checks the rules for sts, and returns the error if there are violations
+func (c *Checker) CheckStatsAndPrint(sts *stats.HTTPStats) error {
+	var err error
+	var baseline *stats.HTTPStats
+	if c.options.Check.Baseline != "" {
+		baseline, err = loadStats(c.options.Check.Baseline)
+		if err != nil {
+			return err
+		}
+	}
+
+	results := c.Check(sts, baseline)
+	c.printer.Print(results)
+
+	if n := Violations(results); n > 0 {
+		return fmt.Errorf("%d of %d checks failed", n, len(results))
+	}
+
+	return nil
+}
@@ -0,0 +1,264 @@
+package checker
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/tkuchiki/alp/options"
+	"github.com/tkuchiki/alp/stats"
+)
+
+func float(f float64) *float64 {
+	return &f
+}
+
+func testStats(foo, bar float64) *stats.HTTPStats {
+	hs := stats.NewHTTPStats(true, false, false)
+	hs.Set("/foo", "GET", 200, foo, 0, 0)
+	hs.Set("/bar", "POST", 200, bar, 0, 0)
+
+	return hs
+}
+
+// check checks the rules for the stats of /foo and /bar, and prints the results in the format
+func check(t *testing.T, format string, rules ...*options.CheckRule) (string, int) {
+	t.Helper()
+
+	opts := options.NewOptions(options.Format(format))
+	opts.Check.Rules = rules
+
+	var buf bytes.Buffer
+	c := NewChecker(&buf, io.Discard, opts)
+	if err := c.Init(); err != nil {
+		t.Fatal(err)
+	}
+
+	// /bar is not in the baseline, and the value of /foo in the baseline is 0
+	baseline := stats.NewHTTPStats(true, false, false)
+	baseline.Set("/foo", "GET", 200, 0, 0, 0)
+
+	results := c.Check(testStats(0.3, 0.6), baseline)
+	c.printer.Print(results)
+
+	return buf.String(), Violations(results)
+}
+
+var testRules = []*options.CheckRule{
+	{Name: "max", Metric: "max", Max: float(0.5)},
+	{Name: "typo", Uri: "^/fooo$", Metric: "max", Max: float(0.5)},
+	{Name: "regression", Methods: []string{"GET", "POST"}, Metric: "max", MaxIncrease: float(10)},
+}
+
+func TestCheckTable(t *testing.T) {
+	got, violations := check(t, "table", testRules...)
+
+	want := `+------------+--------+---------+--------+-------------------------------+----------------------+
+|    RULE    | METHOD |   URI   | METRIC |             VALUE             |        LIMIT         |
++------------+--------+---------+--------+-------------------------------+----------------------+
+| max        | POST   | /bar    | max    | 0.600                         | <= 0.500             |
+| typo       |        | ^/fooo$ | max    | no endpoint matches the rule  | matches any endpoint |
+| regression | GET    | /foo    | max    | skipped (the baseline is 0)   | increase <= 10.000%  |
+| regression | POST   | /bar    | max    | skipped (not in the baseline) | increase <= 10.000%  |
++------------+--------+---------+--------+-------------------------------+----------------------+
+`
+	if got != want {
+		t.Errorf("want: %s, got: %s", want, got)
+	}
+
+	// the skipped results are not the violations
+	if violations != 2 {
+		t.Errorf("want: 2 violations, got: %d", violations)
+	}
+}
+
+func TestCheckJSON(t *testing.T) {
+	got, _ := check(t, "json", testRules...)
+
+	want := `[{"rule":"max","method":"POST","uri":"/bar","metric":"max","value":0.6,"change":false,"limit":"<= 0.500","skipped":false},` +
+		`{"rule":"typo","method":"","uri":"^/fooo$","metric":"max","value":null,"change":false,"limit":"matches any endpoint","skipped":false,"reason":"no endpoint matches the rule"},` +
+		`{"rule":"regression","method":"GET","uri":"/foo","metric":"max","value":null,"change":true,"limit":"increase <= 10.000%","skipped":true,"reason":"the baseline is 0"},` +
+		`{"rule":"regression","method":"POST","uri":"/bar","metric":"max","value":null,"change":true,"limit":"increase <= 10.000%","skipped":true,"reason":"not in the baseline"}]
+`
+	if got != want {
+		t.Errorf("want: %s, got: %s", want, got)
+	}
+}
+
+func TestCheckJUnit(t *testing.T) {
+	got, _ := check(t, "junit", testRules...)
+
+	want := `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites tests="5" failures="2" skipped="2">
+  <testsuite name="max" tests="2" failures="1" skipped="0">
+    <testcase classname="max" name="GET /foo max &lt;= 0.500"></testcase>
+    <testcase classname="max" name="POST /bar max &lt;= 0.500">
+      <failure message="max is 0.600, want &lt;= 0.500"></failure>
+    </testcase>
+  </testsuite>
+  <testsuite name="typo" tests="1" failures="1" skipped="0">
+    <testcase classname="typo" name=" ^/fooo$ max matches any endpoint">
+      <failure message="no endpoint matches the rule"></failure>
+    </testcase>
+  </testsuite>
+  <testsuite name="regression" tests="2" failures="0" skipped="2">
+    <testcase classname="regression" name="GET /foo max increase &lt;= 10.000%">
+      <skipped message="the baseline is 0"></skipped>
+    </testcase>
+    <testcase classname="regression" name="POST /bar max increase &lt;= 10.000%">
+      <skipped message="not in the baseline"></skipped>
+    </testcase>
+  </testsuite>
+</testsuites>
+`
+	if got != want {
+		t.Errorf("want: %s, got: %s", want, got)
+	}
+}
+
+func TestCheckChange(t *testing.T) {
+	opts := options.NewOptions()
+	opts.Check.Rules = []*options.CheckRule{
+		{Metric: "max", MaxIncrease: float(10), MaxDecrease: float(10)},
+	}
+
+	c := NewChecker(io.Discard, io.Discard, opts)
+	if err := c.Init(); err != nil {
+		t.Fatal(err)
+	}
+
+	results := c.Check(testStats(0.3, 0.5), testStats(0.2, 0.6))
+
+	want := []Result{
+		{Rule: "rule1", Method: "GET", Uri: "/foo", Metric: "max", Value: 50, Change: true, Limit: "increase <= 10.000%", Passed: false},
+		{Rule: "rule1", Method: "GET", Uri: "/foo", Metric: "max", Value: 50, Change: true, Limit: "decrease <= 10.000%", Passed: true},
+		{Rule: "rule1", Method: "POST", Uri: "/bar", Metric: "max", Value: -100.0 / 6, Change: true, Limit: "increase <= 10.000%", Passed: true},
+		{Rule: "rule1", Method: "POST", Uri: "/bar", Metric: "max", Value: -100.0 / 6, Change: true, Limit: "decrease <= 10.000%", Passed: false},
+	}
+	if len(results) != len(want) {
+		t.Fatalf("want: %d results, got: %d", len(want), len(results))
+	}
+	for i, r := range results {
+		if r.Rule != want[i].Rule || r.Uri != want[i].Uri || r.Limit != want[i].Limit || r.Passed != want[i].Passed ||
+			r.Skipped || r.Reason != "" || !r.Change || r.Value-want[i].Value > 1e-9 || want[i].Value-r.Value > 1e-9 {
+			t.Errorf("result %d want: %+v, got: %+v", i, want[i], *r)
+		}
+	}
+}
+
+func TestCheckMissingData(t *testing.T) {
+	newStats := func(statusCodes []int) *stats.HTTPStats {
+		// the response body bytes are not retained for the percentiles
+		hs := stats.NewHTTPStats(true, false, false)
+		hs.SetStatusCodes(statusCodes)
+		hs.SetOptions(options.NewOptions(options.StatusCodes(statusCodes)))
+		for i := 0; i < 3; i++ {
+			hs.Set("/foo", "GET", 499, 0.1, 5000, 0)
+		}
+
+		return hs
+	}
+
+	opts := options.NewOptions()
+	opts.Check.Rules = []*options.CheckRule{
+		{Name: "p99_body", Metric: "p99_body", Max: float(10)},
+		{Name: "499", Metric: "499", Max: float(0)},
+		{Name: "404", Metric: "404", Max: float(0)},
+		{Name: "rps", Metric: "rps", Min: float(1), MaxIncrease: float(10)},
+	}
+
+	c := NewChecker(io.Discard, io.Discard, opts)
+	if err := c.Init(); err != nil {
+		t.Fatal(err)
+	}
+
+	// the missing data fails instead of the value of 0
+	results := c.Check(newStats(nil), nil)
+	want := []Result{
+		{Rule: "p99_body", Limit: "<= 10.000", Reason: "no samples of the response body bytes"},
+		{Rule: "499", Limit: "<= 0.000", Reason: "status code 499 is not counted, see --status-codes"},
+		{Rule: "404", Limit: "<= 0.000", Reason: "status code 404 is not counted, see --status-codes"},
+		{Rule: "rps", Limit: ">= 1.000", Reason: "no time of the logs"},
+		{Rule: "rps", Limit: "increase <= 10.000%", Reason: "no time of the logs"},
+	}
+	if len(results) != len(want) {
+		t.Fatalf("want: %d results, got: %d", len(want), len(results))
+	}
+	for i, r := range results {
+		if r.Rule != want[i].Rule || r.Limit != want[i].Limit || r.Reason != want[i].Reason || r.Passed || r.Skipped {
+			t.Errorf("result %d want: %+v, got: %+v", i, want[i], *r)
+		}
+	}
+
+	// the status codes that are counted in the dump are checked, even if they do not occur
+	var buf bytes.Buffer
+	if err := newStats([]int{404, 499}).DumpStats(&buf); err != nil {
+		t.Fatal(err)
+	}
+	loaded := stats.NewHTTPStats(true, false, false)
+	if err := loaded.LoadStats(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	results = c.Check(loaded, nil)
+	for _, r := range results {
+		switch r.Rule {
+		case "499":
+			if r.Reason != "" || r.Passed || r.Value != 3 {
+				t.Errorf("want the violation of the counted status code, got: %+v", *r)
+			}
+		case "404":
+			if r.Reason != "" || !r.Passed || r.Value != 0 {
+				t.Errorf("want the status code that does not occur to pass, got: %+v", *r)
+			}
+		}
+	}
+}
+
+func TestCheckApdexDisabled(t *testing.T) {
+	opts := options.NewOptions()
+	opts.Check.Rules = []*options.CheckRule{
+		{Name: "apdex", Metric: "apdex", Min: float(0.9)},
+	}
+
+	c := NewChecker(io.Discard, io.Discard, opts)
+	if err := c.Init(); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		threshold float64
+		reason    string
+		passed    bool
+	}{
+		{threshold: 0.5, passed: true},
+		// --apdex-threshold 0 disables the Apdex score, which is not 0 but missing
+		{threshold: 0, reason: "the Apdex score is not counted, see --apdex-threshold"},
+	}
+
+	for _, tt := range tests {
+		hs := stats.NewHTTPStats(true, false, false)
+		hs.SetOptions(options.NewOptions(options.ApdexThreshold(tt.threshold)))
+		if err := hs.SetApdexThreshold(tt.threshold); err != nil {
+			t.Fatal(err)
+		}
+		hs.Set("/foo", "GET", 200, 0.1, 0, 0)
+
+		// the threshold of the dump is loaded from the metadata
+		var buf bytes.Buffer
+		if err := hs.DumpStats(&buf); err != nil {
+			t.Fatal(err)
+		}
+		loaded := stats.NewHTTPStats(true, false, false)
+		if err := loaded.LoadStats(&buf); err != nil {
+			t.Fatal(err)
+		}
+
+		for _, sts := range []*stats.HTTPStats{hs, loaded} {
+			results := c.Check(sts, nil)
+			if len(results) != 1 || results[0].Reason != tt.reason || results[0].Passed != tt.passed {
+				t.Errorf("threshold %v want: passed %v (%s), got: %+v", tt.threshold, tt.passed, tt.reason, *results[0])
+			}
+		}
+	}
+}
@@ -0,0 +1,215 @@
+package checker
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+
+	"github.com/olekukonko/tablewriter"
+)
+
+var headers = []string{"Rule", "Method", "Uri", "Metric", "Value", "Limit"}
+
+type Printer struct {
+	format string
+	writer io.Writer
+}
+
+func NewPrinter(w io.Writer, format string) *Printer {
+	return &Printer{
+		format: format,
+		writer: w,
+	}
+}
+
+func (p *Printer) Validate() error {
+	switch p.format {
+	case "table", "json", "junit":
+		return nil
+	}
+
+	return fmt.Errorf("enum value must be one of table,json,junit, got '%s'", p.format)
+}
+
+// Print prints the violations and the skipped results in table and JSON format, and every result in JUnit XML format
+func (p *Printer) Print(results []*Result) {
+	switch p.format {
+	case "table":
+		p.printTable(results)
+	case "json":
+		p.printJSON(results)
+	case "junit":
+		p.printJUnit(results)
+	}
+}
+
+// value returns the value of the metric, or the change from the baseline with the sign and the percent sign.
+// It returns the reason if the result has no value.
+func (r *Result) value() string {
+	if r.Reason != "" {
+		if r.Skipped {
+			return fmt.Sprintf("skipped (%s)", r.Reason)
+		}
+		return r.Reason
+	}
+
+	if r.Change {
+		return fmt.Sprintf("%+.3f%%", r.Value)
+	}
+
+	return fmt.Sprintf("%.3f", r.Value)
+}
+
+func (p *Printer) generateLine(r *Result) []string {
+	return []string{
+		r.Rule,
+		r.Method,
+		r.Uri,
+		r.Metric,
+		r.value(),
+		r.Limit,
+	}
+}
+
+func (p *Printer) printTable(results []*Result) {
+	table := tablewriter.NewWriter(p.writer)
+	table.SetHeader(headers)
+
+	for _, r := range results {
+		if r.Passed {
+			continue
+		}
+		table.Append(p.generateLine(r))
+	}
+
+	table.SetAlignment(tablewriter.ALIGN_LEFT)
+	table.Render()
+}
+
+type jsonResult struct {
+	Rule    string   `json:"rule"`
+	Method  string   `json:"method"`
+	Uri     string   `json:"uri"`
+	Metric  string   `json:"metric"`
+	Value   *float64 `json:"value"`
+	Change  bool     `json:"change"`
+	Limit   string   `json:"limit"`
+	Skipped bool     `json:"skipped"`
+	Reason  string   `json:"reason,omitempty"`
+}
+
+func (p *Printer) printJSON(results []*Result) {
+	violations := make([]*jsonResult, 0)
+	for _, r := range results {
+		if r.Passed {
+			continue
+		}
+
+		jr := &jsonResult{
+			Rule:    r.Rule,
+			Method:  r.Method,
+			Uri:     r.Uri,
+			Metric:  r.Metric,
+			Change:  r.Change,
+			Limit:   r.Limit,
+			Skipped: r.Skipped,
+			Reason:  r.Reason,
+		}
+		// the value is null if the result has no value
+		if r.Reason == "" {
+			value := r.Value
+			jr.Value = &value
+		}
+
+		violations = append(violations, jr)
+	}
+
+	// the limits such as "<= 0.500" are not escaped
+	enc := json.NewEncoder(p.writer)
+	enc.SetEscapeHTML(false)
+	enc.Encode(violations)
+}
+
+type junitTestSuites struct {
+	XMLName  xml.Name          `xml:"testsuites"`
+	Tests    int               `xml:"tests,attr"`
+	Failures int               `xml:"failures,attr"`
+	Skipped  int               `xml:"skipped,attr"`
+	Suites   []*junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string           `xml:"name,attr"`
+	Tests     int              `xml:"tests,attr"`
+	Failures  int              `xml:"failures,attr"`
+	Skipped   int              `xml:"skipped,attr"`
+	TestCases []*junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	ClassName string        `xml:"classname,attr"`
+	Name      string        `xml:"name,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	Skipped   *junitSkipped `xml:"skipped,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+}
+
+type junitSkipped struct {
+	Message string `xml:"message,attr"`
+}
+
+// printJUnit prints each rule as the test suite, and each result as the test case
+func (p *Printer) printJUnit(results []*Result) {
+	suites := &junitTestSuites{}
+	suiteIndex := make(map[string]int)
+
+	for _, r := range results {
+		i, ok := suiteIndex[r.Rule]
+		if !ok {
+			i = len(suites.Suites)
+			suiteIndex[r.Rule] = i
+			suites.Suites = append(suites.Suites, &junitTestSuite{
+				Name: r.Rule,
+			})
+		}
+		suite := suites.Suites[i]
+
+		tc := &junitTestCase{
+			ClassName: r.Rule,
+			Name:      fmt.Sprintf("%s %s %s %s", r.Method, r.Uri, r.Metric, r.Limit),
+		}
+		switch {
+		case r.Skipped:
+			tc.Skipped = &junitSkipped{
+				Message: r.Reason,
+			}
+			suite.Skipped++
+			suites.Skipped++
+		case r.Reason != "":
+			tc.Failure = &junitFailure{
+				Message: r.Reason,
+			}
+			suite.Failures++
+			suites.Failures++
+		case !r.Passed:
+			tc.Failure = &junitFailure{
+				Message: fmt.Sprintf("%s is %s, want %s", r.Metric, r.value(), r.Limit),
+			}
+			suite.Failures++
+			suites.Failures++
+		}
+
+		suite.Tests++
+		suites.Tests++
+		suite.TestCases = append(suite.TestCases, tc)
+	}
+
+	b, _ := xml.MarshalIndent(suites, "", "  ")
+
+	fmt.Fprint(p.writer, xml.Header)
+	fmt.Fprintln(p.writer, string(b))
+}
@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/tkuchiki/alp/checker"
+	"github.com/tkuchiki/alp/options"
+	"github.com/tkuchiki/alp/parsers"
+	"github.com/tkuchiki/alp/profiler"
+	"github.com/tkuchiki/alp/stats"
+)
+
+func newCheckCmd(flags *flags) *cobra.Command {
+	checkCmd := &cobra.Command{
+		Use:   "check <dump>",
+		Args:  cobra.ExactArgs(1),
+		Short: "Check the profile results against the rules of the configuration file",
+		Long: `Check the profile results against the rules of the configuration file, and exit with a non-zero status if any rule is violated.
+The profile results are dumped with --dump, or use the check subcommand of the parser to check the log, e.g. alp ltsv check`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts, err := flags.createCheckOptions(cmd)
+			if err != nil {
+				return err
+			}
+
+			c := checker.NewChecker(os.Stdout, os.Stderr, opts)
+			if err = c.Init(); err != nil {
+				return err
+			}
+
+			return c.CheckAndPrint(args[0])
+		},
+	}
+
+	flags.defineCheckOptions(checkCmd)
+
+	checkCmd.Flags().SortFlags = false
+	checkCmd.PersistentFlags().SortFlags = false
+	checkCmd.InheritedFlags().SortFlags = false
+
+	return checkCmd
+}
+
+func newCheckSubCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "check",
+		Args:  cobra.NoArgs,
+		Short: "Check the profile results of the log against the rules of the configuration file",
+		Long:  `Check the profile results of the log against the rules of the configuration file, and exit with a non-zero status if any rule is violated`,
+	}
+}
+
+// newCheckProfiler returns the checker of the rules, and the profiler that counts the metrics of the rules
+func newCheckProfiler(opts *options.Options) (*checker.Checker, *profiler.Profiler, error) {
+	c := checker.NewChecker(os.Stdout, os.Stderr, opts)
+	if err := c.Init(); err != nil {
+		return nil, nil, err
+	}
+
+	// the log is profiled once, even if the configuration file follows the log or loads the dumps
+	profOpts := c.ProfileOptions()
+	profOpts.Follow = false
+	profOpts.MetricsListen = ""
+
+	prof := profiler.NewProfiler(os.Stdout, os.Stderr, profOpts)
+	prof.DisableLoad()
+
+	return c, prof, nil
+}
+
+func runCheck(sortOptions *stats.SortOptions, c *checker.Checker, prof *profiler.Profiler, parser parsers.Parser) error {
+	sts, err := prof.Profile(sortOptions, parser)
+	if err != nil {
+		return err
+	}
+
+	return c.CheckStatsAndPrint(sts)
+}
@@ -0,0 +1,165 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/tkuchiki/alp/internal/testutil"
+)
+
+func TestCheckCmd(t *testing.T) {
+	baseline := "../../../example/logs/dump1.yaml"
+	dump := "../../../example/logs/dump2.yaml"
+
+	tests := []struct {
+		rules   string
+		wantErr bool
+	}{
+		{
+			rules: `check:
+  rules:
+    - name: p99
+      uri: '^/foo/'
+      metric: p99
+      max: 100
+    - name: count
+      methods: [GET, POST]
+      metric: count
+      min: 1
+`,
+		},
+		{
+			rules: `check:
+  rules:
+    - name: 5xx
+      metric: 5xx
+      max: 0
+`,
+			wantErr: true,
+		},
+		{
+			rules: `check:
+  rules:
+    - name: avg regression
+      metric: avg
+      max_increase: 0
+`,
+			wantErr: true,
+		},
+		{
+			rules: `check:
+  rules:
+    - name: invalid metric
+      metric: unknown
+      max: 1
+`,
+			wantErr: true,
+		},
+		{
+			// the rule that matches no endpoint fails
+			rules: `check:
+  rules:
+    - name: typo
+      uri: '^/fooo/'
+      metric: p99
+      max: 100
+`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		config := filepath.Join(t.TempDir(), "config.yml")
+		if err := os.WriteFile(config, []byte(tt.rules), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		for _, format := range []string{"table", "json", "junit"} {
+			args := []string{"check",
+				"--config", config,
+				"--baseline", baseline,
+				"--format", format,
+				dump,
+			}
+
+			command := NewCommand("test")
+			command.setArgs(args)
+
+			err := command.Execute()
+			if tt.wantErr && err == nil {
+				t.Errorf("%s: want error, got nil", tt.rules)
+			} else if !tt.wantErr && err != nil {
+				t.Errorf("%s: %v", tt.rules, err)
+			}
+		}
+	}
+}
+
+func TestCheckSubCmd(t *testing.T) {
+	keys := testutil.NewLTSVLogKeys()
+	ltsvLog := testutil.LTSVLog(keys)
+
+	tempFile, err := testutil.CreateTempDirAndFile(t.TempDir(), "test_check_sub_cmd_temp_file", ltsvLog)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		rules   string
+		wantErr bool
+	}{
+		{
+			// the metrics of the rules are counted without the dump
+			rules: `check:
+  rules:
+    - name: count
+      uri: '^/foo/bar/\.\+$'
+      metric: count
+      min: 2
+    - name: p99_body
+      metric: p99_body
+      max: 100
+    - name: "200"
+      metric: "200"
+      min: 2
+    - name: rps
+      metric: rps
+      min: 0
+`,
+		},
+		{
+			rules: `check:
+  rules:
+    - name: max
+      metric: max
+      max: 0.05
+`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		config := filepath.Join(t.TempDir(), "config.yml")
+		if err := os.WriteFile(config, []byte(tt.rules), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		args := []string{"ltsv", "check",
+			"--config", config,
+			"--file", tempFile,
+			"--matching-groups", `/foo/bar/.+`,
+			"--format", "json",
+		}
+
+		command := NewCommand("test")
+		command.setArgs(args)
+
+		err := command.Execute()
+		if tt.wantErr && err == nil {
+			t.Errorf("%s: want error, got nil", tt.rules)
+		} else if !tt.wantErr && err != nil {
+			t.Errorf("%s: %v", tt.rules, err)
+		}
+	}
+}
@@ -14,6 +14,12 @@ type Command struct {
 	// alp count
 	countCmd *cobra.Command
 
+	// alp check
+	checkCmd *cobra.Command
+
+	// alp merge
+	mergeCmd *cobra.Command
+
 	// alp json
 	jsonCmd *cobra.Command
 	// alp json diff
@@ -22,6 +28,10 @@ type Command struct {
 	jsonTopNCmd *cobra.Command
 	// alp json count
 	jsonCountCmd *cobra.Command
+	// alp json suggest-groups
+	jsonSuggestGroupsCmd *cobra.Command
+	// alp json check
+	jsonCheckCmd *cobra.Command
 
 	// alp ltsv
 	ltsvCmd *cobra.Command
@@ -31,6 +41,10 @@ type Command struct {
 	ltsvTopNCmd *cobra.Command
 	// alp ltsv count
 	ltsvCountCmd *cobra.Command
+	// alp ltsv suggest-groups
+	ltsvSuggestGroupsCmd *cobra.Command
+	// alp ltsv check
+	ltsvCheckCmd *cobra.Command
 
 	// alp regexp
 	regexpCmd *cobra.Command
@@ -40,6 +54,10 @@ type Command struct {
 	regexpTopNCmd *cobra.Command
 	// alp regexp count
 	regexpCountCmd *cobra.Command
+	// alp regexp suggest-groups
+	regexpSuggestGroupsCmd *cobra.Command
+	// alp regexp check
+	regexpCheckCmd *cobra.Command
 
 	// alp pcap
 	pcapCmd *cobra.Command
@@ -47,6 +65,10 @@ type Command struct {
 	pcapDiffCmd *cobra.Command
 	// alp pcap topN
 	pcapTopNCmd *cobra.Command
+	// alp pcap suggest-groups
+	pcapSuggestGroupsCmd *cobra.Command
+	// alp pcap check
+	pcapCheckCmd *cobra.Command
 
 	flags *flags
 }
@@ -71,6 +93,12 @@ func NewCommand(version string) *Command {
 	// alp ltsv count
 	command.ltsvCountCmd = newLTSVCountCmd(command.flags)
 	command.ltsvCmd.AddCommand(command.ltsvCountCmd)
+	// alp ltsv suggest-groups
+	command.ltsvSuggestGroupsCmd = newLTSVSuggestGroupsCmd(command.flags)
+	command.ltsvCmd.AddCommand(command.ltsvSuggestGroupsCmd)
+	// alp ltsv check
+	command.ltsvCheckCmd = newLTSVCheckCmd(command.flags)
+	command.ltsvCmd.AddCommand(command.ltsvCheckCmd)
 
 	// alp json
 	command.jsonCmd = newJSONCmd(command.flags)
@@ -84,6 +112,12 @@ func NewCommand(version string) *Command {
 	// alp json count
 	command.jsonCountCmd = newJsonCountCmd(command.flags)
 	command.jsonCmd.AddCommand(command.jsonCountCmd)
+	// alp json suggest-groups
+	command.jsonSuggestGroupsCmd = newJsonSuggestGroupsCmd(command.flags)
+	command.jsonCmd.AddCommand(command.jsonSuggestGroupsCmd)
+	// alp json check
+	command.jsonCheckCmd = newJsonCheckCmd(command.flags)
+	command.jsonCmd.AddCommand(command.jsonCheckCmd)
 
 	// alp regexp
 	command.regexpCmd = newRegexpCmd(command.flags)
@@ -97,6 +131,12 @@ func NewCommand(version string) *Command {
 	// alp regexp count
 	command.regexpCountCmd = newRegexpCountCmd(command.flags)
 	command.regexpCmd.AddCommand(command.regexpCountCmd)
+	// alp regexp suggest-groups
+	command.regexpSuggestGroupsCmd = newRegexpSuggestGroupsCmd(command.flags)
+	command.regexpCmd.AddCommand(command.regexpSuggestGroupsCmd)
+	// alp regexp check
+	command.regexpCheckCmd = newRegexpCheckCmd(command.flags)
+	command.regexpCmd.AddCommand(command.regexpCheckCmd)
 
 	// alp pcap
 	command.pcapCmd = newPcapCmd(command.flags)
@@ -107,11 +147,25 @@ func NewCommand(version string) *Command {
 	// alp pcap topN
 	command.pcapTopNCmd = newPcapTopNCmd(command.flags)
 	command.pcapCmd.AddCommand(command.pcapTopNCmd)
+	// alp pcap suggest-groups
+	command.pcapSuggestGroupsCmd = newPcapSuggestGroupsCmd(command.flags)
+	command.pcapCmd.AddCommand(command.pcapSuggestGroupsCmd)
+	// alp pcap check
+	command.pcapCheckCmd = newPcapCheckCmd(command.flags)
+	command.pcapCmd.AddCommand(command.pcapCheckCmd)
 
 	// alp diff
 	command.diffCmd = newDiffCmd(command.flags)
 	command.rootCmd.AddCommand(command.diffCmd)
 
+	// alp check
+	command.checkCmd = newCheckCmd(command.flags)
+	command.rootCmd.AddCommand(command.checkCmd)
+
+	// alp merge
+	command.mergeCmd = newMergeCmd(command.flags)
+	command.rootCmd.AddCommand(command.mergeCmd)
+
 	return command
 }
 
@@ -1,6 +1,11 @@
 package cmd
 
 import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
@@ -15,6 +20,20 @@ func TestCommonFlags(t *testing.T) {
 		t.Fatal(err)
 	}
 
+	var gzipLog bytes.Buffer
+	zw := gzip.NewWriter(&gzipLog)
+	if _, err = zw.Write([]byte(testutil.JsonLog(testutil.NewJsonLogKeys()))); err != nil {
+		t.Fatal(err)
+	}
+	if err = zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	tempGzipLog, err := testutil.CreateTempDirAndFile(tempDir, "test_common_flags_temp_log.gz", gzipLog.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+
 	tempConfig, err := testutil.CreateTempDirAndFile(tempDir, "test_common_flags_temp_config", testutil.ConfigFile())
 	if err != nil {
 		t.Fatal(err)
@@ -30,6 +49,8 @@ func TestCommonFlags(t *testing.T) {
 		t.Fatal(err)
 	}
 
+	tempBinaryDump := filepath.Join(tempDir, "test_common_flags_temp_dump.bin")
+
 	tests := []struct {
 		args []string
 	}{
@@ -70,6 +91,72 @@ func TestCommonFlags(t *testing.T) {
 				"-r",
 			},
 		},
+		{
+			args: []string{"json",
+				"--file", tempLog,
+				"--percentile-estimator", "sketch",
+				"--percentile-accuracy", "0.05",
+			},
+		},
+		{
+			args: []string{"json",
+				"--file", tempLog,
+				"--workers", "4",
+			},
+		},
+		{
+			args: []string{"json",
+				"--file", tempLog,
+				"--status-codes", "200,404",
+				"--output", "count,200,404,other,uri",
+				"--sort", "404",
+			},
+		},
+		{
+			args: []string{"json",
+				"--file", tempLog,
+				"--group-by", "ua",
+				"--output", "count,method,uri,ua",
+				"--sort", "ua",
+			},
+		},
+		{
+			args: []string{"json",
+				"--file", tempLog,
+				"--timeline", "1m",
+			},
+		},
+		{
+			args: []string{"json",
+				"--file", tempLog,
+				"--histogram",
+			},
+		},
+		{
+			args: []string{"json",
+				"--file", tempLog,
+				"--histogram",
+				"--histogram-buckets", "5",
+				"--histogram-scale", "log",
+				"--histogram-uri", "^/foo",
+				"--format", "json",
+			},
+		},
+		{
+			args: []string{"json",
+				"--file", tempGzipLog,
+			},
+		},
+		{
+			args: []string{"json",
+				"--file", strings.Join([]string{tempLog, tempGzipLog}, ","),
+			},
+		},
+		{
+			args: []string{"json",
+				"--file", filepath.Join(tempDir, "test_common_flags_temp_log*"),
+			},
+		},
 		{
 			args: []string{"json",
 				"--file", tempLog,
@@ -95,6 +182,34 @@ func TestCommonFlags(t *testing.T) {
 				"--load", tempDump,
 			},
 		},
+		{
+			args: []string{"json",
+				"--load", strings.Join([]string{tempDump, tempDump}, ","),
+			},
+		},
+		{
+			args: []string{"json",
+				"--file", tempLog,
+				"--dump", tempBinaryDump,
+			},
+		},
+		{
+			args: []string{"json",
+				"--load", strings.Join([]string{tempBinaryDump, tempDump}, ","),
+			},
+		},
+		{
+			args: []string{"json",
+				"--file", tempLog,
+				"--dump", tempDump,
+				"--dump-format", "binary",
+			},
+		},
+		{
+			args: []string{"json",
+				"--load", tempDump,
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -109,3 +224,50 @@ func TestCommonFlags(t *testing.T) {
 		})
 	}
 }
+
+func TestSQLiteFormat(t *testing.T) {
+	tempDir := t.TempDir()
+
+	tempLog, err := testutil.CreateTempDirAndFile(tempDir, "test_sqlite_format_temp_log", testutil.JsonLog(testutil.NewJsonLogKeys()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tempDB := filepath.Join(tempDir, "test_sqlite_format.db")
+
+	command := NewCommand("test")
+	command.setArgs([]string{"json", "--file", tempLog, "--format", "sqlite"})
+	if err = command.Execute(); err == nil {
+		t.Fatal("--format sqlite without --dump must be an error")
+	}
+
+	command = NewCommand("test")
+	command.setArgs([]string{"json", "--file", tempLog, "--format", "sqlite", "--dump", tempDB, "--group-by", "ua"})
+	if err = command.Execute(); err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := os.ReadFile(tempDB)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.HasPrefix(b, []byte("SQLite format 3\x00")) {
+		t.Fatalf("invalid header: %q", b[:16])
+	}
+
+	// the database size in the header is the number of the pages of 4096 bytes
+	if len(b)%4096 != 0 || int(binary.BigEndian.Uint32(b[28:])) != len(b)/4096 {
+		t.Fatalf("database size want: %d pages, got: %d pages and %d bytes", binary.BigEndian.Uint32(b[28:]), len(b)/4096, len(b))
+	}
+
+	for _, sql := range []string{
+		`CREATE TABLE "requests"`,
+		`CREATE TABLE "stats" ("id" INTEGER PRIMARY KEY, "method" TEXT, "uri" TEXT, "ua" TEXT,`,
+		`CREATE INDEX "requests_time" ON "requests" ("time")`,
+	} {
+		if !bytes.Contains(b[:4096], []byte(sql)) {
+			t.Errorf("the schema does not contain %s", sql)
+		}
+	}
+}
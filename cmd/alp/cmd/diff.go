@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"fmt"
 	"os"
 
 	"github.com/spf13/cobra"
@@ -33,9 +34,11 @@ func newDiffCmd(flags *flags) *cobra.Command {
 
 			sts.SetOptions(opts)
 			sts.SetSortOptions(flags.sortOptions)
+			sts.SetGroupBy(opts.GroupBy)
 
-			printOptions := stats.NewPrintOptions(opts.NoHeaders, opts.ShowFooters, opts.DecodeUri, opts.PaginationLimit)
-			printer := stats.NewPrinter(os.Stdout, opts.Output, opts.Format, opts.Percentiles, printOptions)
+			printOptions := stats.NewPrintOptions(opts.NoHeaders, opts.ShowFooters, opts.DecodeUri, opts.PaginationLimit, false)
+			printer := stats.NewPrinter(os.Stdout, opts.Output, opts.Format, opts.Percentiles, opts.StatusCodes, opts.GroupBy, printOptions)
+			printer.SetSignificanceLevel(opts.SignificanceLevel)
 			if err = printer.Validate(); err != nil {
 				return err
 			}
@@ -60,6 +63,7 @@ func newDiffCmd(flags *flags) *cobra.Command {
 
 			toSts.SetOptions(opts)
 			toSts.SetSortOptions(flags.sortOptions)
+			toSts.SetGroupBy(opts.GroupBy)
 
 			tof, err := os.Open(to)
 			if err != nil {
@@ -71,6 +75,11 @@ func newDiffCmd(flags *flags) *cobra.Command {
 			}
 			defer tof.Close()
 
+			toSts.SetDiffFrom(sts)
+
+			for _, w := range stats.GroupingWarnings(sts.Metadata(), toSts.Metadata()) {
+				fmt.Fprintf(os.Stderr, "Warning: %s\n", w)
+			}
 			toSts.SortWithOptions()
 
 			printer.Print(sts, toSts)
@@ -3,6 +3,7 @@ package cmd
 import (
 	"strings"
 
+	"github.com/mitchellh/mapstructure"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 	"github.com/tkuchiki/alp/helpers"
@@ -14,6 +15,7 @@ const (
 	flagConfig                  = "config"
 	flagFile                    = "file"
 	flagDump                    = "dump"
+	flagDumpFormat              = "dump-format"
 	flagLoad                    = "load"
 	flagFormat                  = "format"
 	flagSort                    = "sort"
@@ -31,16 +33,34 @@ const (
 	flagPositionFile            = "pos"
 	flagNoSavePositionFile      = "nosave-pos"
 	flagPercentiles             = "percentiles"
+	flagStatusCodes             = "status-codes"
+	flagGroupBy                 = "group-by"
 	flagPage                    = "page"
+	flagPercentileEstimator     = "percentile-estimator"
+	flagPercentileAccuracy      = "percentile-accuracy"
+	flagApdexThreshold          = "apdex-threshold"
+	flagSignificanceLevel       = "significance-level"
+	flagWorkers                 = "workers"
+	flagTimeline                = "timeline"
+	flagFollow                  = "follow"
+	flagFollowInterval          = "follow-interval"
+	flagMetricsListen           = "metrics-listen"
+	flagMetricsBuckets          = "metrics-buckets"
+	flagOpenAPI                 = "openapi"
+	flagHistogram               = "histogram"
+	flagHistogramBuckets        = "histogram-buckets"
+	flagHistogramScale          = "histogram-scale"
+	flagHistogramUri            = "histogram-uri"
 
 	// json
-	flagJSONUriKey       = "uri-key"
-	flagJSONMethodKey    = "method-key"
-	flagJSONTimeKey      = "time-key"
-	flagJSONRestimeKey   = "restime-key"
-	flagJSONReqtimeKey   = "reqtime-key"
-	flagJSONBodyBytesKey = "body-bytes-key"
-	flagJSONStatusKey    = "status-key"
+	flagJSONUriKey              = "uri-key"
+	flagJSONMethodKey           = "method-key"
+	flagJSONTimeKey             = "time-key"
+	flagJSONRestimeKey          = "restime-key"
+	flagJSONReqtimeKey          = "reqtime-key"
+	flagJSONBodyBytesKey        = "body-bytes-key"
+	flagJSONRequestBodyBytesKey = "request-body-bytes-key"
+	flagJSONStatusKey           = "status-key"
 
 	// ltsv
 	flagLTSVUriLabel     = "uri-label"
@@ -49,17 +69,19 @@ const (
 	flagLTSVApptimeLabel = "apptime-label"
 	flagLTSVReqtimeLabel = "reqtime-label"
 	flagLTSVSizeLabel    = "size-label"
+	flagLTSVReqsizeLabel = "reqsize-label"
 	flagLTSVStatusLabel  = "status-label"
 
 	// regexp
-	flagRegexpPattern         = "pattern"
-	flagRegexpUriSubexp       = "uri-subexp"
-	flagRegexpMethodSubexp    = "method-subexp"
-	flagRegexpTimeSubexp      = "time-subexp"
-	flagRegexpRestimeSubexp   = "restime-subexp"
-	flagRegexpReqtimeSubexp   = "reqtime-subexp"
-	flagRegexpBodyBytesSubexp = "body-bytes-subexp"
-	flagRegexpStatusSubexp    = "status-subexp"
+	flagRegexpPattern                = "pattern"
+	flagRegexpUriSubexp              = "uri-subexp"
+	flagRegexpMethodSubexp           = "method-subexp"
+	flagRegexpTimeSubexp             = "time-subexp"
+	flagRegexpRestimeSubexp          = "restime-subexp"
+	flagRegexpReqtimeSubexp          = "reqtime-subexp"
+	flagRegexpBodyBytesSubexp        = "body-bytes-subexp"
+	flagRegexpRequestBodyBytesSubexp = "request-body-bytes-subexp"
+	flagRegexpStatusSubexp           = "status-subexp"
 
 	// pcap
 	flagPcapPcapServerIP   = "pcap-server-ip"
@@ -67,6 +89,15 @@ const (
 
 	// count
 	flagCountKeys = "keys"
+
+	// suggest-groups
+	flagSuggestGroupsMinCardinality = "min-cardinality"
+
+	// check
+	flagCheckBaseline = "baseline"
+
+	// merge
+	flagMergeOutput = "output"
 )
 
 type flags struct {
@@ -85,21 +116,29 @@ func (f *flags) defineConfig(cmd *cobra.Command) {
 }
 
 func (f *flags) defineFile(cmd *cobra.Command) {
-	cmd.PersistentFlags().StringP(flagFile, "", "", "The access log file")
+	cmd.PersistentFlags().StringP(flagFile, "", "", "The access log files, separated by commas. Glob patterns and gzip, zstd, bzip2 compressed files are supported")
 }
 
 func (f *flags) defineDump(cmd *cobra.Command) {
-	cmd.PersistentFlags().StringP(flagDump, "", "", "Dump profiled data as YAML")
+	cmd.PersistentFlags().StringP(flagDump, "", "", "Dump profiled data as YAML or binary")
+}
+
+func (f *flags) defineDumpFormat(cmd *cobra.Command) {
+	cmd.PersistentFlags().StringP(flagDumpFormat, "", "", "The format of the dumped data (yaml and binary). The default is binary if the file name ends with .bin, and yaml otherwise")
 }
 
 func (f *flags) defineLoad(cmd *cobra.Command) {
-	cmd.PersistentFlags().StringP(flagLoad, "", "", "Load the profiled YAML data")
+	cmd.PersistentFlags().StringP(flagLoad, "", "", "Load the profiled data separated by commas")
 }
 
 func (f *flags) defineFormat(cmd *cobra.Command) {
 	cmd.PersistentFlags().StringP(flagFormat, "", options.DefaultFormatOption, "The output format (table, markdown, tsv, csv, html, and json)")
 }
 
+func (f *flags) defineProfileFormat(cmd *cobra.Command) {
+	cmd.PersistentFlags().StringP(flagFormat, "", options.DefaultFormatOption, "The output format (table, markdown, tsv, csv, html, json, tui, and sqlite)")
+}
+
 func (f *flags) defineSort(cmd *cobra.Command) {
 	cmd.PersistentFlags().StringP(flagSort, "", options.DefaultSortOption, "Output the results in sorted order")
 }
@@ -164,10 +203,78 @@ func (f *flags) definePercentiles(cmd *cobra.Command) {
 	cmd.PersistentFlags().StringP(flagPercentiles, "", "", "Specifies the percentiles separated by commas")
 }
 
+func (f *flags) defineStatusCodes(cmd *cobra.Command) {
+	cmd.PersistentFlags().StringP(flagStatusCodes, "", "", "Specifies the status codes to count exactly separated by commas")
+}
+
+func (f *flags) defineGroupBy(cmd *cobra.Command) {
+	cmd.PersistentFlags().StringP(flagGroupBy, "", "", "Specifies the log keys to aggregate by in addition to the method and the URI separated by commas")
+}
+
 func (f *flags) definePage(cmd *cobra.Command) {
 	cmd.PersistentFlags().IntP(flagPage, "", options.DefaultPaginationLimit, "Number of pages of pagination")
 }
 
+func (f *flags) definePercentileEstimator(cmd *cobra.Command) {
+	cmd.PersistentFlags().StringP(flagPercentileEstimator, "", options.DefaultPercentileEstimatorOption, "How to estimate the percentiles (exact or sketch)")
+}
+
+func (f *flags) definePercentileAccuracy(cmd *cobra.Command) {
+	cmd.PersistentFlags().Float64P(flagPercentileAccuracy, "", options.DefaultPercentileAccuracyOption, "The relative accuracy of the percentiles (only use with --percentile-estimator=sketch)")
+}
+
+func (f *flags) defineApdexThreshold(cmd *cobra.Command) {
+	cmd.PersistentFlags().Float64P(flagApdexThreshold, "", options.DefaultApdexThresholdOption, "The threshold T of the Apdex score in seconds, and 0 disables the Apdex score")
+}
+
+func (f *flags) defineSignificanceLevel(cmd *cobra.Command) {
+	cmd.PersistentFlags().Float64P(flagSignificanceLevel, "", options.DefaultSignificanceLevelOption, "The significance level of the statistical test of the response times")
+}
+
+func (f *flags) defineWorkers(cmd *cobra.Command) {
+	cmd.PersistentFlags().IntP(flagWorkers, "", options.DefaultWorkersOption, "Number of goroutines that parse and aggregate the log")
+}
+
+func (f *flags) defineTimeline(cmd *cobra.Command) {
+	cmd.PersistentFlags().StringP(flagTimeline, "", "", "Aggregate each URI into buckets of the interval (e.g. 10s, 1m)")
+}
+
+func (f *flags) defineFollow(cmd *cobra.Command) {
+	cmd.PersistentFlags().BoolP(flagFollow, "", false, "Keep reading the appended lines and print the results periodically, until Ctrl-C")
+}
+
+func (f *flags) defineFollowInterval(cmd *cobra.Command) {
+	cmd.PersistentFlags().StringP(flagFollowInterval, "", options.DefaultFollowIntervalOption, "The interval to print the results (only use with --follow)")
+}
+
+func (f *flags) defineMetricsListen(cmd *cobra.Command) {
+	cmd.PersistentFlags().StringP(flagMetricsListen, "", "", "Keep reading the appended lines and expose the results as Prometheus metrics on /metrics of the address (e.g. :9101)")
+}
+
+func (f *flags) defineMetricsBuckets(cmd *cobra.Command) {
+	cmd.PersistentFlags().StringP(flagMetricsBuckets, "", options.DefaultMetricsBucketsOption, "The upper bounds of the response time histogram buckets separated by commas (only use with --metrics-listen)")
+}
+
+func (f *flags) defineOpenAPI(cmd *cobra.Command) {
+	cmd.PersistentFlags().StringP(flagOpenAPI, "", "", "The OpenAPI 3 or Swagger 2 document (YAML or JSON) to aggregate the URIs into the documented path templates")
+}
+
+func (f *flags) defineHistogram(cmd *cobra.Command) {
+	cmd.PersistentFlags().BoolP(flagHistogram, "", false, "Print the histogram of the response times of each URI instead of the profile results (table and json)")
+}
+
+func (f *flags) defineHistogramBuckets(cmd *cobra.Command) {
+	cmd.PersistentFlags().IntP(flagHistogramBuckets, "", options.DefaultHistogramBucketsOption, "The number of the buckets of the histogram (only use with --histogram)")
+}
+
+func (f *flags) defineHistogramScale(cmd *cobra.Command) {
+	cmd.PersistentFlags().StringP(flagHistogramScale, "", options.DefaultHistogramScaleOption, "The scale of the buckets of the histogram (linear or log) (only use with --histogram)")
+}
+
+func (f *flags) defineHistogramUri(cmd *cobra.Command) {
+	cmd.PersistentFlags().StringP(flagHistogramUri, "", "", "Print the histograms only of the URIs that match the regular expression (only use with --histogram)")
+}
+
 func (f *flags) defineJSONUriKey(cmd *cobra.Command) {
 	cmd.PersistentFlags().StringP(flagJSONUriKey, "", options.DefaultUriKeyOption, "Change the uri key")
 }
@@ -192,6 +299,10 @@ func (f *flags) defineJSONBodyBytesKey(cmd *cobra.Command) {
 	cmd.PersistentFlags().StringP(flagJSONBodyBytesKey, "", options.DefaultBodyBytesKeyOption, "Change the body_bytes key")
 }
 
+func (f *flags) defineJSONRequestBodyBytesKey(cmd *cobra.Command) {
+	cmd.PersistentFlags().StringP(flagJSONRequestBodyBytesKey, "", options.DefaultRequestBodyBytesKeyOption, "Change the request_body_bytes key")
+}
+
 func (f *flags) defineJSONStatusKey(cmd *cobra.Command) {
 	cmd.PersistentFlags().StringP(flagJSONStatusKey, "", options.DefaultStatusKeyOption, "Change the status key")
 }
@@ -220,6 +331,10 @@ func (f *flags) defineLTSVSizeLabel(cmd *cobra.Command) {
 	cmd.PersistentFlags().StringP(flagLTSVSizeLabel, "", options.DefaultSizeLabelOption, "Change the size label")
 }
 
+func (f *flags) defineLTSVReqsizeLabel(cmd *cobra.Command) {
+	cmd.PersistentFlags().StringP(flagLTSVReqsizeLabel, "", options.DefaultReqsizeLabelOption, "Change the reqsize label")
+}
+
 func (f *flags) defineLTSVStatusLabel(cmd *cobra.Command) {
 	cmd.PersistentFlags().StringP(flagLTSVStatusLabel, "", options.DefaultStatusLabelOption, "Change the status label")
 }
@@ -252,6 +367,10 @@ func (f *flags) defineRegexpBodyBytesSubexp(cmd *cobra.Command) {
 	cmd.PersistentFlags().StringP(flagRegexpBodyBytesSubexp, "", options.DefaultBodyBytesSubexpOption, "Change the body_bytes sub expression")
 }
 
+func (f *flags) defineRegexpRequestBodyBytesSubexp(cmd *cobra.Command) {
+	cmd.PersistentFlags().StringP(flagRegexpRequestBodyBytesSubexp, "", options.DefaultRequestBodyBytesSubexpOption, "Change the request_body_bytes sub expression")
+}
+
 func (f *flags) defineRegexpStatusSubexp(cmd *cobra.Command) {
 	cmd.PersistentFlags().StringP(flagRegexpStatusSubexp, "", options.DefaultStatusSubexpOption, "Change the status sub expression")
 }
@@ -269,6 +388,22 @@ func (f *flags) defineCountKeys(cmd *cobra.Command) {
 	cmd.MarkPersistentFlagRequired(flagCountKeys)
 }
 
+func (f *flags) defineCheckFormat(cmd *cobra.Command) {
+	cmd.PersistentFlags().StringP(flagFormat, "", options.DefaultFormatOption, "The output format (table, json, and junit)")
+}
+
+func (f *flags) defineCheckBaseline(cmd *cobra.Command) {
+	cmd.PersistentFlags().StringP(flagCheckBaseline, "", "", "The profiled YAML data to compare with")
+}
+
+func (f *flags) defineMergeOutput(cmd *cobra.Command) {
+	cmd.PersistentFlags().StringP(flagMergeOutput, "o", "", "The file to write the merged data (default: stdout)")
+}
+
+func (f *flags) defineSuggestGroupsMinCardinality(cmd *cobra.Command) {
+	cmd.PersistentFlags().IntP(flagSuggestGroupsMinCardinality, "", options.DefaultSuggestGroupsMinCardinalityOption, "The number of distinct path segments at the same position to treat them as a variable")
+}
+
 func (f *flags) defineGlobalOptions(cmd *cobra.Command) {
 	f.defineConfig(cmd)
 }
@@ -276,8 +411,9 @@ func (f *flags) defineGlobalOptions(cmd *cobra.Command) {
 func (f *flags) defineProfileOptions(cmd *cobra.Command) {
 	f.defineFile(cmd)
 	f.defineDump(cmd)
+	f.defineDumpFormat(cmd)
 	f.defineLoad(cmd)
-	f.defineFormat(cmd)
+	f.defineProfileFormat(cmd)
 	f.defineSort(cmd)
 	f.defineReverse(cmd)
 	f.defineNoHeaders(cmd)
@@ -293,7 +429,23 @@ func (f *flags) defineProfileOptions(cmd *cobra.Command) {
 	f.definePositionFile(cmd)
 	f.defineNoSavePositionFile(cmd)
 	f.definePercentiles(cmd)
+	f.defineStatusCodes(cmd)
+	f.defineGroupBy(cmd)
 	f.definePage(cmd)
+	f.definePercentileEstimator(cmd)
+	f.definePercentileAccuracy(cmd)
+	f.defineApdexThreshold(cmd)
+	f.defineWorkers(cmd)
+	f.defineTimeline(cmd)
+	f.defineFollow(cmd)
+	f.defineFollowInterval(cmd)
+	f.defineMetricsListen(cmd)
+	f.defineMetricsBuckets(cmd)
+	f.defineOpenAPI(cmd)
+	f.defineHistogram(cmd)
+	f.defineHistogramBuckets(cmd)
+	f.defineHistogramScale(cmd)
+	f.defineHistogramUri(cmd)
 }
 
 func (f *flags) defineJSONOptions(cmd *cobra.Command) {
@@ -303,6 +455,7 @@ func (f *flags) defineJSONOptions(cmd *cobra.Command) {
 	f.defineJSONRestimeKey(cmd)
 	f.defineJSONReqtimeKey(cmd)
 	f.defineJSONBodyBytesKey(cmd)
+	f.defineJSONRequestBodyBytesKey(cmd)
 	f.defineJSONStatusKey(cmd)
 }
 
@@ -313,6 +466,7 @@ func (f *flags) defineLTSVOptions(cmd *cobra.Command) {
 	f.defineLTSVApptimeLabel(cmd)
 	f.defineLTSVReqtimeLabel(cmd)
 	f.defineLTSVSizeLabel(cmd)
+	f.defineLTSVReqsizeLabel(cmd)
 	f.defineLTSVStatusLabel(cmd)
 }
 
@@ -324,6 +478,7 @@ func (f *flags) defineRegexpOptions(cmd *cobra.Command) {
 	f.defineRegexpRestimeSubexp(cmd)
 	f.defineRegexpReqtimeSubexp(cmd)
 	f.defineRegexpBodyBytesSubexp(cmd)
+	f.defineRegexpRequestBodyBytesSubexp(cmd)
 	f.defineRegexpStatusSubexp(cmd)
 }
 
@@ -347,7 +502,10 @@ func (f *flags) defineDiffOptions(cmd *cobra.Command) {
 	f.defineMatchingGroups(cmd)
 	f.defineFilters(cmd)
 	f.definePercentiles(cmd)
+	f.defineStatusCodes(cmd)
+	f.defineGroupBy(cmd)
 	f.definePage(cmd)
+	f.defineSignificanceLevel(cmd)
 }
 
 func (f *flags) defineDiffSubCommandOptions(cmd *cobra.Command) {
@@ -356,6 +514,7 @@ func (f *flags) defineDiffSubCommandOptions(cmd *cobra.Command) {
 	cmd.LocalFlags().MarkHidden(flagFile)
 
 	f.defineDump(cmd)
+	f.defineDumpFormat(cmd)
 	f.defineLoad(cmd)
 	f.defineFormat(cmd)
 	f.defineSort(cmd)
@@ -373,13 +532,23 @@ func (f *flags) defineDiffSubCommandOptions(cmd *cobra.Command) {
 	f.definePositionFile(cmd)
 	f.defineNoSavePositionFile(cmd)
 	f.definePercentiles(cmd)
+	f.defineStatusCodes(cmd)
+	f.defineGroupBy(cmd)
 	f.definePage(cmd)
+	f.definePercentileEstimator(cmd)
+	f.definePercentileAccuracy(cmd)
+	f.defineApdexThreshold(cmd)
+	f.defineSignificanceLevel(cmd)
+	f.defineWorkers(cmd)
+	f.defineOpenAPI(cmd)
 }
 
 func (f *flags) defineTopNSubCommandOptions(cmd *cobra.Command) {
 	// overwrite and hidden => remove flag
 	cmd.LocalFlags().String(flagDump, "", "")
 	cmd.LocalFlags().MarkHidden(flagDump)
+	cmd.LocalFlags().String(flagDumpFormat, "", "")
+	cmd.LocalFlags().MarkHidden(flagDumpFormat)
 	cmd.LocalFlags().String(flagLoad, "", "")
 	cmd.LocalFlags().MarkHidden(flagLoad)
 	cmd.LocalFlags().String(flagShowFooters, "", "")
@@ -396,6 +565,10 @@ func (f *flags) defineTopNSubCommandOptions(cmd *cobra.Command) {
 	cmd.LocalFlags().MarkHidden(flagMatchingGroups)
 	cmd.LocalFlags().String(flagPercentiles, "", "")
 	cmd.LocalFlags().MarkHidden(flagPercentiles)
+	cmd.LocalFlags().String(flagStatusCodes, "", "")
+	cmd.LocalFlags().MarkHidden(flagStatusCodes)
+	cmd.LocalFlags().String(flagGroupBy, "", "")
+	cmd.LocalFlags().MarkHidden(flagGroupBy)
 
 	f.defineFile(cmd)
 	f.defineFormat(cmd)
@@ -414,6 +587,8 @@ func (f *flags) defineCountSubCommandOptions(cmd *cobra.Command) {
 	// overwrite and hidden => remove flag
 	cmd.LocalFlags().String(flagDump, "", "")
 	cmd.LocalFlags().MarkHidden(flagDump)
+	cmd.LocalFlags().String(flagDumpFormat, "", "")
+	cmd.LocalFlags().MarkHidden(flagDumpFormat)
 	cmd.LocalFlags().String(flagLoad, "", "")
 	cmd.LocalFlags().MarkHidden(flagLoad)
 	cmd.LocalFlags().String(flagSort, "", "")
@@ -442,6 +617,10 @@ func (f *flags) defineCountSubCommandOptions(cmd *cobra.Command) {
 	cmd.LocalFlags().MarkHidden(flagNoSavePositionFile)
 	cmd.LocalFlags().String(flagPercentiles, "", "")
 	cmd.LocalFlags().MarkHidden(flagPercentiles)
+	cmd.LocalFlags().String(flagStatusCodes, "", "")
+	cmd.LocalFlags().MarkHidden(flagStatusCodes)
+	cmd.LocalFlags().String(flagGroupBy, "", "")
+	cmd.LocalFlags().MarkHidden(flagGroupBy)
 
 	f.defineFile(cmd)
 	f.defineReverse(cmd)
@@ -451,9 +630,129 @@ func (f *flags) defineCountSubCommandOptions(cmd *cobra.Command) {
 	f.defineCountKeys(cmd)
 }
 
+func (f *flags) defineCheckOptions(cmd *cobra.Command) {
+	f.defineCheckFormat(cmd)
+	f.defineCheckBaseline(cmd)
+}
+
+func (f *flags) defineCheckSubCommandOptions(cmd *cobra.Command) {
+	// overwrite and hidden => remove flag
+	cmd.LocalFlags().String(flagDump, "", "")
+	cmd.LocalFlags().MarkHidden(flagDump)
+	cmd.LocalFlags().String(flagDumpFormat, "", "")
+	cmd.LocalFlags().MarkHidden(flagDumpFormat)
+	cmd.LocalFlags().String(flagLoad, "", "")
+	cmd.LocalFlags().MarkHidden(flagLoad)
+	cmd.LocalFlags().String(flagSort, "", "")
+	cmd.LocalFlags().MarkHidden(flagSort)
+	cmd.LocalFlags().String(flagReverse, "", "")
+	cmd.LocalFlags().MarkHidden(flagReverse)
+	cmd.LocalFlags().String(flagNoHeaders, "", "")
+	cmd.LocalFlags().MarkHidden(flagNoHeaders)
+	cmd.LocalFlags().String(flagShowFooters, "", "")
+	cmd.LocalFlags().MarkHidden(flagShowFooters)
+	cmd.LocalFlags().String(flagLimit, "", "")
+	cmd.LocalFlags().MarkHidden(flagLimit)
+	cmd.LocalFlags().String(flagOutput, "", "")
+	cmd.LocalFlags().MarkHidden(flagOutput)
+	cmd.LocalFlags().String(flagPage, "", "")
+	cmd.LocalFlags().MarkHidden(flagPage)
+	cmd.LocalFlags().String(flagTimeline, "", "")
+	cmd.LocalFlags().MarkHidden(flagTimeline)
+	cmd.LocalFlags().String(flagFollow, "", "")
+	cmd.LocalFlags().MarkHidden(flagFollow)
+	cmd.LocalFlags().String(flagFollowInterval, "", "")
+	cmd.LocalFlags().MarkHidden(flagFollowInterval)
+	cmd.LocalFlags().String(flagMetricsListen, "", "")
+	cmd.LocalFlags().MarkHidden(flagMetricsListen)
+	cmd.LocalFlags().String(flagMetricsBuckets, "", "")
+	cmd.LocalFlags().MarkHidden(flagMetricsBuckets)
+	cmd.LocalFlags().String(flagHistogram, "", "")
+	cmd.LocalFlags().MarkHidden(flagHistogram)
+	cmd.LocalFlags().String(flagHistogramBuckets, "", "")
+	cmd.LocalFlags().MarkHidden(flagHistogramBuckets)
+	cmd.LocalFlags().String(flagHistogramScale, "", "")
+	cmd.LocalFlags().MarkHidden(flagHistogramScale)
+	cmd.LocalFlags().String(flagHistogramUri, "", "")
+	cmd.LocalFlags().MarkHidden(flagHistogramUri)
+
+	f.defineFile(cmd)
+	f.defineCheckFormat(cmd)
+	f.defineCheckBaseline(cmd)
+	f.defineQueryString(cmd)
+	f.defineQueryStringIgnoreValues(cmd)
+	f.defineLocation(cmd)
+	f.defineDecodeUri(cmd)
+	f.defineMatchingGroups(cmd)
+	f.defineFilters(cmd)
+	f.definePositionFile(cmd)
+	f.defineNoSavePositionFile(cmd)
+	f.definePercentiles(cmd)
+	f.defineStatusCodes(cmd)
+	f.defineGroupBy(cmd)
+	f.definePercentileEstimator(cmd)
+	f.definePercentileAccuracy(cmd)
+	f.defineApdexThreshold(cmd)
+	f.defineWorkers(cmd)
+	f.defineOpenAPI(cmd)
+}
+
+func (f *flags) defineMergeOptions(cmd *cobra.Command) {
+	f.defineMergeOutput(cmd)
+	f.defineDumpFormat(cmd)
+}
+
+func (f *flags) defineSuggestGroupsSubCommandOptions(cmd *cobra.Command) {
+	// overwrite and hidden => remove flag
+	cmd.LocalFlags().String(flagDump, "", "")
+	cmd.LocalFlags().MarkHidden(flagDump)
+	cmd.LocalFlags().String(flagDumpFormat, "", "")
+	cmd.LocalFlags().MarkHidden(flagDumpFormat)
+	cmd.LocalFlags().String(flagLoad, "", "")
+	cmd.LocalFlags().MarkHidden(flagLoad)
+	cmd.LocalFlags().String(flagFormat, "", "")
+	cmd.LocalFlags().MarkHidden(flagFormat)
+	cmd.LocalFlags().String(flagSort, "", "")
+	cmd.LocalFlags().MarkHidden(flagSort)
+	cmd.LocalFlags().String(flagReverse, "", "")
+	cmd.LocalFlags().MarkHidden(flagReverse)
+	cmd.LocalFlags().String(flagNoHeaders, "", "")
+	cmd.LocalFlags().MarkHidden(flagNoHeaders)
+	cmd.LocalFlags().String(flagShowFooters, "", "")
+	cmd.LocalFlags().MarkHidden(flagShowFooters)
+	cmd.LocalFlags().String(flagLimit, "", "")
+	cmd.LocalFlags().MarkHidden(flagLimit)
+	cmd.LocalFlags().String(flagOutput, "", "")
+	cmd.LocalFlags().MarkHidden(flagOutput)
+	cmd.LocalFlags().String(flagQueryString, "", "")
+	cmd.LocalFlags().MarkHidden(flagQueryString)
+	cmd.LocalFlags().String(flagQueryStringIgnoreValues, "", "")
+	cmd.LocalFlags().MarkHidden(flagQueryStringIgnoreValues)
+	cmd.LocalFlags().String(flagDecodeUri, "", "")
+	cmd.LocalFlags().MarkHidden(flagDecodeUri)
+	cmd.LocalFlags().String(flagMatchingGroups, "", "")
+	cmd.LocalFlags().MarkHidden(flagMatchingGroups)
+	cmd.LocalFlags().String(flagPositionFile, "", "")
+	cmd.LocalFlags().MarkHidden(flagPositionFile)
+	cmd.LocalFlags().String(flagNoSavePositionFile, "", "")
+	cmd.LocalFlags().MarkHidden(flagNoSavePositionFile)
+	cmd.LocalFlags().String(flagPercentiles, "", "")
+	cmd.LocalFlags().MarkHidden(flagPercentiles)
+	cmd.LocalFlags().String(flagStatusCodes, "", "")
+	cmd.LocalFlags().MarkHidden(flagStatusCodes)
+	cmd.LocalFlags().String(flagGroupBy, "", "")
+	cmd.LocalFlags().MarkHidden(flagGroupBy)
+
+	f.defineFile(cmd)
+	f.defineLocation(cmd)
+	f.defineFilters(cmd)
+	f.defineSuggestGroupsMinCardinality(cmd)
+}
+
 func (f *flags) bindFlags(cmd *cobra.Command) {
 	viper.BindPFlag("file", cmd.PersistentFlags().Lookup(flagFile))
 	viper.BindPFlag("dump", cmd.PersistentFlags().Lookup(flagDump))
+	viper.BindPFlag("dump_format", cmd.PersistentFlags().Lookup(flagDumpFormat))
 	viper.BindPFlag("load", cmd.PersistentFlags().Lookup(flagLoad))
 
 	if !strings.Contains(cmd.Name(), "topN") {
@@ -475,6 +774,22 @@ func (f *flags) bindFlags(cmd *cobra.Command) {
 	viper.BindPFlag("location", cmd.PersistentFlags().Lookup(flagLocation))
 	viper.BindPFlag("output", cmd.PersistentFlags().Lookup(flagOutput))
 	viper.BindPFlag("pagenation_limit", cmd.PersistentFlags().Lookup(flagPage))
+	viper.BindPFlag("percentile_estimator", cmd.PersistentFlags().Lookup(flagPercentileEstimator))
+	viper.BindPFlag("percentile_accuracy", cmd.PersistentFlags().Lookup(flagPercentileAccuracy))
+	viper.BindPFlag("group_by", cmd.PersistentFlags().Lookup(flagGroupBy))
+	viper.BindPFlag("apdex_threshold", cmd.PersistentFlags().Lookup(flagApdexThreshold))
+	viper.BindPFlag("significance_level", cmd.PersistentFlags().Lookup(flagSignificanceLevel))
+	viper.BindPFlag("workers", cmd.PersistentFlags().Lookup(flagWorkers))
+	viper.BindPFlag("timeline", cmd.PersistentFlags().Lookup(flagTimeline))
+	viper.BindPFlag("follow", cmd.PersistentFlags().Lookup(flagFollow))
+	viper.BindPFlag("follow_interval", cmd.PersistentFlags().Lookup(flagFollowInterval))
+	viper.BindPFlag("metrics_listen", cmd.PersistentFlags().Lookup(flagMetricsListen))
+	viper.BindPFlag("metrics_buckets", cmd.PersistentFlags().Lookup(flagMetricsBuckets))
+	viper.BindPFlag("openapi", cmd.PersistentFlags().Lookup(flagOpenAPI))
+	viper.BindPFlag("histogram", cmd.PersistentFlags().Lookup(flagHistogram))
+	viper.BindPFlag("histogram_buckets", cmd.PersistentFlags().Lookup(flagHistogramBuckets))
+	viper.BindPFlag("histogram_scale", cmd.PersistentFlags().Lookup(flagHistogramScale))
+	viper.BindPFlag("histogram_uri", cmd.PersistentFlags().Lookup(flagHistogramUri))
 
 	// json
 	viper.BindPFlag("json.uri_key", cmd.PersistentFlags().Lookup(flagJSONUriKey))
@@ -483,6 +798,7 @@ func (f *flags) bindFlags(cmd *cobra.Command) {
 	viper.BindPFlag("json.restime_key", cmd.PersistentFlags().Lookup(flagJSONRestimeKey))
 	viper.BindPFlag("json.reqtime_key", cmd.PersistentFlags().Lookup(flagJSONReqtimeKey))
 	viper.BindPFlag("json.body_bytes_key", cmd.PersistentFlags().Lookup(flagJSONBodyBytesKey))
+	viper.BindPFlag("json.request_body_bytes_key", cmd.PersistentFlags().Lookup(flagJSONRequestBodyBytesKey))
 	viper.BindPFlag("json.status_key", cmd.PersistentFlags().Lookup(flagJSONStatusKey))
 
 	// ltsv
@@ -492,6 +808,7 @@ func (f *flags) bindFlags(cmd *cobra.Command) {
 	viper.BindPFlag("ltsv.apptime_label", cmd.PersistentFlags().Lookup(flagLTSVApptimeLabel))
 	viper.BindPFlag("ltsv.reqtime_label", cmd.PersistentFlags().Lookup(flagLTSVReqtimeLabel))
 	viper.BindPFlag("ltsv.size_label", cmd.PersistentFlags().Lookup(flagLTSVSizeLabel))
+	viper.BindPFlag("ltsv.reqsize_label", cmd.PersistentFlags().Lookup(flagLTSVReqsizeLabel))
 	viper.BindPFlag("ltsv.status_label", cmd.PersistentFlags().Lookup(flagLTSVStatusLabel))
 
 	// regexp
@@ -502,6 +819,7 @@ func (f *flags) bindFlags(cmd *cobra.Command) {
 	viper.BindPFlag("regexp.restime_subexp", cmd.PersistentFlags().Lookup(flagRegexpRestimeSubexp))
 	viper.BindPFlag("regexp.reqtime_subexp", cmd.PersistentFlags().Lookup(flagRegexpReqtimeSubexp))
 	viper.BindPFlag("regexp.body_bytes_subexp", cmd.PersistentFlags().Lookup(flagRegexpBodyBytesSubexp))
+	viper.BindPFlag("regexp.request_body_bytes_subexp", cmd.PersistentFlags().Lookup(flagRegexpRequestBodyBytesSubexp))
 	viper.BindPFlag("regexp.status_subexp", cmd.PersistentFlags().Lookup(flagRegexpStatusSubexp))
 
 	// pcap
@@ -510,6 +828,12 @@ func (f *flags) bindFlags(cmd *cobra.Command) {
 	// count
 	viper.BindPFlag("count.keys", cmd.PersistentFlags().Lookup(flagCountKeys))
 
+	// suggest-groups
+	viper.BindPFlag("suggest_groups.min_cardinality", cmd.PersistentFlags().Lookup(flagSuggestGroupsMinCardinality))
+
+	// check
+	viper.BindPFlag("check.baseline", cmd.PersistentFlags().Lookup(flagCheckBaseline))
+
 	// topN
 	if strings.Contains(cmd.Name(), "topN") {
 		viper.BindPFlag("topN.sort", cmd.PersistentFlags().Lookup(flagSort))
@@ -531,7 +855,13 @@ func (f *flags) createOptionsFromConfig(cmd *cobra.Command) (*options.Options, e
 		return nil, err
 	}
 
-	if err := viper.Unmarshal(opts); err != nil {
+	// matching_groups accepts both the patterns and the named matching groups
+	decodeHook := viper.DecodeHook(mapstructure.ComposeDecodeHookFunc(
+		mapstructure.StringToTimeDurationHookFunc(),
+		mapstructure.StringToSliceHookFunc(","),
+		options.StringToMatchingGroupHookFunc(),
+	))
+	if err := viper.Unmarshal(opts, decodeHook); err != nil {
 		return nil, err
 	}
 
@@ -562,12 +892,26 @@ func (f *flags) createOptionsFromConfig(cmd *cobra.Command) (*options.Options, e
 		opts.Percentiles = percentiles
 	}
 
+	statusCodesFlag := cmd.PersistentFlags().Lookup(flagStatusCodes)
+	if statusCodesFlag != nil && statusCodesFlag.Changed {
+		statusCodes, err := helpers.SplitCSVIntoInts(statusCodesFlag.Value.String())
+		if err != nil {
+			return nil, err
+		}
+		opts.StatusCodes = statusCodes
+	}
+
+	if err := helpers.ValidateStatusCodes(opts.StatusCodes); err != nil {
+		return nil, err
+	}
+
 	srvIPFlag := cmd.PersistentFlags().Lookup(flagPcapPcapServerIP)
 	if srvIPFlag != nil && srvIPFlag.Changed {
 		ips := cmd.PersistentFlags().Lookup(flagPcapPcapServerIP).Value.String()
 		opts.Pcap.ServerIPs = helpers.SplitCSV(ips)
 	}
 
+	f.sortOptions.SetGroupBy(opts.GroupBy)
 	if err := f.sortOptions.SetAndValidate(opts.Sort); err != nil {
 		return nil, err
 	}
@@ -576,6 +920,20 @@ func (f *flags) createOptionsFromConfig(cmd *cobra.Command) (*options.Options, e
 	return opts, nil
 }
 
+// groupBy returns the keys of --group-by, and returns nil if the subcommand has no --group-by
+func (f *flags) groupBy(cmd *cobra.Command) ([]string, error) {
+	if cmd.PersistentFlags().Lookup(flagGroupBy) == nil {
+		return nil, nil
+	}
+
+	groupBy, err := cmd.PersistentFlags().GetString(flagGroupBy)
+	if err != nil {
+		return nil, err
+	}
+
+	return helpers.SplitCSV(groupBy), nil
+}
+
 func (f *flags) setOptions(cmd *cobra.Command, opts *options.Options, flags []string) (*options.Options, error) {
 	for _, flag := range flags {
 		switch flag {
@@ -591,6 +949,12 @@ func (f *flags) setOptions(cmd *cobra.Command, opts *options.Options, flags []st
 				return nil, err
 			}
 			opts = options.SetOptions(opts, options.Dump(dump))
+		case flagDumpFormat:
+			dumpFormat, err := cmd.PersistentFlags().GetString(flagDumpFormat)
+			if err != nil {
+				return nil, err
+			}
+			opts = options.SetOptions(opts, options.DumpFormat(dumpFormat))
 		case flagLoad:
 			load, err := cmd.PersistentFlags().GetString(flagLoad)
 			if err != nil {
@@ -609,6 +973,13 @@ func (f *flags) setOptions(cmd *cobra.Command, opts *options.Options, flags []st
 				return nil, err
 			}
 
+			// the keys of group-by are also the sort keys
+			groupBy, err := f.groupBy(cmd)
+			if err != nil {
+				return nil, err
+			}
+			f.sortOptions.SetGroupBy(groupBy)
+
 			err = f.sortOptions.SetAndValidate(sort)
 			if err != nil {
 				return nil, err
@@ -711,12 +1082,123 @@ func (f *flags) setOptions(cmd *cobra.Command, opts *options.Options, flags []st
 				}
 			}
 			opts = options.SetOptions(opts, options.Percentiles(percentiles))
+		case flagGroupBy:
+			groupBy, err := f.groupBy(cmd)
+			if err != nil {
+				return nil, err
+			}
+			opts = options.SetOptions(opts, options.GroupBy(groupBy))
+		case flagStatusCodes:
+			scs, err := cmd.PersistentFlags().GetString(flagStatusCodes)
+			if err != nil {
+				return nil, err
+			}
+
+			statusCodes, err := helpers.SplitCSVIntoInts(scs)
+			if err != nil {
+				return nil, err
+			}
+
+			if err = helpers.ValidateStatusCodes(statusCodes); err != nil {
+				return nil, err
+			}
+			opts = options.SetOptions(opts, options.StatusCodes(statusCodes))
 		case flagPage:
 			paginationLimit, err := cmd.PersistentFlags().GetInt(flagPage)
 			if err != nil {
 				return nil, err
 			}
 			opts = options.SetOptions(opts, options.PaginationLimit(paginationLimit))
+		case flagPercentileEstimator:
+			estimator, err := cmd.PersistentFlags().GetString(flagPercentileEstimator)
+			if err != nil {
+				return nil, err
+			}
+			opts = options.SetOptions(opts, options.PercentileEstimator(estimator))
+		case flagPercentileAccuracy:
+			accuracy, err := cmd.PersistentFlags().GetFloat64(flagPercentileAccuracy)
+			if err != nil {
+				return nil, err
+			}
+			opts = options.SetOptions(opts, options.PercentileAccuracy(accuracy))
+		case flagApdexThreshold:
+			threshold, err := cmd.PersistentFlags().GetFloat64(flagApdexThreshold)
+			if err != nil {
+				return nil, err
+			}
+			opts = options.SetOptions(opts, options.ApdexThreshold(threshold))
+		case flagSignificanceLevel:
+			level, err := cmd.PersistentFlags().GetFloat64(flagSignificanceLevel)
+			if err != nil {
+				return nil, err
+			}
+			opts = options.SetOptions(opts, options.SignificanceLevel(level))
+		case flagWorkers:
+			workers, err := cmd.PersistentFlags().GetInt(flagWorkers)
+			if err != nil {
+				return nil, err
+			}
+			opts = options.SetOptions(opts, options.Workers(workers))
+		case flagTimeline:
+			timeline, err := cmd.PersistentFlags().GetString(flagTimeline)
+			if err != nil {
+				return nil, err
+			}
+			opts = options.SetOptions(opts, options.Timeline(timeline))
+		case flagFollow:
+			follow, err := cmd.PersistentFlags().GetBool(flagFollow)
+			if err != nil {
+				return nil, err
+			}
+			opts = options.SetOptions(opts, options.Follow(follow))
+		case flagFollowInterval:
+			interval, err := cmd.PersistentFlags().GetString(flagFollowInterval)
+			if err != nil {
+				return nil, err
+			}
+			opts = options.SetOptions(opts, options.FollowInterval(interval))
+		case flagMetricsListen:
+			listen, err := cmd.PersistentFlags().GetString(flagMetricsListen)
+			if err != nil {
+				return nil, err
+			}
+			opts = options.SetOptions(opts, options.MetricsListen(listen))
+		case flagMetricsBuckets:
+			buckets, err := cmd.PersistentFlags().GetString(flagMetricsBuckets)
+			if err != nil {
+				return nil, err
+			}
+			opts = options.SetOptions(opts, options.MetricsBuckets(buckets))
+		case flagOpenAPI:
+			openAPI, err := cmd.PersistentFlags().GetString(flagOpenAPI)
+			if err != nil {
+				return nil, err
+			}
+			opts = options.SetOptions(opts, options.OpenAPI(openAPI))
+		case flagHistogram:
+			histogram, err := cmd.PersistentFlags().GetBool(flagHistogram)
+			if err != nil {
+				return nil, err
+			}
+			opts = options.SetOptions(opts, options.Histogram(histogram))
+		case flagHistogramBuckets:
+			buckets, err := cmd.PersistentFlags().GetInt(flagHistogramBuckets)
+			if err != nil {
+				return nil, err
+			}
+			opts = options.SetOptions(opts, options.HistogramBuckets(buckets))
+		case flagHistogramScale:
+			scale, err := cmd.PersistentFlags().GetString(flagHistogramScale)
+			if err != nil {
+				return nil, err
+			}
+			opts = options.SetOptions(opts, options.HistogramScale(scale))
+		case flagHistogramUri:
+			uri, err := cmd.PersistentFlags().GetString(flagHistogramUri)
+			if err != nil {
+				return nil, err
+			}
+			opts = options.SetOptions(opts, options.HistogramUri(uri))
 		}
 	}
 
@@ -727,6 +1209,7 @@ func (f *flags) setProfileOptions(cmd *cobra.Command, opts *options.Options) (*o
 	_flags := []string{
 		flagFile,
 		flagDump,
+		flagDumpFormat,
 		flagLoad,
 		flagFormat,
 		flagSort,
@@ -744,7 +1227,23 @@ func (f *flags) setProfileOptions(cmd *cobra.Command, opts *options.Options) (*o
 		flagPositionFile,
 		flagNoSavePositionFile,
 		flagPercentiles,
+		flagStatusCodes,
+		flagGroupBy,
 		flagPage,
+		flagPercentileEstimator,
+		flagPercentileAccuracy,
+		flagApdexThreshold,
+		flagWorkers,
+		flagTimeline,
+		flagFollow,
+		flagFollowInterval,
+		flagMetricsListen,
+		flagMetricsBuckets,
+		flagOpenAPI,
+		flagHistogram,
+		flagHistogramBuckets,
+		flagHistogramScale,
+		flagHistogramUri,
 	}
 
 	return f.setOptions(cmd, opts, _flags)
@@ -781,6 +1280,11 @@ func (f *flags) setJSONOptions(cmd *cobra.Command, opts *options.Options) (*opti
 		return nil, err
 	}
 
+	requestBodyBytesKey, err := cmd.PersistentFlags().GetString(flagJSONRequestBodyBytesKey)
+	if err != nil {
+		return nil, err
+	}
+
 	statusKey, err := cmd.PersistentFlags().GetString(flagJSONStatusKey)
 	if err != nil {
 		return nil, err
@@ -793,6 +1297,7 @@ func (f *flags) setJSONOptions(cmd *cobra.Command, opts *options.Options) (*opti
 		options.ResponseTimeKey(responseTimeKey),
 		options.RequestTimeKey(requestTimeKey),
 		options.BodyBytesKey(bodyBytesKey),
+		options.RequestBodyBytesKey(requestBodyBytesKey),
 		options.StatusKey(statusKey),
 	), nil
 }
@@ -828,6 +1333,11 @@ func (f *flags) setLTSVOptions(cmd *cobra.Command, opts *options.Options) (*opti
 		return nil, err
 	}
 
+	reqsizeLabel, err := cmd.PersistentFlags().GetString(flagLTSVReqsizeLabel)
+	if err != nil {
+		return nil, err
+	}
+
 	statusLabel, err := cmd.PersistentFlags().GetString(flagLTSVStatusLabel)
 	if err != nil {
 		return nil, err
@@ -840,6 +1350,7 @@ func (f *flags) setLTSVOptions(cmd *cobra.Command, opts *options.Options) (*opti
 		options.ApptimeLabel(appTimeLabel),
 		options.ReqtimeLabel(reqTimeLabel),
 		options.SizeLabel(sizeLabel),
+		options.ReqsizeLabel(reqsizeLabel),
 		options.StatusLabel(statusLabel),
 	), nil
 }
@@ -879,6 +1390,11 @@ func (f *flags) setRegexpOptions(cmd *cobra.Command, opts *options.Options) (*op
 		return nil, err
 	}
 
+	requestBodyBytesSubexp, err := cmd.PersistentFlags().GetString(flagRegexpRequestBodyBytesSubexp)
+	if err != nil {
+		return nil, err
+	}
+
 	statusSubexp, err := cmd.PersistentFlags().GetString(flagRegexpStatusSubexp)
 	if err != nil {
 		return nil, err
@@ -892,6 +1408,7 @@ func (f *flags) setRegexpOptions(cmd *cobra.Command, opts *options.Options) (*op
 		options.ResponseTimeSubexp(restimeSubexp),
 		options.RequestTimeSubexp(reqtimeSubexp),
 		options.BodyBytesSubexp(bodyBytesSubexp),
+		options.RequestBodyBytesSubexp(requestBodyBytesSubexp),
 		options.StatusSubexp(statusSubexp),
 	), nil
 }
@@ -913,6 +1430,75 @@ func (f *flags) setPcapOptions(cmd *cobra.Command, opts *options.Options) (*opti
 	), nil
 }
 
+func (f *flags) setCheckOptions(cmd *cobra.Command, opts *options.Options) (*options.Options, error) {
+	baseline, err := cmd.PersistentFlags().GetString(flagCheckBaseline)
+	if err != nil {
+		return nil, err
+	}
+
+	opts = options.SetOptions(opts,
+		options.CheckBaseline(baseline),
+	)
+
+	_flags := []string{
+		flagFormat,
+	}
+
+	return f.setOptions(cmd, opts, _flags)
+}
+
+func (f *flags) setCheckSubCommandOptions(cmd *cobra.Command, opts *options.Options) (*options.Options, error) {
+	baseline, err := cmd.PersistentFlags().GetString(flagCheckBaseline)
+	if err != nil {
+		return nil, err
+	}
+
+	opts = options.SetOptions(opts,
+		options.CheckBaseline(baseline),
+	)
+
+	_flags := []string{
+		flagFile,
+		flagFormat,
+		flagQueryString,
+		flagQueryStringIgnoreValues,
+		flagLocation,
+		flagDecodeUri,
+		flagMatchingGroups,
+		flagFilters,
+		flagPositionFile,
+		flagNoSavePositionFile,
+		flagPercentiles,
+		flagStatusCodes,
+		flagGroupBy,
+		flagPercentileEstimator,
+		flagPercentileAccuracy,
+		flagApdexThreshold,
+		flagWorkers,
+		flagOpenAPI,
+	}
+
+	return f.setOptions(cmd, opts, _flags)
+}
+
+// setMergeOptions sets the output of merge as the file to dump
+func (f *flags) setMergeOptions(cmd *cobra.Command, opts *options.Options) (*options.Options, error) {
+	output, err := cmd.PersistentFlags().GetString(flagMergeOutput)
+	if err != nil {
+		return nil, err
+	}
+
+	dumpFormat, err := cmd.PersistentFlags().GetString(flagDumpFormat)
+	if err != nil {
+		return nil, err
+	}
+
+	return options.SetOptions(opts,
+		options.Dump(output),
+		options.DumpFormat(dumpFormat),
+	), nil
+}
+
 func (f *flags) setDiffOptions(cmd *cobra.Command, opts *options.Options) (*options.Options, error) {
 	_flags := []string{
 		flagFormat,
@@ -929,7 +1515,10 @@ func (f *flags) setDiffOptions(cmd *cobra.Command, opts *options.Options) (*opti
 		flagMatchingGroups,
 		flagFilters,
 		flagPercentiles,
+		flagStatusCodes,
+		flagGroupBy,
 		flagPage,
+		flagSignificanceLevel,
 	}
 
 	return f.setOptions(cmd, opts, _flags)
@@ -938,6 +1527,7 @@ func (f *flags) setDiffOptions(cmd *cobra.Command, opts *options.Options) (*opti
 func (f *flags) setDiffSubCommandOptions(cmd *cobra.Command, opts *options.Options) (*options.Options, error) {
 	_flags := []string{
 		flagDump,
+		flagDumpFormat,
 		flagLoad,
 		flagFormat,
 		flagSort,
@@ -955,7 +1545,15 @@ func (f *flags) setDiffSubCommandOptions(cmd *cobra.Command, opts *options.Optio
 		flagPositionFile,
 		flagNoSavePositionFile,
 		flagPercentiles,
+		flagStatusCodes,
+		flagGroupBy,
 		flagPage,
+		flagPercentileEstimator,
+		flagPercentileAccuracy,
+		flagApdexThreshold,
+		flagSignificanceLevel,
+		flagWorkers,
+		flagOpenAPI,
 	}
 
 	return f.setOptions(cmd, opts, _flags)
@@ -1011,6 +1609,25 @@ func (f *flags) setCountSubCommandOptions(cmd *cobra.Command, opts *options.Opti
 	return f.setOptions(cmd, opts, _flags)
 }
 
+func (f *flags) setSuggestGroupsSubCommandOptions(cmd *cobra.Command, opts *options.Options) (*options.Options, error) {
+	minCardinality, err := cmd.PersistentFlags().GetInt(flagSuggestGroupsMinCardinality)
+	if err != nil {
+		return nil, err
+	}
+
+	opts = options.SetOptions(opts,
+		options.SuggestGroupsMinCardinality(minCardinality),
+	)
+
+	_flags := []string{
+		flagFile,
+		flagLocation,
+		flagFilters,
+	}
+
+	return f.setOptions(cmd, opts, _flags)
+}
+
 // alp json
 func (f *flags) createJSONOptions(cmd *cobra.Command) (*options.Options, error) {
 	if f.config != "" {
@@ -1071,6 +1688,36 @@ func (f *flags) createJSONCountOptions(cmd *cobra.Command) (*options.Options, er
 	return f.setJSONOptions(cmd, opts)
 }
 
+// alp json suggest-groups
+func (f *flags) createJSONSuggestGroupsOptions(cmd *cobra.Command) (*options.Options, error) {
+	if f.config != "" {
+		f.bindFlags(cmd)
+		return f.createOptionsFromConfig(cmd)
+	}
+
+	opts, err := f.setSuggestGroupsSubCommandOptions(cmd, options.NewOptions())
+	if err != nil {
+		return nil, err
+	}
+
+	return f.setJSONOptions(cmd, opts)
+}
+
+// alp json check
+func (f *flags) createJSONCheckOptions(cmd *cobra.Command) (*options.Options, error) {
+	if f.config != "" {
+		f.bindFlags(cmd)
+		return f.createOptionsFromConfig(cmd)
+	}
+
+	opts, err := f.setCheckSubCommandOptions(cmd, options.NewOptions())
+	if err != nil {
+		return nil, err
+	}
+
+	return f.setJSONOptions(cmd, opts)
+}
+
 // alp ltsv
 func (f *flags) createLTSVOptions(cmd *cobra.Command) (*options.Options, error) {
 	if f.config != "" {
@@ -1131,6 +1778,36 @@ func (f *flags) createLTSVCountOptions(cmd *cobra.Command) (*options.Options, er
 	return f.setLTSVOptions(cmd, opts)
 }
 
+// alp ltsv suggest-groups
+func (f *flags) createLTSVSuggestGroupsOptions(cmd *cobra.Command) (*options.Options, error) {
+	if f.config != "" {
+		f.bindFlags(cmd)
+		return f.createOptionsFromConfig(cmd)
+	}
+
+	opts, err := f.setSuggestGroupsSubCommandOptions(cmd, options.NewOptions())
+	if err != nil {
+		return nil, err
+	}
+
+	return f.setLTSVOptions(cmd, opts)
+}
+
+// alp ltsv check
+func (f *flags) createLTSVCheckOptions(cmd *cobra.Command) (*options.Options, error) {
+	if f.config != "" {
+		f.bindFlags(cmd)
+		return f.createOptionsFromConfig(cmd)
+	}
+
+	opts, err := f.setCheckSubCommandOptions(cmd, options.NewOptions())
+	if err != nil {
+		return nil, err
+	}
+
+	return f.setLTSVOptions(cmd, opts)
+}
+
 // alp regexp
 func (f *flags) createRegexpOptions(cmd *cobra.Command) (*options.Options, error) {
 	if f.config != "" {
@@ -1191,6 +1868,36 @@ func (f *flags) createRegexpCountOptions(cmd *cobra.Command) (*options.Options,
 	return f.setRegexpOptions(cmd, opts)
 }
 
+// alp regexp suggest-groups
+func (f *flags) createRegexpSuggestGroupsOptions(cmd *cobra.Command) (*options.Options, error) {
+	if f.config != "" {
+		f.bindFlags(cmd)
+		return f.createOptionsFromConfig(cmd)
+	}
+
+	opts, err := f.setSuggestGroupsSubCommandOptions(cmd, options.NewOptions())
+	if err != nil {
+		return nil, err
+	}
+
+	return f.setRegexpOptions(cmd, opts)
+}
+
+// alp regexp check
+func (f *flags) createRegexpCheckOptions(cmd *cobra.Command) (*options.Options, error) {
+	if f.config != "" {
+		f.bindFlags(cmd)
+		return f.createOptionsFromConfig(cmd)
+	}
+
+	opts, err := f.setCheckSubCommandOptions(cmd, options.NewOptions())
+	if err != nil {
+		return nil, err
+	}
+
+	return f.setRegexpOptions(cmd, opts)
+}
+
 // alp pcap
 func (f *flags) createPcapOptions(cmd *cobra.Command) (*options.Options, error) {
 	if f.config != "" {
@@ -1236,6 +1943,52 @@ func (f *flags) createPcapTopNOptions(cmd *cobra.Command) (*options.Options, err
 	return f.setPcapOptions(cmd, opts)
 }
 
+// alp pcap suggest-groups
+func (f *flags) createPcapSuggestGroupsOptions(cmd *cobra.Command) (*options.Options, error) {
+	if f.config != "" {
+		f.bindFlags(cmd)
+		return f.createOptionsFromConfig(cmd)
+	}
+
+	opts, err := f.setSuggestGroupsSubCommandOptions(cmd, options.NewOptions())
+	if err != nil {
+		return nil, err
+	}
+
+	return f.setPcapOptions(cmd, opts)
+}
+
+// alp pcap check
+func (f *flags) createPcapCheckOptions(cmd *cobra.Command) (*options.Options, error) {
+	if f.config != "" {
+		f.bindFlags(cmd)
+		return f.createOptionsFromConfig(cmd)
+	}
+
+	opts, err := f.setCheckSubCommandOptions(cmd, options.NewOptions())
+	if err != nil {
+		return nil, err
+	}
+
+	return f.setPcapOptions(cmd, opts)
+}
+
+// alp check
+func (f *flags) createCheckOptions(cmd *cobra.Command) (*options.Options, error) {
+	if f.config != "" {
+		f.bindFlags(cmd)
+		return f.createOptionsFromConfig(cmd)
+	}
+
+	return f.setCheckOptions(cmd, options.NewOptions())
+}
+
+// alp merge
+func (f *flags) createMergeOptions(cmd *cobra.Command) (*options.Options, error) {
+	// the configuration file is not read, because the output of merge is not the output of the configuration file
+	return f.setMergeOptions(cmd, options.NewOptions())
+}
+
 // alp diff
 func (f *flags) createDiffOptions(cmd *cobra.Command) (*options.Options, error) {
 	if f.config != "" {
@@ -53,6 +53,7 @@ func Test_createOptionsFromConfig_overwrite(t *testing.T) {
 
 	viper.Set("file", overwrittenOpts.File)
 	viper.Set("dump", overwrittenOpts.Dump)
+	viper.Set("dump_format", overwrittenOpts.DumpFormat)
 	viper.Set("load", overwrittenOpts.Load)
 	viper.Set("sort", overwrittenSort)
 	viper.Set("reverse", overwrittenOpts.Reverse)
@@ -63,14 +64,31 @@ func Test_createOptionsFromConfig_overwrite(t *testing.T) {
 	viper.Set("noheaders", overwrittenOpts.NoHeaders)
 	viper.Set("show_footers", overwrittenOpts.ShowFooters)
 	viper.Set("limit", overwrittenOpts.Limit)
-	viper.Set("matching_groups", strings.Join(overwrittenOpts.MatchingGroups, ","))
+	viper.Set("matching_groups", testutil.MatchingGroupsToString(overwrittenOpts.MatchingGroups))
 	viper.Set("filters", overwrittenOpts.Filters)
 	viper.Set("pos_file", overwrittenOpts.PosFile)
 	viper.Set("nosave_pos", overwrittenOpts.NoSavePos)
 	viper.Set("location", overwrittenOpts.Location)
 	viper.Set("output", overwrittenOpts.Output)
 	viper.Set("percentiles", testutil.IntSliceToString(overwrittenOpts.Percentiles))
+	viper.Set("status_codes", testutil.IntSliceToString(overwrittenOpts.StatusCodes))
+	viper.Set("group_by", strings.Join(overwrittenOpts.GroupBy, ","))
 	viper.Set("pagination_limit", overwrittenOpts.PaginationLimit)
+	viper.Set("percentile_estimator", overwrittenOpts.PercentileEstimator)
+	viper.Set("percentile_accuracy", overwrittenOpts.PercentileAccuracy)
+	viper.Set("apdex_threshold", overwrittenOpts.ApdexThreshold)
+	viper.Set("significance_level", overwrittenOpts.SignificanceLevel)
+	viper.Set("workers", overwrittenOpts.Workers)
+	viper.Set("timeline", overwrittenOpts.Timeline)
+	viper.Set("follow", overwrittenOpts.Follow)
+	viper.Set("follow_interval", overwrittenOpts.FollowInterval)
+	viper.Set("metrics_listen", overwrittenOpts.MetricsListen)
+	viper.Set("metrics_buckets", overwrittenOpts.MetricsBuckets)
+	viper.Set("openapi", overwrittenOpts.OpenAPI)
+	viper.Set("histogram", overwrittenOpts.Histogram)
+	viper.Set("histogram_buckets", overwrittenOpts.HistogramBuckets)
+	viper.Set("histogram_scale", overwrittenOpts.HistogramScale)
+	viper.Set("histogram_uri", overwrittenOpts.HistogramUri)
 
 	// json
 	viper.Set("json.uri_key", overwrittenOpts.JSON.UriKey)
@@ -79,6 +97,7 @@ func Test_createOptionsFromConfig_overwrite(t *testing.T) {
 	viper.Set("json.restime_key", overwrittenOpts.JSON.ResponseTimeKey)
 	viper.Set("json.reqtime_key", overwrittenOpts.JSON.RequestTimeKey)
 	viper.Set("json.body_bytes_key", overwrittenOpts.JSON.BodyBytesKey)
+	viper.Set("json.request_body_bytes_key", overwrittenOpts.JSON.RequestBodyBytesKey)
 	viper.Set("json.status_key", overwrittenOpts.JSON.StatusKey)
 
 	// ltsv
@@ -88,6 +107,7 @@ func Test_createOptionsFromConfig_overwrite(t *testing.T) {
 	viper.Set("ltsv.apptime_label", overwrittenOpts.LTSV.ApptimeLabel)
 	viper.Set("ltsv.reqtime_label", overwrittenOpts.LTSV.ReqtimeLabel)
 	viper.Set("ltsv.size_label", overwrittenOpts.LTSV.SizeLabel)
+	viper.Set("ltsv.reqsize_label", overwrittenOpts.LTSV.ReqsizeLabel)
 	viper.Set("ltsv.status_label", overwrittenOpts.LTSV.StatusLabel)
 
 	// regexp
@@ -98,6 +118,7 @@ func Test_createOptionsFromConfig_overwrite(t *testing.T) {
 	viper.Set("regexp.restime_subexp", overwrittenOpts.Regexp.ResponseTimeSubexp)
 	viper.Set("regexp.reqtime_subexp", overwrittenOpts.Regexp.RequestTimeSubexp)
 	viper.Set("regexp.body_bytes_subexp", overwrittenOpts.Regexp.BodyBytesSubexp)
+	viper.Set("regexp.request_body_bytes_subexp", overwrittenOpts.Regexp.RequestBodyBytesSubexp)
 	viper.Set("regexp.status_subexp", overwrittenOpts.Regexp.StatusSubexp)
 
 	// pcap
@@ -111,6 +132,10 @@ func Test_createOptionsFromConfig_overwrite(t *testing.T) {
 	viper.Set("topN.sort", overwrittenOpts.TopN.Sort)
 	viper.Set("topN.reverse", overwrittenOpts.TopN.Reverse)
 
+	// suggest-groups
+	viper.Set("suggest_groups.min_cardinality", overwrittenOpts.SuggestGroups.MinCardinality)
+	viper.Set("check.baseline", overwrittenOpts.Check.Baseline)
+
 	var opts *options.Options
 	opts, err = command.flags.createOptionsFromConfig(command.rootCmd)
 	if err != nil {
@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"io"
 	"os"
 
 	"github.com/tkuchiki/alp/counter"
@@ -11,6 +12,7 @@ import (
 	"github.com/tkuchiki/alp/options"
 	"github.com/tkuchiki/alp/parsers"
 	"github.com/tkuchiki/alp/profiler"
+	"github.com/tkuchiki/alp/suggester"
 )
 
 func newJSONCmd(flags *flags) *cobra.Command {
@@ -54,11 +56,11 @@ func newJSONCmd(flags *flags) *cobra.Command {
 	return jsonCmd
 }
 
-func newJsonParser(opts *options.Options, f *os.File) parsers.Parser {
+func newJsonParser(opts *options.Options, r io.Reader) parsers.Parser {
 	keys := parsers.NewJSONKeys(opts.JSON.UriKey, opts.JSON.MethodKey, opts.JSON.TimeKey,
-		opts.JSON.ResponseTimeKey, opts.JSON.RequestTimeKey, opts.JSON.BodyBytesKey, opts.JSON.StatusKey)
+		opts.JSON.ResponseTimeKey, opts.JSON.RequestTimeKey, opts.JSON.BodyBytesKey, opts.JSON.RequestBodyBytesKey, opts.JSON.StatusKey)
 
-	return parsers.NewJSONParser(f, keys, opts.QueryString, opts.QueryStringIgnoreValues)
+	return parsers.NewJSONParser(r, keys, opts.QueryString, opts.QueryStringIgnoreValues)
 }
 
 func newJsonDiffCmd(flags *flags) *cobra.Command {
@@ -178,3 +180,68 @@ func newJsonCountCmd(flags *flags) *cobra.Command {
 
 	return jsonCountCmd
 }
+
+func newJsonSuggestGroupsCmd(flags *flags) *cobra.Command {
+	jsonSuggestGroupsCmd := newSuggestGroupsSubCmd()
+	jsonSuggestGroupsCmd.RunE = func(cmd *cobra.Command, args []string) error {
+		opts, err := flags.createJSONSuggestGroupsOptions(cmd)
+		if err != nil {
+			return err
+		}
+
+		suggester := suggester.NewSuggester(os.Stdout, os.Stderr, opts)
+
+		f, err := suggester.Open(opts.File)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		parser := newJsonParser(opts, f)
+
+		return runSuggestGroups(suggester, parser)
+	}
+
+	flags.defineSuggestGroupsSubCommandOptions(jsonSuggestGroupsCmd)
+	flags.defineJSONOptions(jsonSuggestGroupsCmd)
+
+	jsonSuggestGroupsCmd.Flags().SortFlags = false
+	jsonSuggestGroupsCmd.PersistentFlags().SortFlags = false
+	jsonSuggestGroupsCmd.InheritedFlags().SortFlags = false
+
+	return jsonSuggestGroupsCmd
+}
+
+func newJsonCheckCmd(flags *flags) *cobra.Command {
+	jsonCheckCmd := newCheckSubCmd()
+	jsonCheckCmd.RunE = func(cmd *cobra.Command, args []string) error {
+		opts, err := flags.createJSONCheckOptions(cmd)
+		if err != nil {
+			return err
+		}
+
+		c, prof, err := newCheckProfiler(opts)
+		if err != nil {
+			return err
+		}
+
+		f, err := prof.Open(opts.File)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		parser := newJsonParser(opts, f)
+
+		return runCheck(flags.sortOptions, c, prof, parser)
+	}
+
+	flags.defineCheckSubCommandOptions(jsonCheckCmd)
+	flags.defineJSONOptions(jsonCheckCmd)
+
+	jsonCheckCmd.Flags().SortFlags = false
+	jsonCheckCmd.PersistentFlags().SortFlags = false
+	jsonCheckCmd.InheritedFlags().SortFlags = false
+
+	return jsonCheckCmd
+}
@@ -172,3 +172,35 @@ func TestJSONCountCmd(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+func TestJSONSuggestGroupsCmd(t *testing.T) {
+	keys := testutil.NewJsonLogKeys()
+
+	jsonLog := testutil.JsonLog(keys)
+
+	tempFile, err := testutil.CreateTempDirAndFile(t.TempDir(), "test_json_suggest_groups_cmd_temp_file", jsonLog)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	args := []string{"json", "suggest-groups",
+		"--file", tempFile,
+		"--min-cardinality", "2",
+	}
+
+	command := NewCommand("test")
+	command.setArgs(args)
+
+	out, err := testutil.CaptureStdout(command.Execute)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `# 2 of 2 distinct URIs are grouped into 1 groups
+matching_groups:
+  - '^/foo/bar/[0-9]+$' # 2 URIs (e.g. /foo/bar/123)
+`
+	if out != want {
+		t.Errorf("want: %s, got: %s", want, out)
+	}
+}
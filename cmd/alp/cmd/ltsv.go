@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"io"
 	"os"
 
 	"github.com/tkuchiki/alp/counter"
@@ -11,6 +12,7 @@ import (
 	"github.com/tkuchiki/alp/options"
 	"github.com/tkuchiki/alp/parsers"
 	"github.com/tkuchiki/alp/profiler"
+	"github.com/tkuchiki/alp/suggester"
 )
 
 func newLTSVCmd(flags *flags) *cobra.Command {
@@ -54,12 +56,12 @@ func newLTSVCmd(flags *flags) *cobra.Command {
 	return ltsvCmd
 }
 
-func newLTSVParser(opts *options.Options, f *os.File) parsers.Parser {
+func newLTSVParser(opts *options.Options, r io.Reader) parsers.Parser {
 	label := parsers.NewLTSVLabel(opts.LTSV.UriLabel, opts.LTSV.MethodLabel, opts.LTSV.TimeLabel,
-		opts.LTSV.ApptimeLabel, opts.LTSV.ReqtimeLabel, opts.LTSV.SizeLabel, opts.LTSV.StatusLabel,
+		opts.LTSV.ApptimeLabel, opts.LTSV.ReqtimeLabel, opts.LTSV.SizeLabel, opts.LTSV.ReqsizeLabel, opts.LTSV.StatusLabel,
 	)
 
-	return parsers.NewLTSVParser(f, label, opts.QueryString, opts.QueryStringIgnoreValues)
+	return parsers.NewLTSVParser(r, label, opts.QueryString, opts.QueryStringIgnoreValues)
 }
 
 func newLTSVDiffCmd(flags *flags) *cobra.Command {
@@ -179,3 +181,68 @@ func newLTSVCountCmd(flags *flags) *cobra.Command {
 
 	return ltsvCountCmd
 }
+
+func newLTSVSuggestGroupsCmd(flags *flags) *cobra.Command {
+	ltsvSuggestGroupsCmd := newSuggestGroupsSubCmd()
+	ltsvSuggestGroupsCmd.RunE = func(cmd *cobra.Command, args []string) error {
+		opts, err := flags.createLTSVSuggestGroupsOptions(cmd)
+		if err != nil {
+			return err
+		}
+
+		suggester := suggester.NewSuggester(os.Stdout, os.Stderr, opts)
+
+		f, err := suggester.Open(opts.File)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		parser := newLTSVParser(opts, f)
+
+		return runSuggestGroups(suggester, parser)
+	}
+
+	flags.defineSuggestGroupsSubCommandOptions(ltsvSuggestGroupsCmd)
+	flags.defineLTSVOptions(ltsvSuggestGroupsCmd)
+
+	ltsvSuggestGroupsCmd.Flags().SortFlags = false
+	ltsvSuggestGroupsCmd.PersistentFlags().SortFlags = false
+	ltsvSuggestGroupsCmd.InheritedFlags().SortFlags = false
+
+	return ltsvSuggestGroupsCmd
+}
+
+func newLTSVCheckCmd(flags *flags) *cobra.Command {
+	ltsvCheckCmd := newCheckSubCmd()
+	ltsvCheckCmd.RunE = func(cmd *cobra.Command, args []string) error {
+		opts, err := flags.createLTSVCheckOptions(cmd)
+		if err != nil {
+			return err
+		}
+
+		c, prof, err := newCheckProfiler(opts)
+		if err != nil {
+			return err
+		}
+
+		f, err := prof.Open(opts.File)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		parser := newLTSVParser(opts, f)
+
+		return runCheck(flags.sortOptions, c, prof, parser)
+	}
+
+	flags.defineCheckSubCommandOptions(ltsvCheckCmd)
+	flags.defineLTSVOptions(ltsvCheckCmd)
+
+	ltsvCheckCmd.Flags().SortFlags = false
+	ltsvCheckCmd.PersistentFlags().SortFlags = false
+	ltsvCheckCmd.InheritedFlags().SortFlags = false
+
+	return ltsvCheckCmd
+}
@@ -172,3 +172,35 @@ func TestLTSVCountCmd(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+func TestLTSVSuggestGroupsCmd(t *testing.T) {
+	keys := testutil.NewLTSVLogKeys()
+
+	ltsvLog := testutil.LTSVLog(keys)
+
+	tempFile, err := testutil.CreateTempDirAndFile(t.TempDir(), "test_ltsv_suggest_groups_cmd_temp_file", ltsvLog)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	args := []string{"ltsv", "suggest-groups",
+		"--file", tempFile,
+		"--min-cardinality", "2",
+	}
+
+	command := NewCommand("test")
+	command.setArgs(args)
+
+	out, err := testutil.CaptureStdout(command.Execute)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `# 2 of 2 distinct URIs are grouped into 1 groups
+matching_groups:
+  - '^/foo/bar/[0-9]+$' # 2 URIs (e.g. /foo/bar/123)
+`
+	if out != want {
+		t.Errorf("want: %s, got: %s", want, out)
+	}
+}
@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/tkuchiki/alp/helpers"
+	"github.com/tkuchiki/alp/stats"
+)
+
+func newMergeCmd(flags *flags) *cobra.Command {
+	mergeCmd := &cobra.Command{
+		Use:   "merge <dump>...",
+		Args:  cobra.MinimumNArgs(1),
+		Short: "Merge the profile results",
+		Long:  `Merge the profile results by the method, the URI and the groups, e.g. the results dumped by several servers behind a load balancer`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts, err := flags.createMergeOptions(cmd)
+			if err != nil {
+				return err
+			}
+
+			sts := stats.NewHTTPStats(true, false, false)
+
+			for _, arg := range args {
+				files, err := helpers.ExpandFiles(arg)
+				if err != nil {
+					return err
+				}
+
+				for _, file := range files {
+					f, err := os.Open(file)
+					if err != nil {
+						return err
+					}
+
+					warnings, err := sts.LoadStatsWithWarnings(f)
+					f.Close()
+					if err != nil {
+						return err
+					}
+
+					for _, w := range warnings {
+						fmt.Fprintf(os.Stderr, "Warning: %s: %s\n", file, w)
+					}
+				}
+			}
+
+			format, err := stats.DumpFormat(opts.DumpFormat, opts.Dump)
+			if err != nil {
+				return err
+			}
+
+			var w io.Writer = os.Stdout
+			if opts.Dump != "" {
+				df, err := os.OpenFile(opts.Dump, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0644)
+				if err != nil {
+					return err
+				}
+				defer df.Close()
+
+				w = df
+			}
+
+			return sts.DumpStatsWithFormat(w, format)
+		},
+	}
+
+	flags.defineMergeOptions(mergeCmd)
+
+	mergeCmd.Flags().SortFlags = false
+	mergeCmd.PersistentFlags().SortFlags = false
+	mergeCmd.InheritedFlags().SortFlags = false
+
+	return mergeCmd
+}
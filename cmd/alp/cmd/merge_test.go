@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/tkuchiki/alp/stats"
+)
+
+func TestMergeCmd(t *testing.T) {
+	dump1 := "../../../example/logs/dump1.yaml"
+	dump2 := "../../../example/logs/dump2.yaml"
+	merged := filepath.Join(t.TempDir(), "merged.yaml")
+
+	command := NewCommand("test")
+	command.setArgs([]string{"merge", dump1, dump2, "-o", merged})
+
+	if err := command.Execute(); err != nil {
+		t.Fatal(err)
+	}
+
+	load := func(filenames ...string) *stats.HTTPStats {
+		sts := stats.NewHTTPStats(true, false, false)
+		for _, filename := range filenames {
+			f, err := os.Open(filename)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer f.Close()
+
+			if err = sts.LoadStats(f); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		return sts
+	}
+
+	want := load(dump1, dump2)
+	got := load(merged)
+
+	if got.CountUris() != want.CountUris() {
+		t.Fatalf("uris want: %d, got: %d", want.CountUris(), got.CountUris())
+	}
+
+	for _, s := range want.Stats() {
+		g := got.Find(s)
+		if g == nil {
+			t.Errorf("%s %s is not found", s.Method, s.Uri)
+			continue
+		}
+
+		if g.Cnt != s.Cnt || g.SumResponseTime() != s.SumResponseTime() || g.PNResponseTime(90) != s.PNResponseTime(90) {
+			t.Errorf("%s %s want: %d %f %f, got: %d %f %f", s.Method, s.Uri,
+				s.Cnt, s.SumResponseTime(), s.PNResponseTime(90),
+				g.Cnt, g.SumResponseTime(), g.PNResponseTime(90))
+		}
+	}
+}
@@ -1,14 +1,19 @@
 package cmd
 
 import (
+	"fmt"
+	"io"
 	"os"
+	"strings"
 
 	"github.com/tkuchiki/alp/log_reader"
 
 	"github.com/spf13/cobra"
+	"github.com/tkuchiki/alp/helpers"
 	"github.com/tkuchiki/alp/options"
 	"github.com/tkuchiki/alp/parsers"
 	"github.com/tkuchiki/alp/profiler"
+	"github.com/tkuchiki/alp/suggester"
 )
 
 func newPcapCmd(flags *flags) *cobra.Command {
@@ -55,8 +60,20 @@ func newPcapCmd(flags *flags) *cobra.Command {
 	return pcapCmd
 }
 
-func newPcapParser(opts *options.Options, f *os.File) (parsers.Parser, error) {
-	return parsers.NewPcapParser(f, opts.Pcap.ServerIPs, opts.Pcap.ServerPort, opts.QueryString, opts.QueryStringIgnoreValues)
+func newPcapParser(opts *options.Options, r io.Reader) (parsers.Parser, error) {
+	// pcap files cannot be concatenated
+	if opts.File != "" {
+		files, err := helpers.ExpandFiles(opts.File)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(files) > 1 {
+			return nil, fmt.Errorf("pcap does not support multiple files: %s", strings.Join(files, ","))
+		}
+	}
+
+	return parsers.NewPcapParser(r, opts.Pcap.ServerIPs, opts.Pcap.ServerPort, opts.QueryString, opts.QueryStringIgnoreValues)
 }
 
 func newPcapDiffCmd(flags *flags) *cobra.Command {
@@ -157,3 +174,74 @@ func newPcapTopNCmd(flags *flags) *cobra.Command {
 
 	return pcapTopNCmd
 }
+
+func newPcapSuggestGroupsCmd(flags *flags) *cobra.Command {
+	pcapSuggestGroupsCmd := newSuggestGroupsSubCmd()
+	pcapSuggestGroupsCmd.RunE = func(cmd *cobra.Command, args []string) error {
+		opts, err := flags.createPcapSuggestGroupsOptions(cmd)
+		if err != nil {
+			return err
+		}
+
+		suggester := suggester.NewSuggester(os.Stdout, os.Stderr, opts)
+
+		f, err := suggester.Open(opts.File)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		parser, err := newPcapParser(opts, f)
+		if err != nil {
+			return err
+		}
+
+		return runSuggestGroups(suggester, parser)
+	}
+
+	flags.defineSuggestGroupsSubCommandOptions(pcapSuggestGroupsCmd)
+	flags.definePcapOptions(pcapSuggestGroupsCmd)
+
+	pcapSuggestGroupsCmd.Flags().SortFlags = false
+	pcapSuggestGroupsCmd.PersistentFlags().SortFlags = false
+	pcapSuggestGroupsCmd.InheritedFlags().SortFlags = false
+
+	return pcapSuggestGroupsCmd
+}
+
+func newPcapCheckCmd(flags *flags) *cobra.Command {
+	pcapCheckCmd := newCheckSubCmd()
+	pcapCheckCmd.RunE = func(cmd *cobra.Command, args []string) error {
+		opts, err := flags.createPcapCheckOptions(cmd)
+		if err != nil {
+			return err
+		}
+
+		c, prof, err := newCheckProfiler(opts)
+		if err != nil {
+			return err
+		}
+
+		f, err := prof.Open(opts.File)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		parser, err := newPcapParser(opts, f)
+		if err != nil {
+			return err
+		}
+
+		return runCheck(flags.sortOptions, c, prof, parser)
+	}
+
+	flags.defineCheckSubCommandOptions(pcapCheckCmd)
+	flags.definePcapOptions(pcapCheckCmd)
+
+	pcapCheckCmd.Flags().SortFlags = false
+	pcapCheckCmd.PersistentFlags().SortFlags = false
+	pcapCheckCmd.InheritedFlags().SortFlags = false
+
+	return pcapCheckCmd
+}
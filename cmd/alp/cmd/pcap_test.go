@@ -96,3 +96,30 @@ func TestPcapTopNCmd(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+func TestPcapSuggestGroupsCmd(t *testing.T) {
+	pcapFile := "../../../example/logs/http.cap"
+	pcapServerPort := "18080"
+
+	args := []string{"pcap", "suggest-groups",
+		"--file", pcapFile,
+		"--pcap-server-ip", options.DefaultPcapServerIPsOption[0],
+		"--pcap-server-port", pcapServerPort,
+	}
+
+	command := NewCommand("test")
+	command.setArgs(args)
+
+	out, err := testutil.CaptureStdout(command.Execute)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `# 2 of 2 distinct URIs are grouped into 1 groups
+matching_groups:
+  - '^/foo/bar/[0-9]+$' # 2 URIs (e.g. /foo/bar/123)
+`
+	if out != want {
+		t.Errorf("want: %s, got: %s", want, out)
+	}
+}
@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"io"
 	"os"
 
 	"github.com/tkuchiki/alp/counter"
@@ -11,6 +12,7 @@ import (
 	"github.com/tkuchiki/alp/options"
 	"github.com/tkuchiki/alp/parsers"
 	"github.com/tkuchiki/alp/profiler"
+	"github.com/tkuchiki/alp/suggester"
 )
 
 func newRegexpCmd(flags *flags) *cobra.Command {
@@ -57,10 +59,10 @@ func newRegexpCmd(flags *flags) *cobra.Command {
 	return regexpCmd
 }
 
-func newRegexpParser(opts *options.Options, f *os.File) (parsers.Parser, error) {
+func newRegexpParser(opts *options.Options, r io.Reader) (parsers.Parser, error) {
 	names := parsers.NewSubexpNames(opts.Regexp.UriSubexp, opts.Regexp.MethodSubexp, opts.Regexp.TimeSubexp,
-		opts.Regexp.ResponseTimeSubexp, opts.Regexp.RequestTimeSubexp, opts.Regexp.BodyBytesSubexp, opts.Regexp.StatusSubexp)
-	return parsers.NewRegexpParser(f, opts.Regexp.Pattern, names, opts.QueryString, opts.QueryStringIgnoreValues)
+		opts.Regexp.ResponseTimeSubexp, opts.Regexp.RequestTimeSubexp, opts.Regexp.BodyBytesSubexp, opts.Regexp.RequestBodyBytesSubexp, opts.Regexp.StatusSubexp)
+	return parsers.NewRegexpParser(r, opts.Regexp.Pattern, names, opts.QueryString, opts.QueryStringIgnoreValues)
 }
 
 func newRegexpDiffCmd(flags *flags) *cobra.Command {
@@ -192,3 +194,74 @@ func newRegexpCountCmd(flags *flags) *cobra.Command {
 
 	return regexpCountCmd
 }
+
+func newRegexpSuggestGroupsCmd(flags *flags) *cobra.Command {
+	regexpSuggestGroupsCmd := newSuggestGroupsSubCmd()
+	regexpSuggestGroupsCmd.RunE = func(cmd *cobra.Command, args []string) error {
+		opts, err := flags.createRegexpSuggestGroupsOptions(cmd)
+		if err != nil {
+			return err
+		}
+
+		suggester := suggester.NewSuggester(os.Stdout, os.Stderr, opts)
+
+		f, err := suggester.Open(opts.File)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		parser, err := newRegexpParser(opts, f)
+		if err != nil {
+			return err
+		}
+
+		return runSuggestGroups(suggester, parser)
+	}
+
+	flags.defineSuggestGroupsSubCommandOptions(regexpSuggestGroupsCmd)
+	flags.defineRegexpOptions(regexpSuggestGroupsCmd)
+
+	regexpSuggestGroupsCmd.Flags().SortFlags = false
+	regexpSuggestGroupsCmd.PersistentFlags().SortFlags = false
+	regexpSuggestGroupsCmd.InheritedFlags().SortFlags = false
+
+	return regexpSuggestGroupsCmd
+}
+
+func newRegexpCheckCmd(flags *flags) *cobra.Command {
+	regexpCheckCmd := newCheckSubCmd()
+	regexpCheckCmd.RunE = func(cmd *cobra.Command, args []string) error {
+		opts, err := flags.createRegexpCheckOptions(cmd)
+		if err != nil {
+			return err
+		}
+
+		c, prof, err := newCheckProfiler(opts)
+		if err != nil {
+			return err
+		}
+
+		f, err := prof.Open(opts.File)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		parser, err := newRegexpParser(opts, f)
+		if err != nil {
+			return err
+		}
+
+		return runCheck(flags.sortOptions, c, prof, parser)
+	}
+
+	flags.defineCheckSubCommandOptions(regexpCheckCmd)
+	flags.defineRegexpOptions(regexpCheckCmd)
+
+	regexpCheckCmd.Flags().SortFlags = false
+	regexpCheckCmd.PersistentFlags().SortFlags = false
+	regexpCheckCmd.InheritedFlags().SortFlags = false
+
+	return regexpCheckCmd
+}
@@ -165,3 +165,36 @@ func TestRegexpCountCmd(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+func TestRegexpSuggestGroupsCmd(t *testing.T) {
+	keys := testutil.NewRegexpLogKeys()
+
+	regexpLog := testutil.RegexpLog()
+
+	tempFile, err := testutil.CreateTempDirAndFile(t.TempDir(), "test_regexp_suggest_groups_cmd_temp_file", regexpLog)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	args := []string{"regexp", "suggest-groups",
+		"--pattern", testutil.RegexpPattern(keys),
+		"--file", tempFile,
+		"--min-cardinality", "2",
+	}
+
+	command := NewCommand("test")
+	command.setArgs(args)
+
+	out, err := testutil.CaptureStdout(command.Execute)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `# 2 of 2 distinct URIs are grouped into 1 groups
+matching_groups:
+  - '^/foo/bar/[0-9]+$' # 2 URIs (e.g. /foo/bar/123)
+`
+	if out != want {
+		t.Errorf("want: %s, got: %s", want, out)
+	}
+}
@@ -0,0 +1,20 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/tkuchiki/alp/parsers"
+	"github.com/tkuchiki/alp/suggester"
+)
+
+func newSuggestGroupsSubCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "suggest-groups",
+		Short: "Suggest URI matching groups from the log",
+		Long:  `Suggest URI matching groups from the log`,
+	}
+}
+
+func runSuggestGroups(suggester *suggester.Suggester, parser parsers.Parser) error {
+	suggester.SetParser(parser)
+	return suggester.SuggestAndPrint()
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/tkuchiki/alp/options"
 
 	"github.com/tkuchiki/alp/errors"
+	"github.com/tkuchiki/alp/helpers"
 	"github.com/tkuchiki/alp/parsers"
 )
 
@@ -43,17 +44,12 @@ func (c *Counter) SetInReader(f *os.File) {
 	c.inReader = f
 }
 
-func (c *Counter) Open(filename string) (*os.File, error) {
-	var f *os.File
-	var err error
-
+func (c *Counter) Open(filename string) (io.ReadCloser, error) {
 	if filename != "" {
-		f, err = os.Open(filename)
-	} else {
-		f = c.inReader
+		return helpers.OpenFiles(filename)
 	}
 
-	return f, err
+	return helpers.NewDecompressReader(c.inReader), nil
 }
 
 func (c *Counter) Count(keys []string) error {
@@ -146,7 +146,13 @@ func (p *Printer) printHTML(groups *groups) {
 		data = append(data, p.generateLine(groups.keys, group))
 	}
 
-	content, _ := html.RenderTableWithGridJS("alp", headers, data, p.printOptions.paginationLimit)
+	report := &html.Report{
+		Title:           "alp",
+		Columns:         headers,
+		Rows:            data,
+		PaginationLimit: p.printOptions.paginationLimit,
+	}
+	content, _ := report.Render()
 	fmt.Println(content)
 }
 
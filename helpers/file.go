@@ -0,0 +1,329 @@
+package helpers
+
+import (
+	"bufio"
+	"bytes"
+	"compress/bzip2"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+var (
+	gzipMagic  = []byte{0x1f, 0x8b}
+	zstdMagic  = []byte{0x28, 0xb5, 0x2f, 0xfd}
+	bzip2Magic = []byte("BZh")
+)
+
+// decompressReader detects the compression format on the first Read,
+// so that opening a reader that is never read (e.g. stdin) does not block.
+type decompressReader struct {
+	r       io.ReadCloser
+	reader  io.Reader
+	closers []io.Closer
+}
+
+// NewDecompressReader returns a reader that decompresses r if r is compressed with gzip, zstd or bzip2.
+// The compression format is detected by the magic bytes. Close closes r as well.
+func NewDecompressReader(r io.ReadCloser) io.ReadCloser {
+	return &decompressReader{
+		r:       r,
+		closers: []io.Closer{r},
+	}
+}
+
+func (dr *decompressReader) init() error {
+	br := bufio.NewReader(dr.r)
+	magic, err := br.Peek(len(zstdMagic))
+	if err != nil && err != io.EOF {
+		return err
+	}
+
+	switch {
+	case bytes.HasPrefix(magic, gzipMagic):
+		gr, err := gzip.NewReader(br)
+		if err != nil {
+			return err
+		}
+		dr.reader = gr
+		dr.closers = append([]io.Closer{gr}, dr.closers...)
+	case bytes.HasPrefix(magic, zstdMagic):
+		zr, err := zstd.NewReader(br)
+		if err != nil {
+			return err
+		}
+		dr.reader = zr
+		dr.closers = append([]io.Closer{zr.IOReadCloser()}, dr.closers...)
+	case bytes.HasPrefix(magic, bzip2Magic):
+		dr.reader = bzip2.NewReader(br)
+	default:
+		dr.reader = br
+	}
+
+	return nil
+}
+
+func (dr *decompressReader) Read(p []byte) (int, error) {
+	if dr.reader == nil {
+		if err := dr.init(); err != nil {
+			return 0, err
+		}
+	}
+
+	return dr.reader.Read(p)
+}
+
+func (dr *decompressReader) Close() error {
+	var err error
+	for _, c := range dr.closers {
+		if cerr := c.Close(); cerr != nil && err == nil {
+			err = cerr
+		}
+	}
+
+	return err
+}
+
+// ExpandFiles expands the comma separated file names and glob patterns.
+// The files matched by a pattern are sorted from oldest to newest by modification time,
+// so that rotated logs (e.g. access.log.2.gz, access.log.1, access.log) are read in order.
+func ExpandFiles(val string) ([]string, error) {
+	files := make([]string, 0)
+	for _, pattern := range SplitCSV(val) {
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(matches) == 0 {
+			return nil, fmt.Errorf("open %s: no such file or directory", pattern)
+		}
+
+		modTimes := make(map[string]int64, len(matches))
+		for _, m := range matches {
+			fi, err := os.Stat(m)
+			if err != nil {
+				return nil, err
+			}
+			modTimes[m] = fi.ModTime().UnixNano()
+		}
+
+		sort.SliceStable(matches, func(i, j int) bool {
+			return modTimes[matches[i]] < modTimes[matches[j]]
+		})
+
+		files = append(files, matches...)
+	}
+
+	return files, nil
+}
+
+// multiFileReader reads the files one after another as one stream.
+// The files are opened lazily, and a newline is inserted if a file does not end with it.
+// The last file is kept open at EOF and read again on the next Read, so that the lines appended to it are followed.
+type multiFileReader struct {
+	files          []string
+	current        io.ReadCloser
+	lastByte       byte
+	pendingNewline bool
+}
+
+// OpenFiles opens the comma separated file names and glob patterns as one stream,
+// and decompresses each file if it is compressed.
+func OpenFiles(val string) (io.ReadCloser, error) {
+	files, err := ExpandFiles(val)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(files) == 1 {
+		return openFile(files[0])
+	}
+
+	// open the first file to return the error immediately
+	r, err := openFile(files[0])
+	if err != nil {
+		return nil, err
+	}
+
+	return &multiFileReader{
+		files:    files[1:],
+		current:  r,
+		lastByte: '\n',
+	}, nil
+}
+
+func openFile(filename string) (io.ReadCloser, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewDecompressReader(f), nil
+}
+
+func (mr *multiFileReader) Read(p []byte) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+
+	for {
+		if mr.pendingNewline {
+			p[0] = '\n'
+			mr.pendingNewline = false
+			mr.lastByte = '\n'
+			return 1, nil
+		}
+
+		if mr.current == nil {
+			if len(mr.files) == 0 {
+				return 0, io.EOF
+			}
+
+			r, err := openFile(mr.files[0])
+			if err != nil {
+				return 0, err
+			}
+			mr.current = r
+			mr.files = mr.files[1:]
+		}
+
+		n, err := mr.current.Read(p)
+		if n > 0 {
+			mr.lastByte = p[n-1]
+		}
+
+		// the line of the last file may be being written, so that no newline is inserted
+		if err == io.EOF && len(mr.files) > 0 {
+			mr.current.Close()
+			mr.current = nil
+			if mr.lastByte != '\n' {
+				mr.pendingNewline = true
+			}
+
+			if n > 0 {
+				return n, nil
+			}
+			continue
+		}
+
+		return n, err
+	}
+}
+
+func (mr *multiFileReader) Close() error {
+	if mr.current == nil {
+		return nil
+	}
+
+	err := mr.current.Close()
+	mr.current = nil
+
+	return err
+}
+
+// FollowReader keeps reading r like `tail -f`.
+// When r reaches EOF, Read waits for data to be appended instead of returning io.EOF, until Stop is called.
+// r is read in the goroutine, so that Stop can interrupt Read even if r blocks, e.g. stdin.
+type FollowReader struct {
+	r            io.ReadCloser
+	pollInterval time.Duration
+	stop         chan struct{}
+	once         sync.Once
+	results      chan followResult
+	// reading is true while the goroutine reads r
+	reading bool
+	// buf and err are the rest of the result that has not been returned by Read
+	buf []byte
+	err error
+}
+
+type followResult struct {
+	b   []byte
+	err error
+}
+
+func NewFollowReader(r io.ReadCloser, pollInterval time.Duration) *FollowReader {
+	return &FollowReader{
+		r:            r,
+		pollInterval: pollInterval,
+		stop:         make(chan struct{}),
+		results:      make(chan followResult, 1),
+	}
+}
+
+func (fr *FollowReader) readAsync(size int) {
+	if fr.reading {
+		return
+	}
+	fr.reading = true
+
+	go func() {
+		b := make([]byte, size)
+		n, err := fr.r.Read(b)
+		fr.results <- followResult{b: b[:n], err: err}
+	}()
+}
+
+func (fr *FollowReader) Read(p []byte) (int, error) {
+	for {
+		if len(fr.buf) > 0 {
+			n := copy(p, fr.buf)
+			fr.buf = fr.buf[n:]
+			return n, nil
+		}
+
+		if fr.err != nil {
+			return 0, fr.err
+		}
+
+		fr.readAsync(len(p))
+
+		var res followResult
+		select {
+		case res = <-fr.results:
+		case <-fr.stop:
+			// the rest of the data is read after Stop, unless r blocks for pollInterval
+			select {
+			case res = <-fr.results:
+			case <-time.After(fr.pollInterval):
+				return 0, io.EOF
+			}
+		}
+		fr.reading = false
+
+		if res.err != io.EOF {
+			fr.err = res.err
+		}
+
+		if len(res.b) > 0 || fr.err != nil {
+			fr.buf = res.b
+			continue
+		}
+
+		select {
+		case <-fr.stop:
+			return 0, io.EOF
+		case <-time.After(fr.pollInterval):
+		}
+	}
+}
+
+// Stop makes Read return io.EOF when it reaches the end of the data
+func (fr *FollowReader) Stop() {
+	fr.once.Do(func() {
+		close(fr.stop)
+	})
+}
+
+func (fr *FollowReader) Close() error {
+	fr.Stop()
+	return fr.r.Close()
+}
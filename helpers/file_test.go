@@ -0,0 +1,76 @@
+package helpers
+
+import (
+	"io"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestOpenFiles(t *testing.T) {
+	dir := t.TempDir()
+	file1 := filepath.Join(dir, "access.log.1")
+	file2 := filepath.Join(dir, "access.log")
+	// the file that does not end with a newline is followed by a newline
+	writeFile(t, file1, "a\nb")
+	writeFile(t, file2, "c\n")
+
+	r, err := OpenFiles(file1 + "," + file2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+
+	b, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := string(b); got != "a\nb\nc\n" {
+		t.Errorf("want: %q, got: %q", "a\nb\nc\n", got)
+	}
+
+	// the lines appended to the last file are read after EOF
+	appendFile(t, file2, "d")
+	b, err = io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := string(b); got != "d" {
+		t.Errorf("want: %q, got: %q", "d", got)
+	}
+}
+
+func TestFollowOpenFiles(t *testing.T) {
+	dir := t.TempDir()
+	file1 := filepath.Join(dir, "access.log.1")
+	file2 := filepath.Join(dir, "access.log")
+	writeFile(t, file1, "a\n")
+	writeFile(t, file2, "b\n")
+
+	r, err := OpenFiles(file1 + "," + file2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fr := NewFollowReader(r, 10*time.Millisecond)
+	defer fr.Close()
+
+	read := func(want string) {
+		t.Helper()
+
+		buf := make([]byte, len(want))
+		if _, err := io.ReadFull(fr, buf); err != nil {
+			t.Fatal(err)
+		}
+		if got := string(buf); got != want {
+			t.Errorf("want: %q, got: %q", want, got)
+		}
+	}
+
+	read("a\nb\n")
+
+	// the follow reader polls the last file at EOF
+	time.Sleep(50 * time.Millisecond)
+	appendFile(t, file2, "c\n")
+	read("c\n")
+}
@@ -0,0 +1,13 @@
+//go:build windows
+
+package helpers
+
+import (
+	"os"
+)
+
+// fileID returns 0, since os.FileInfo does not have the file index on Windows.
+// The file is identified only by the head checksum.
+func fileID(fi os.FileInfo) (uint64, uint64) {
+	return 0, 0
+}
@@ -104,6 +104,31 @@ func SplitCSVIntoInts(val string) ([]int, error) {
 	return trimedInts, nil
 }
 
+func SplitCSVIntoFloat64s(val string) ([]float64, error) {
+	strs := SplitCSV(val)
+
+	floats := make([]float64, 0, len(strs))
+	for _, s := range strs {
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return []float64{}, err
+		}
+		floats = append(floats, f)
+	}
+
+	return floats, nil
+}
+
+func ValidateStatusCodes(statusCodes []int) error {
+	for _, i := range statusCodes {
+		if i < 100 || i > 599 {
+			return fmt.Errorf(`status codes allowed 100 to 599`)
+		}
+	}
+
+	return nil
+}
+
 func ValidatePercentiles(percentiles []int) error {
 	if len(percentiles) == 0 {
 		return nil
@@ -0,0 +1,303 @@
+package helpers
+
+import (
+	"bytes"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// the number of bytes from the beginning of the file used to identify the file
+const positionHeadSize = 1024
+
+// Position is the position of the log file saved in the pos file.
+// Inode, Dev and HeadChecksum identify the file, so that the rotation and the truncation can be detected.
+type Position struct {
+	Offset       int64  `yaml:"offset"`
+	Inode        uint64 `yaml:"inode,omitempty"`
+	Dev          uint64 `yaml:"dev,omitempty"`
+	Size         int64  `yaml:"size,omitempty"`
+	HeadSize     int64  `yaml:"head_size,omitempty"`
+	HeadChecksum string `yaml:"head_checksum,omitempty"`
+}
+
+// ReadPosition reads the pos file.
+// The pos file of the older versions that has only the number of bytes is also supported.
+func ReadPosition(r io.Reader) (*Position, error) {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	pos := &Position{}
+
+	s := strings.TrimSpace(string(b))
+	if s == "" {
+		return pos, nil
+	}
+
+	if offset, err := strconv.ParseInt(s, 10, 64); err == nil {
+		pos.Offset = offset
+		return pos, nil
+	}
+
+	err = yaml.Unmarshal(b, pos)
+
+	return pos, err
+}
+
+func (pos *Position) Write(w io.Writer) error {
+	buf, err := yaml.Marshal(pos)
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(buf)
+
+	return err
+}
+
+// identifies reports whether f is the file that pos was saved from, and can be resumed from pos.Offset
+func (pos *Position) identifies(f *os.File, fi os.FileInfo) bool {
+	if fi.Size() < pos.Offset {
+		// truncated
+		return false
+	}
+
+	if pos.HeadChecksum == "" {
+		// only the offset is saved
+		return true
+	}
+
+	inode, dev := fileID(fi)
+	if inode != 0 && pos.Inode != 0 && (inode != pos.Inode || dev != pos.Dev) {
+		return false
+	}
+
+	checksum, err := headChecksum(f, pos.HeadSize)
+	if err != nil {
+		return false
+	}
+
+	return checksum == pos.HeadChecksum
+}
+
+// PositionTracker counts the bytes read from the log file opened by OpenFileWithPosition
+type PositionTracker struct {
+	file   *os.File
+	offset int64
+}
+
+func (pt *PositionTracker) Read(p []byte) (int, error) {
+	n, err := pt.file.Read(p)
+	pt.offset += int64(n)
+
+	return n, err
+}
+
+// Position returns the position of the bytes read so far
+func (pt *PositionTracker) Position() (*Position, error) {
+	fi, err := pt.file.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	headSize := fi.Size()
+	if headSize > positionHeadSize {
+		headSize = positionHeadSize
+	}
+
+	checksum, err := headChecksum(pt.file, headSize)
+	if err != nil {
+		return nil, err
+	}
+
+	inode, dev := fileID(fi)
+
+	return &Position{
+		Offset:       pt.offset,
+		Inode:        inode,
+		Dev:          dev,
+		Size:         fi.Size(),
+		HeadSize:     headSize,
+		HeadChecksum: checksum,
+	}, nil
+}
+
+type positionReader struct {
+	io.Reader
+	closers []io.Closer
+}
+
+func (pr *positionReader) Close() error {
+	var err error
+	for _, c := range pr.closers {
+		if cerr := c.Close(); cerr != nil && err == nil {
+			err = cerr
+		}
+	}
+
+	return err
+}
+
+// OpenFileWithPosition opens filename and resumes reading from pos.
+//   - If filename is the file that pos was saved from, it is read from pos.Offset
+//   - If filename has been rotated, the rest of the rotated file (e.g. filename.1) is read first, and then filename is read from the beginning
+//   - If filename has been truncated, or the rotated file is not found, filename is read from the beginning
+//
+// The returned PositionTracker is nil if filename is compressed or not a regular file,
+// then the caller must skip pos.Offset bytes by itself.
+func OpenFileWithPosition(filename string, pos *Position) (io.ReadCloser, *PositionTracker, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	fi, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, nil, err
+	}
+
+	compressed, err := isCompressed(f)
+	if err != nil {
+		f.Close()
+		return nil, nil, err
+	}
+
+	if !fi.Mode().IsRegular() || compressed {
+		return NewDecompressReader(f), nil, nil
+	}
+
+	pr := &positionReader{
+		closers: []io.Closer{f},
+	}
+
+	var offset int64
+	var rotated io.Reader
+	if pos.identifies(f, fi) {
+		offset = pos.Offset
+	} else if rf, rfi := findRotatedFile(filename, pos); rf != nil {
+		rotated = io.NewSectionReader(rf, pos.Offset, rfi.Size()-pos.Offset)
+		pr.closers = append(pr.closers, rf)
+	}
+
+	_, err = f.Seek(offset, io.SeekStart)
+	if err != nil {
+		pr.Close()
+		return nil, nil, err
+	}
+
+	pt := &PositionTracker{
+		file:   f,
+		offset: offset,
+	}
+
+	pr.Reader = pt
+	if rotated != nil {
+		pr.Reader = io.MultiReader(rotated, pt)
+	}
+
+	return pr, pt, nil
+}
+
+// findRotatedFile finds the file that pos was saved from, in the files whose name starts with the base name of filename
+// (e.g. access.log.1, access.log-20060102). The file that has the same inode is preferred.
+func findRotatedFile(filename string, pos *Position) (*os.File, os.FileInfo) {
+	if pos.HeadChecksum == "" {
+		return nil, nil
+	}
+
+	dir, base := filepath.Split(filename)
+	if dir == "" {
+		dir = "."
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, nil
+	}
+
+	type candidate struct {
+		file      *os.File
+		fi        os.FileInfo
+		sameInode bool
+	}
+
+	candidates := make([]candidate, 0)
+	for _, e := range entries {
+		if e.Name() == base || !strings.HasPrefix(e.Name(), base) || !e.Type().IsRegular() {
+			continue
+		}
+
+		f, err := os.Open(filepath.Join(dir, e.Name()))
+		if err != nil {
+			continue
+		}
+
+		fi, err := f.Stat()
+		if err != nil || fi.Size() < pos.Offset {
+			f.Close()
+			continue
+		}
+
+		checksum, err := headChecksum(f, pos.HeadSize)
+		if err != nil || checksum != pos.HeadChecksum {
+			f.Close()
+			continue
+		}
+
+		inode, dev := fileID(fi)
+		candidates = append(candidates, candidate{
+			file:      f,
+			fi:        fi,
+			sameInode: inode != 0 && inode == pos.Inode && dev == pos.Dev,
+		})
+	}
+
+	if len(candidates) == 0 {
+		return nil, nil
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].sameInode && !candidates[j].sameInode
+	})
+
+	for _, c := range candidates[1:] {
+		c.file.Close()
+	}
+
+	return candidates[0].file, candidates[0].fi
+}
+
+func headChecksum(f *os.File, size int64) (string, error) {
+	buf := make([]byte, size)
+	n, err := f.ReadAt(buf, 0)
+	if err != nil && err != io.EOF {
+		return "", err
+	}
+
+	if int64(n) < size {
+		return "", fmt.Errorf("%s is smaller than %d bytes", f.Name(), size)
+	}
+
+	return fmt.Sprintf("%08x", crc32.ChecksumIEEE(buf)), nil
+}
+
+func isCompressed(f *os.File) (bool, error) {
+	magic := make([]byte, len(zstdMagic))
+	n, err := f.ReadAt(magic, 0)
+	if err != nil && err != io.EOF {
+		return false, err
+	}
+	magic = magic[:n]
+
+	return bytes.HasPrefix(magic, gzipMagic) || bytes.HasPrefix(magic, zstdMagic) || bytes.HasPrefix(magic, bzip2Magic), nil
+}
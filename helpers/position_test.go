@@ -0,0 +1,193 @@
+package helpers
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeFile(t *testing.T, filename, content string) {
+	t.Helper()
+
+	if err := os.WriteFile(filename, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func appendFile(t *testing.T, filename, content string) {
+	t.Helper()
+
+	f, err := os.OpenFile(filename, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	if _, err = f.WriteString(content); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// readWithPosition reads filename from pos to the end, and returns the content and the position saved after reading
+func readWithPosition(t *testing.T, filename string, pos *Position) (string, *Position) {
+	t.Helper()
+
+	r, pt, err := OpenFileWithPosition(filename, pos)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+
+	b, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if pt == nil {
+		t.Fatal("the position tracker of the regular file must not be nil")
+	}
+
+	newPos, err := pt.Position()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return string(b), newPos
+}
+
+func TestOpenFileWithPosition(t *testing.T) {
+	tempDir := t.TempDir()
+	log := filepath.Join(tempDir, "access.log")
+
+	writeFile(t, log, "line1\nline2\n")
+	got, pos := readWithPosition(t, log, &Position{})
+	if got != "line1\nline2\n" {
+		t.Fatalf("want the whole file, got: %q", got)
+	}
+
+	appendFile(t, log, "line3\n")
+	got, pos = readWithPosition(t, log, pos)
+	if got != "line3\n" {
+		t.Fatalf("want the appended line, got: %q", got)
+	}
+
+	if pos.Offset != int64(len("line1\nline2\nline3\n")) {
+		t.Errorf("want the offset at the end, got: %d", pos.Offset)
+	}
+}
+
+func TestOpenFileWithPositionRenameRotation(t *testing.T) {
+	tempDir := t.TempDir()
+	log := filepath.Join(tempDir, "access.log")
+
+	writeFile(t, log, "line1\nline2\n")
+	_, pos := readWithPosition(t, log, &Position{})
+
+	// the lines appended before the rotation are read from the rotated file
+	appendFile(t, log, "line3\n")
+	if err := os.Rename(log, log+".1"); err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, log, "line4\n")
+
+	got, pos := readWithPosition(t, log, pos)
+	if got != "line3\nline4\n" {
+		t.Fatalf("want the rest of the rotated file and the new file, got: %q", got)
+	}
+
+	// the position moves to the new file
+	appendFile(t, log, "line5\n")
+	got, _ = readWithPosition(t, log, pos)
+	if got != "line5\n" {
+		t.Fatalf("want the line appended to the new file, got: %q", got)
+	}
+}
+
+func TestOpenFileWithPositionCopyTruncate(t *testing.T) {
+	tempDir := t.TempDir()
+	log := filepath.Join(tempDir, "access.log")
+
+	writeFile(t, log, "line1\nline2\n")
+	_, pos := readWithPosition(t, log, &Position{})
+
+	appendFile(t, log, "line3\n")
+	b, err := os.ReadFile(log)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// copytruncate copies the file, and truncates the original file that keeps the inode
+	writeFile(t, log+"-20060102", string(b))
+	if err = os.Truncate(log, 0); err != nil {
+		t.Fatal(err)
+	}
+	appendFile(t, log, "line4\n")
+
+	got, _ := readWithPosition(t, log, pos)
+	if got != "line3\nline4\n" {
+		t.Fatalf("want the rest of the copied file and the truncated file, got: %q", got)
+	}
+}
+
+func TestOpenFileWithPositionTruncated(t *testing.T) {
+	tempDir := t.TempDir()
+	log := filepath.Join(tempDir, "access.log")
+
+	writeFile(t, log, "line1\nline2\nline3\n")
+	_, pos := readWithPosition(t, log, &Position{})
+
+	// the file is truncated below the saved offset without the rotated file
+	writeFile(t, log, "new1\n")
+
+	got, pos := readWithPosition(t, log, pos)
+	if got != "new1\n" {
+		t.Fatalf("want the truncated file from the beginning, got: %q", got)
+	}
+
+	if pos.Offset != int64(len("new1\n")) {
+		t.Errorf("want the offset of the truncated file, got: %d", pos.Offset)
+	}
+}
+
+func TestReadPositionLegacy(t *testing.T) {
+	tempDir := t.TempDir()
+	log := filepath.Join(tempDir, "access.log")
+	writeFile(t, log, "line1\nline2\n")
+
+	// the pos file of the older versions has only the number of bytes
+	pos, err := ReadPosition(strings.NewReader("6\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if pos.Offset != 6 || pos.HeadChecksum != "" {
+		t.Fatalf("want only the offset, got: %+v", pos)
+	}
+
+	got, newPos := readWithPosition(t, log, pos)
+	if got != "line2\n" {
+		t.Fatalf("want the file from the offset, got: %q", got)
+	}
+
+	// the position is saved in the current format
+	var buf bytes.Buffer
+	if err = newPos.Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	saved, err := ReadPosition(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if saved.Offset != 12 || saved.HeadChecksum == "" {
+		t.Errorf("want the offset and the checksum, got: %+v", saved)
+	}
+
+	empty, err := ReadPosition(strings.NewReader(""))
+	if err != nil || empty.Offset != 0 {
+		t.Errorf("want the zero offset of the empty pos file, got: %+v, %v", empty, err)
+	}
+}
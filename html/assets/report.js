@@ -0,0 +1,156 @@
+// The table of the profile results that can be searched, sorted by multiple columns with the shift key, and paginated.
+// It reads alpReport, which has the columns, the rows, the CSS classes of the cells and the pagination limit.
+(function (report) {
+  var container = document.getElementById("alpTable");
+  var rows = report.rows.map(function (cells, i) {
+    return { cells: cells, classes: report.classes ? report.classes[i] : null };
+  });
+  var sorts = [];
+  var query = "";
+  var page = 0;
+
+  // the values with the differences, e.g. "0.123 (+0.010)", are sorted by the values
+  function sortValue(v) {
+    if (typeof v === "number") {
+      return v;
+    }
+
+    var n = parseFloat(v);
+    return isNaN(n) ? String(v) : n;
+  }
+
+  function compare(a, b) {
+    for (var i = 0; i < sorts.length; i++) {
+      var x = sortValue(a.cells[sorts[i].column]);
+      var y = sortValue(b.cells[sorts[i].column]);
+      if (typeof x !== typeof y) {
+        x = String(x);
+        y = String(y);
+      }
+
+      if (x < y) {
+        return sorts[i].desc ? 1 : -1;
+      } else if (x > y) {
+        return sorts[i].desc ? -1 : 1;
+      }
+    }
+
+    return 0;
+  }
+
+  function filtered() {
+    var q = query.toLowerCase();
+    var result = rows.filter(function (row) {
+      return q === "" || row.cells.some(function (v) {
+        return String(v).toLowerCase().indexOf(q) >= 0;
+      });
+    });
+
+    if (sorts.length > 0) {
+      result = result.slice().sort(compare);
+    }
+
+    return result;
+  }
+
+  function toggleSort(column, multi) {
+    var current = sorts.filter(function (s) { return s.column === column; })[0];
+    if (!multi) {
+      sorts = current ? [current] : [];
+    }
+
+    if (!current) {
+      sorts.push({ column: column, desc: false });
+    } else if (!current.desc) {
+      current.desc = true;
+    } else {
+      sorts = sorts.filter(function (s) { return s !== current; });
+    }
+
+    render();
+  }
+
+  var search = document.createElement("input");
+  search.className = "alp-search";
+  search.type = "search";
+  search.placeholder = "Type a keyword...";
+  search.addEventListener("input", function () {
+    query = search.value;
+    page = 0;
+    render();
+  });
+
+  var wrapper = document.createElement("div");
+  wrapper.className = "alp-table-wrapper";
+  var pagination = document.createElement("div");
+  pagination.className = "alp-pagination";
+
+  container.appendChild(search);
+  container.appendChild(wrapper);
+  container.appendChild(pagination);
+
+  function button(label, disabled, onclick) {
+    var b = document.createElement("button");
+    b.textContent = label;
+    b.disabled = disabled;
+    b.addEventListener("click", onclick);
+    return b;
+  }
+
+  function render() {
+    var result = filtered();
+    var limit = report.paginationLimit > 0 ? report.paginationLimit : result.length;
+    var pages = Math.max(1, Math.ceil(result.length / limit));
+    page = Math.min(page, pages - 1);
+    var start = page * limit;
+    var visible = result.slice(start, start + limit);
+
+    var table = document.createElement("table");
+    table.className = "alp-table";
+
+    var head = table.createTHead().insertRow();
+    report.columns.forEach(function (name, i) {
+      var th = document.createElement("th");
+      th.textContent = name;
+      th.title = "Click to sort, and shift-click to sort by multiple columns";
+      var s = sorts.filter(function (s) { return s.column === i; })[0];
+      if (s) {
+        th.className = s.desc ? "desc" : "asc";
+      }
+      th.addEventListener("click", function (e) { toggleSort(i, e.shiftKey); });
+      head.appendChild(th);
+    });
+
+    var body = table.createTBody();
+    visible.forEach(function (row) {
+      var tr = body.insertRow();
+      row.cells.forEach(function (v, i) {
+        var td = tr.insertCell();
+        td.textContent = v;
+        if (row.classes && row.classes[i]) {
+          td.className = row.classes[i];
+        }
+      });
+    });
+
+    wrapper.replaceChildren(table);
+
+    pagination.replaceChildren();
+    if (result.length === 0) {
+      pagination.textContent = "No matching records found";
+      return;
+    }
+
+    var summary = document.createElement("span");
+    summary.textContent = "Showing " + (start + 1) + " to " + (start + visible.length) + " of " + result.length + " results ";
+    pagination.appendChild(summary);
+
+    if (pages > 1) {
+      pagination.appendChild(button("Previous", page === 0, function () { page--; render(); }));
+      pagination.appendChild(document.createTextNode(" " + (page + 1) + " / " + pages + " "));
+      pagination.appendChild(button("Next", page === pages - 1, function () { page++; render(); }));
+    }
+  }
+
+  render();
+})(alpReport);
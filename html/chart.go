@@ -0,0 +1,198 @@
+package html
+
+import (
+	"fmt"
+	"html/template"
+	"math"
+	"strings"
+)
+
+// The charts are rendered as inline SVG, so that the report needs no JavaScript libraries
+const (
+	chartWidth   = 360
+	chartHeight  = 180
+	marginLeft   = 48
+	marginRight  = 12
+	marginTop    = 28
+	marginBottom = 28
+
+	maxLabelLen = 40
+)
+
+func escape(s string) string {
+	return template.HTMLEscapeString(s)
+}
+
+func formatValue(v float64) string {
+	if v == math.Trunc(v) {
+		return fmt.Sprintf("%.0f", v)
+	}
+
+	return fmt.Sprintf("%.3f", v)
+}
+
+// truncate shortens the long label, such as the URI, to fit in the chart
+func truncate(s string) string {
+	r := []rune(s)
+	if len(r) <= maxLabelLen {
+		return s
+	}
+
+	return string(r[:maxLabelLen-1]) + "…"
+}
+
+func svgStart(b *strings.Builder, width, height int, title string) {
+	fmt.Fprintf(b, `<svg class="alp-chart" xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d">`, width, height, width, height)
+	fmt.Fprintf(b, `<text class="title" x="8" y="16">%s</text>`, escape(truncate(title)))
+}
+
+// axes draws the x and y axes, and the labels of the minimum and the maximum of each axis
+func axes(b *strings.Builder, width int, xMin, xMax, yMax string) {
+	x0, y0 := marginLeft, chartHeight-marginBottom
+	fmt.Fprintf(b, `<line class="axis" x1="%d" y1="%d" x2="%d" y2="%d"/>`, x0, y0, width-marginRight, y0)
+	fmt.Fprintf(b, `<line class="axis" x1="%d" y1="%d" x2="%d" y2="%d"/>`, x0, marginTop, x0, y0)
+	fmt.Fprintf(b, `<text x="%d" y="%d" text-anchor="start">%s</text>`, x0, y0+16, escape(xMin))
+	fmt.Fprintf(b, `<text x="%d" y="%d" text-anchor="end">%s</text>`, width-marginRight, y0+16, escape(xMax))
+	fmt.Fprintf(b, `<text x="%d" y="%d" text-anchor="end">%s</text>`, x0-4, marginTop+4, escape(yMax))
+	fmt.Fprintf(b, `<text x="%d" y="%d" text-anchor="end">0</text>`, x0-4, y0)
+}
+
+// HistogramBin is a bar of the histogram, which is the number of the values between Min and Max
+type HistogramBin struct {
+	Min   float64
+	Max   float64
+	Count int
+}
+
+// Histogram renders the histogram of the bins, which are divided by the caller, e.g. stats.NewHistogramBuckets
+func Histogram(title string, bins []HistogramBin) template.HTML {
+	if len(bins) == 0 {
+		return ""
+	}
+
+	maxCount := 0
+	for _, bin := range bins {
+		if bin.Count > maxCount {
+			maxCount = bin.Count
+		}
+	}
+
+	var b strings.Builder
+	svgStart(&b, chartWidth, chartHeight, title)
+
+	plotWidth := float64(chartWidth - marginLeft - marginRight)
+	plotHeight := float64(chartHeight - marginTop - marginBottom)
+	barWidth := plotWidth / float64(len(bins))
+	for i, bin := range bins {
+		h := plotHeight * float64(bin.Count) / float64(maxCount)
+		fmt.Fprintf(&b, `<rect class="bar" x="%.1f" y="%.1f" width="%.1f" height="%.1f"><title>%s - %s: %d</title></rect>`,
+			float64(marginLeft)+barWidth*float64(i)+0.5, float64(marginTop)+plotHeight-h, math.Max(barWidth-1, 1), h,
+			formatValue(bin.Min), formatValue(bin.Max), bin.Count)
+	}
+
+	axes(&b, chartWidth, formatValue(bins[0].Min), formatValue(bins[len(bins)-1].Max), fmt.Sprint(maxCount))
+	b.WriteString(`</svg>`)
+
+	return template.HTML(b.String())
+}
+
+// LineChart renders the values in order, and the labels are shown as the tooltips and at both ends of the x axis
+func LineChart(title string, labels []string, values []float64) template.HTML {
+	if len(values) == 0 {
+		return ""
+	}
+
+	max := 0.0
+	for _, v := range values {
+		max = math.Max(max, v)
+	}
+	if max == 0 {
+		max = 1
+	}
+
+	// the line chart is twice as wide as the other charts to show the changes over time
+	width := chartWidth * 2
+	plotWidth := float64(width - marginLeft - marginRight)
+	plotHeight := float64(chartHeight - marginTop - marginBottom)
+	step := plotWidth
+	if len(values) > 1 {
+		step = plotWidth / float64(len(values)-1)
+	}
+
+	points := make([]string, len(values))
+	for i, v := range values {
+		points[i] = fmt.Sprintf("%.1f,%.1f", float64(marginLeft)+step*float64(i), float64(marginTop)+plotHeight-plotHeight*v/max)
+	}
+	bottom := float64(marginTop) + plotHeight
+
+	var b strings.Builder
+	svgStart(&b, width, chartHeight, title)
+	fmt.Fprintf(&b, `<polygon class="area" points="%.1f,%.1f %s %.1f,%.1f"/>`, float64(marginLeft), bottom, strings.Join(points, " "), float64(marginLeft)+step*float64(len(values)-1), bottom)
+	fmt.Fprintf(&b, `<polyline class="line" points="%s"/>`, strings.Join(points, " "))
+
+	// the transparent bars show the tooltips of the points
+	for i, v := range values {
+		fmt.Fprintf(&b, `<rect x="%.1f" y="%d" width="%.1f" height="%.1f" fill="transparent"><title>%s: %s</title></rect>`,
+			float64(marginLeft)+step*(float64(i)-0.5), marginTop, step, plotHeight, escape(labels[i]), formatValue(v))
+	}
+
+	axes(&b, width, labels[0], labels[len(labels)-1], formatValue(max))
+	b.WriteString(`</svg>`)
+
+	return template.HTML(b.String())
+}
+
+// StackedBars renders a horizontal bar of each label, which is divided into the ratios of the values of the series.
+// The classes are the CSS classes of the series.
+func StackedBars(title string, labels, series, classes []string, values [][]int) template.HTML {
+	if len(labels) == 0 {
+		return ""
+	}
+
+	const (
+		labelWidth = 280
+		barWidth   = 360
+		rowHeight  = 18
+		legendTop  = 24
+	)
+	top := legendTop + 20
+	height := top + rowHeight*len(labels) + 8
+
+	var b strings.Builder
+	svgStart(&b, labelWidth+barWidth+marginRight, height, title)
+
+	for i, name := range series {
+		x := 8 + i*64
+		fmt.Fprintf(&b, `<rect class="%s" x="%d" y="%d" width="10" height="10"/>`, classes[i], x, legendTop)
+		fmt.Fprintf(&b, `<text x="%d" y="%d">%s</text>`, x+14, legendTop+9, escape(name))
+	}
+
+	for i, label := range labels {
+		y := top + rowHeight*i
+		fmt.Fprintf(&b, `<text x="8" y="%d">%s</text>`, y+12, escape(truncate(label)))
+
+		total := 0
+		for _, v := range values[i] {
+			total += v
+		}
+		if total == 0 {
+			continue
+		}
+
+		x := float64(labelWidth)
+		for j, v := range values[i] {
+			if v == 0 {
+				continue
+			}
+
+			w := float64(barWidth) * float64(v) / float64(total)
+			fmt.Fprintf(&b, `<rect class="%s" x="%.1f" y="%d" width="%.1f" height="%d"><title>%s %s: %d (%.1f%%)</title></rect>`,
+				classes[j], x, y+2, w, rowHeight-4, escape(label), escape(series[j]), v, float64(v)/float64(total)*100)
+			x += w
+		}
+	}
+
+	b.WriteString(`</svg>`)
+
+	return template.HTML(b.String())
+}
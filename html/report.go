@@ -0,0 +1,133 @@
+package html
+
+import (
+	"bytes"
+	_ "embed"
+	"html/template"
+	"strconv"
+)
+
+// The assets are embedded in the report, so that it can be opened offline
+var (
+	//go:embed assets/report.css
+	reportCSS string
+	//go:embed assets/report.js
+	reportJS string
+)
+
+const tplReport = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="UTF-8">
+<title>{{ .Title }}</title>
+<style>
+{{ .CSS }}
+</style>
+</head>
+<body>
+<h1>{{ .Title }}</h1>
+{{- if .Legend }}
+<p class="alp-legend"><span class="regression">&#9632; Regression</span><span class="improvement">&#9632; Improvement</span></p>
+{{- end }}
+<div id="alpTable"></div>
+{{- range .Sections }}
+<h2>{{ .Title }}</h2>
+{{- if .Note }}
+<p>{{ .Note }}</p>
+{{- end }}
+<div class="alp-charts">
+{{- range .Charts }}
+{{ . }}
+{{- end }}
+</div>
+{{- end }}
+<script>
+var alpReport = {
+  columns: {{ .Columns }},
+  rows: {{ .Rows }},
+  classes: {{ .Classes }},
+  paginationLimit: {{ .PaginationLimit }}
+};
+{{ .JS }}
+</script>
+</body>
+</html>`
+
+const (
+	ClassRegression  = "regression"
+	ClassImprovement = "improvement"
+)
+
+// Section is the charts under the heading
+type Section struct {
+	Title string
+	// Note is the description of the section, e.g. the reason why some charts are omitted
+	Note   string
+	Charts []template.HTML
+}
+
+// Report is the HTML report of the profile results, which has the table and the sections of the charts
+type Report struct {
+	Title   string
+	Columns []string
+	Rows    [][]string
+	// Classes are the CSS classes of the cells, e.g. ClassRegression and ClassImprovement of the diff, and is nil if there are none
+	Classes         [][]string
+	PaginationLimit int
+	Sections        []*Section
+}
+
+// values converts the cells to the numbers if possible, so that they are sorted as the numbers
+func values(rows [][]string) [][]interface{} {
+	columnValues := make([][]interface{}, 0, len(rows))
+	for _, row := range rows {
+		values := make([]interface{}, 0, len(row))
+		for _, col := range row {
+			vf, err := strconv.ParseFloat(col, 64)
+			if err == nil {
+				values = append(values, vf)
+				continue
+			}
+
+			values = append(values, col)
+		}
+		columnValues = append(columnValues, values)
+	}
+
+	return columnValues
+}
+
+// Render renders the report with the embedded CSS and JavaScript
+func (r *Report) Render() (string, error) {
+	t, err := template.New("report").Parse(tplReport)
+	if err != nil {
+		return "", err
+	}
+
+	legend := false
+	for _, row := range r.Classes {
+		for _, class := range row {
+			if class != "" {
+				legend = true
+			}
+		}
+	}
+
+	data := struct {
+		*Report
+		Rows   [][]interface{}
+		Legend bool
+		CSS    template.CSS
+		JS     template.JS
+	}{
+		Report: r,
+		Rows:   values(r.Rows),
+		Legend: legend,
+		CSS:    template.CSS(reportCSS),
+		JS:     template.JS(reportJS),
+	}
+
+	var buf bytes.Buffer
+	err = t.Execute(&buf, data)
+	return buf.String(), err
+}
@@ -140,15 +140,39 @@ func DummyOptions(sort string) *options.Options {
 		Limit:                   100,
 		NoHeaders:               false,
 		ShowFooters:             false,
-		MatchingGroups: []string{
-			"/foo/.+",
+		MatchingGroups: []*options.MatchingGroup{
+			{
+				Pattern: "/foo/.+",
+			},
+			{
+				Name:    "bar",
+				Pattern: "/bar/.+",
+				Methods: []string{"GET", "POST"},
+			},
 		},
-		Filters:         ".Uri == '/foo/bar'",
-		Output:          "count,uri,min,max",
-		PosFile:         "/path/to/pos",
-		NoSavePos:       false,
-		Percentiles:     []int{1, 5},
-		PaginationLimit: 10,
+		Filters:             ".Uri == '/foo/bar'",
+		Output:              "count,uri,min,max",
+		PosFile:             "/path/to/pos",
+		NoSavePos:           false,
+		Percentiles:         []int{1, 5},
+		StatusCodes:         []int{404, 499},
+		GroupBy:             []string{"host"},
+		PaginationLimit:     10,
+		PercentileEstimator: "sketch",
+		PercentileAccuracy:  0.05,
+		ApdexThreshold:      0.3,
+		SignificanceLevel:   0.01,
+		Workers:             2,
+		Timeline:            "1m",
+		Follow:              true,
+		FollowInterval:      "1s",
+		MetricsListen:       ":9101",
+		MetricsBuckets:      "0.1,1",
+		OpenAPI:             "/path/to/openapi.yaml",
+		Histogram:           true,
+		HistogramBuckets:    20,
+		HistogramScale:      "log",
+		HistogramUri:        "^/foo",
 		LTSV: &options.LTSVOptions{
 			UriLabel:     "u",
 			MethodLabel:  "m",
@@ -156,26 +180,29 @@ func DummyOptions(sort string) *options.Options {
 			ApptimeLabel: "a",
 			ReqtimeLabel: "r",
 			SizeLabel:    "sz",
+			ReqsizeLabel: "rsz",
 			StatusLabel:  "st",
 		},
 		JSON: &options.JSONOptions{
-			UriKey:          "u",
-			MethodKey:       "m",
-			TimeKey:         "t",
-			ResponseTimeKey: "res",
-			RequestTimeKey:  "req",
-			BodyBytesKey:    "b",
-			StatusKey:       "s",
+			UriKey:              "u",
+			MethodKey:           "m",
+			TimeKey:             "t",
+			ResponseTimeKey:     "res",
+			RequestTimeKey:      "req",
+			BodyBytesKey:        "b",
+			RequestBodyBytesKey: "rb",
+			StatusKey:           "s",
 		},
 		Regexp: &options.RegexpOptions{
-			Pattern:            "dummy pattern",
-			UriSubexp:          "u",
-			MethodSubexp:       "m",
-			TimeSubexp:         "t",
-			ResponseTimeSubexp: "res",
-			RequestTimeSubexp:  "req",
-			BodyBytesSubexp:    "b",
-			StatusSubexp:       "s",
+			Pattern:                "dummy pattern",
+			UriSubexp:              "u",
+			MethodSubexp:           "m",
+			TimeSubexp:             "t",
+			ResponseTimeSubexp:     "res",
+			RequestTimeSubexp:      "req",
+			BodyBytesSubexp:        "b",
+			RequestBodyBytesSubexp: "rb",
+			StatusSubexp:           "s",
 		},
 		Pcap: &options.PcapOptions{
 			ServerIPs: []string{
@@ -192,6 +219,26 @@ func DummyOptions(sort string) *options.Options {
 			Sort:    "restime",
 			Reverse: false,
 		},
+		SuggestGroups: &options.SuggestGroupsOptions{
+			MinCardinality: 5,
+		},
+		Check: &options.CheckOptions{
+			Rules:    dummyCheckRules(),
+			Baseline: "baseline.yaml",
+		},
+	}
+}
+
+func dummyCheckRules() []*options.CheckRule {
+	max := 0.5
+	return []*options.CheckRule{
+		{
+			Name:    "p99",
+			Uri:     "^/api/",
+			Methods: []string{"GET"},
+			Metric:  "p99",
+			Max:     &max,
+		},
 	}
 }
 
@@ -211,16 +258,33 @@ func DummyOverwrittenOptions(sort string) *options.Options {
 		Limit:                   200,
 		NoHeaders:               true,
 		ShowFooters:             true,
-		MatchingGroups: []string{
+		MatchingGroups: options.NewMatchingGroups([]string{
 			"/foo/bar/.+",
 			"/bar/.+",
-		},
-		Filters:         ".Status == 200",
-		Output:          "uri,avg",
-		PosFile:         "/path/to/overwritten/pos",
-		NoSavePos:       true,
-		Percentiles:     []int{5, 9},
-		PaginationLimit: 20,
+		}),
+		Filters:             ".Status == 200",
+		Output:              "uri,avg",
+		PosFile:             "/path/to/overwritten/pos",
+		NoSavePos:           true,
+		Percentiles:         []int{5, 9},
+		StatusCodes:         []int{502, 504},
+		GroupBy:             []string{"host", "upstream_addr"},
+		PaginationLimit:     20,
+		PercentileEstimator: "exact",
+		PercentileAccuracy:  0.02,
+		ApdexThreshold:      1.2,
+		SignificanceLevel:   0.1,
+		Workers:             4,
+		Timeline:            "10s",
+		Follow:              true,
+		FollowInterval:      "2s",
+		MetricsListen:       "localhost:9102",
+		MetricsBuckets:      "0.5,5",
+		OpenAPI:             "/path/to/overwritten/openapi.yaml",
+		Histogram:           true,
+		HistogramBuckets:    5,
+		HistogramScale:      "linear",
+		HistogramUri:        "^/bar",
 		LTSV: &options.LTSVOptions{
 			UriLabel:     "u2",
 			MethodLabel:  "m2",
@@ -228,25 +292,28 @@ func DummyOverwrittenOptions(sort string) *options.Options {
 			ApptimeLabel: "a2",
 			ReqtimeLabel: "r2",
 			SizeLabel:    "sz2",
+			ReqsizeLabel: "rsz2",
 			StatusLabel:  "st2",
 		},
 		JSON: &options.JSONOptions{
-			UriKey:          "u2",
-			MethodKey:       "m2",
-			TimeKey:         "t2",
-			ResponseTimeKey: "res2",
-			RequestTimeKey:  "req2",
-			BodyBytesKey:    "b2",
-			StatusKey:       "s2",
+			UriKey:              "u2",
+			MethodKey:           "m2",
+			TimeKey:             "t2",
+			ResponseTimeKey:     "res2",
+			RequestTimeKey:      "req2",
+			BodyBytesKey:        "b2",
+			RequestBodyBytesKey: "rb2",
+			StatusKey:           "s2",
 		},
 		Regexp: &options.RegexpOptions{
-			UriSubexp:          "u2",
-			MethodSubexp:       "m2",
-			TimeSubexp:         "t2",
-			ResponseTimeSubexp: "res2",
-			RequestTimeSubexp:  "req2",
-			BodyBytesSubexp:    "b2",
-			StatusSubexp:       "s2",
+			UriSubexp:              "u2",
+			MethodSubexp:           "m2",
+			TimeSubexp:             "t2",
+			ResponseTimeSubexp:     "res2",
+			RequestTimeSubexp:      "req2",
+			BodyBytesSubexp:        "b2",
+			RequestBodyBytesSubexp: "rb2",
+			StatusSubexp:           "s2",
 		},
 		Pcap: &options.PcapOptions{
 			ServerIPs: []string{
@@ -264,6 +331,13 @@ func DummyOverwrittenOptions(sort string) *options.Options {
 			Sort:    "bytes",
 			Reverse: true,
 		},
+		SuggestGroups: &options.SuggestGroupsOptions{
+			MinCardinality: 20,
+		},
+		Check: &options.CheckOptions{
+			Rules:    dummyCheckRules(),
+			Baseline: "baseline2.yaml",
+		},
 	}
 }
 
@@ -280,7 +354,16 @@ noheaders: {{ .NoHeaders }}
 show_footers: {{ .ShowFooters }}
 matching_groups:
 {{ range .MatchingGroups }}
-  - {{ . }}
+{{ if .Name }}
+  - name: {{ .Name }}
+    pattern: {{ .Pattern }}
+    methods:
+{{ range .Methods }}
+      - {{ . }}
+{{ end }}
+{{ else }}
+  - {{ .Pattern }}
+{{ end }}
 {{ end }}
 filters: {{ .Filters }}
 output: {{ .Output }}
@@ -291,7 +374,30 @@ percentiles:
 {{ range .Percentiles }}
   - {{ . }}
 {{ end }}
+status_codes:
+{{ range .StatusCodes }}
+  - {{ . }}
+{{ end }}
+group_by:
+{{ range .GroupBy }}
+  - {{ . }}
+{{ end }}
 pagination_limit: {{ .PaginationLimit }}
+percentile_estimator: {{ .PercentileEstimator }}
+percentile_accuracy: {{ .PercentileAccuracy }}
+apdex_threshold: {{ .ApdexThreshold }}
+significance_level: {{ .SignificanceLevel }}
+workers: {{ .Workers }}
+timeline: {{ .Timeline }}
+follow: {{ .Follow }}
+follow_interval: {{ .FollowInterval }}
+metrics_listen: {{ .MetricsListen }}
+metrics_buckets: {{ .MetricsBuckets }}
+openapi: {{ .OpenAPI }}
+histogram: {{ .Histogram }}
+histogram_buckets: {{ .HistogramBuckets }}
+histogram_scale: {{ .HistogramScale }}
+histogram_uri: {{ .HistogramUri }}
 ltsv:
   uri_label: {{ .LTSV.UriLabel }}
   method_label: {{ .LTSV.MethodLabel }}
@@ -299,6 +405,7 @@ ltsv:
   apptime_label: {{ .LTSV.ApptimeLabel }}
   reqtime_label: {{ .LTSV.ReqtimeLabel }}
   size_label: {{ .LTSV.SizeLabel }}
+  reqsize_label: {{ .LTSV.ReqsizeLabel }}
   status_label: {{ .LTSV.StatusLabel }}
 json:
   uri_key: {{ .JSON.UriKey }}
@@ -307,6 +414,7 @@ json:
   response_time_key: {{ .JSON.ResponseTimeKey }}
   request_time_key: {{ .JSON.RequestTimeKey }}
   body_bytes_key: {{ .JSON.BodyBytesKey }}
+  request_body_bytes_key: {{ .JSON.RequestBodyBytesKey }}
   status_key: {{ .JSON.StatusKey }}
 regexp:
   pattern: {{ .Regexp.Pattern }}
@@ -316,6 +424,7 @@ regexp:
   response_time_subexp: {{ .Regexp.ResponseTimeSubexp }}
   request_time_subexp: {{ .Regexp.RequestTimeSubexp }}
   body_bytes_subexp: {{ .Regexp.BodyBytesSubexp }}
+  request_body_bytes_subexp: {{ .Regexp.RequestBodyBytesSubexp }}
   status_subexp: {{ .Regexp.StatusSubexp }}
 pcap:
   server_ips:
@@ -331,6 +440,21 @@ count:
 topN:
   sort: {{ .TopN.Sort }}
   reverse: {{ .TopN.Reverse }}
+suggest_groups:
+  min_cardinality: {{ .SuggestGroups.MinCardinality }}
+check:
+  baseline: {{ .Check.Baseline }}
+  rules:
+{{ range .Check.Rules }}
+    - name: {{ .Name }}
+      uri: '{{ .Uri }}'
+      methods:
+{{ range .Methods }}
+        - {{ . }}
+{{ end }}
+      metric: {{ .Metric }}
+      max: {{ .Max }}
+{{ end }}
 `
 	t, err := template.New("dummy_config").Parse(configTmpl)
 	if err != nil {
@@ -0,0 +1,33 @@
+package testutil
+
+import (
+	"bytes"
+	"io"
+	"os"
+)
+
+// CaptureStdout returns what f writes to os.Stdout
+func CaptureStdout(f func() error) (string, error) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		return "", err
+	}
+	defer r.Close()
+
+	stdout := os.Stdout
+	os.Stdout = w
+
+	// the pipe is read while f writes, so that f does not block on the full pipe
+	out := make(chan string)
+	go func() {
+		var buf bytes.Buffer
+		io.Copy(&buf, r)
+		out <- buf.String()
+	}()
+
+	err = f()
+	os.Stdout = stdout
+	w.Close()
+
+	return <-out, err
+}
@@ -3,8 +3,19 @@ package testutil
 import (
 	"fmt"
 	"strings"
+
+	"github.com/tkuchiki/alp/options"
 )
 
 func IntSliceToString(si []int) string {
 	return strings.Trim(strings.Replace(fmt.Sprint(si), " ", ",", -1), "[]")
 }
+
+func MatchingGroupsToString(groups []*options.MatchingGroup) string {
+	patterns := make([]string, 0, len(groups))
+	for _, g := range groups {
+		patterns = append(patterns, g.Pattern)
+	}
+
+	return strings.Join(patterns, ",")
+}
@@ -1,7 +1,6 @@
 package log_reader
 
 import (
-	"bufio"
 	"fmt"
 	"io"
 	"net/url"
@@ -24,13 +23,14 @@ type AccessLog struct {
 }
 
 type AccessLogReader struct {
-	logs      []*AccessLog
-	options   *options.Options
-	outWriter io.Writer
-	errWriter io.Writer
-	inReader  *os.File
-	printer   *Printer
-	numOfTopN int
+	logs       []*AccessLog
+	options    *options.Options
+	outWriter  io.Writer
+	errWriter  io.Writer
+	inReader   *os.File
+	printer    *Printer
+	numOfTopN  int
+	posTracker *helpers.PositionTracker
 }
 
 func NewAccessLogReader(outw, errw io.Writer, opts *options.Options, numOfTopN int) *AccessLogReader {
@@ -55,17 +55,16 @@ func (a *AccessLogReader) SetInReader(f *os.File) {
 	a.inReader = f
 }
 
-func (a *AccessLogReader) Open(filename string) (*os.File, error) {
-	var f *os.File
-	var err error
+func (a *AccessLogReader) Open(filename string) (io.ReadCloser, error) {
+	if filename != "" && a.options.PosFile != "" {
+		return a.openWithPosFile(filename)
+	}
 
 	if filename != "" {
-		f, err = os.Open(filename)
-	} else {
-		f = a.inReader
+		return helpers.OpenFiles(filename)
 	}
 
-	return f, err
+	return helpers.NewDecompressReader(a.inReader), nil
 }
 
 func (a *AccessLogReader) OpenPosFile(filename string) (*os.File, error) {
@@ -73,13 +72,68 @@ func (a *AccessLogReader) OpenPosFile(filename string) (*os.File, error) {
 }
 
 func (a *AccessLogReader) ReadPosFile(f *os.File) (int, error) {
-	reader := bufio.NewReader(f)
-	pos, _, err := reader.ReadLine()
+	pos, err := helpers.ReadPosition(f)
 	if err != nil {
 		return 0, err
 	}
 
-	return helpers.StringToInt(string(pos))
+	return int(pos.Offset), nil
+}
+
+// openWithPosFile opens the file that resumes from the position file, even if the file has been rotated.
+// Multiple files are read from the beginning, and skipped by the number of bytes in the position file.
+func (a *AccessLogReader) openWithPosFile(filename string) (io.ReadCloser, error) {
+	files, err := helpers.ExpandFiles(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(files) > 1 {
+		return helpers.OpenFiles(filename)
+	}
+
+	pos := &helpers.Position{}
+	posfile, err := os.Open(a.options.PosFile)
+	if err == nil {
+		pos, err = helpers.ReadPosition(posfile)
+		posfile.Close()
+		if err != nil {
+			return nil, err
+		}
+	} else if !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	r, tracker, err := helpers.OpenFileWithPosition(files[0], pos)
+	if err != nil {
+		return nil, err
+	}
+	a.posTracker = tracker
+
+	return r, nil
+}
+
+func (a *AccessLogReader) writePosFile(posfile *os.File, readBytes int) error {
+	pos := &helpers.Position{Offset: int64(readBytes)}
+	if a.posTracker != nil {
+		var err error
+		pos, err = a.posTracker.Position()
+		if err != nil {
+			return err
+		}
+	}
+
+	err := posfile.Truncate(0)
+	if err != nil {
+		return err
+	}
+
+	_, err = posfile.Seek(0, 0)
+	if err != nil {
+		return err
+	}
+
+	return pos.Write(posfile)
 }
 
 func (a *AccessLog) UriWithOptions(decode bool) string {
@@ -124,17 +178,20 @@ func (a *AccessLogReader) ReadAll(parser parsers.Parser) error {
 		}
 		defer posfile.Close()
 
-		pos, err := a.ReadPosFile(posfile)
-		if err != nil && err != io.EOF {
-			return err
-		}
+		// the file opened with the position file has been seeked already
+		if a.posTracker == nil {
+			pos, err := a.ReadPosFile(posfile)
+			if err != nil && err != io.EOF {
+				return err
+			}
 
-		err = parser.Seek(pos)
-		if err != nil {
-			return err
-		}
+			err = parser.Seek(pos)
+			if err != nil {
+				return err
+			}
 
-		parser.SetReadBytes(pos)
+			parser.SetReadBytes(pos)
+		}
 	}
 
 	sts := stats.NewHTTPStats(true, false, false)
@@ -171,8 +228,7 @@ Loop:
 	}
 
 	if !a.options.NoSavePos && a.options.PosFile != "" {
-		posfile.Seek(0, 0)
-		_, err = posfile.Write([]byte(fmt.Sprint(parser.ReadBytes())))
+		err = a.writePosFile(posfile, parser.ReadBytes())
 		if err != nil {
 			return err
 		}
@@ -222,7 +222,13 @@ func (p *Printer) printHTML(logs []*AccessLog) {
 		data = append(data, p.GenerateLine(l, true))
 	}
 
-	content, _ := html.RenderTableWithGridJS("alp", p.headers, data, p.printOptions.paginationLimit)
+	report := &html.Report{
+		Title:           "alp",
+		Columns:         p.headers,
+		Rows:            data,
+		PaginationLimit: p.printOptions.paginationLimit,
+	}
+	content, _ := report.Render()
 	fmt.Println(content)
 }
 
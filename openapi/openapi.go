@@ -0,0 +1,188 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// paramSegment matches the value of the path parameter
+const paramSegment = `[^/]+`
+
+var (
+	pathParamRe = regexp.MustCompile(`\{[^}/]+\}`)
+
+	httpMethods = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+)
+
+// Route is the path template and the methods documented in the OpenAPI / Swagger document
+type Route struct {
+	// Path is the path template such as /users/{id}/posts, including the base path
+	Path string
+	// Methods are the upper case HTTP methods
+	Methods []string
+	re      *regexp.Regexp
+	params  int
+}
+
+// Match reports whether path matches the path template
+func (r *Route) Match(path string) bool {
+	return r.re.MatchString(path)
+}
+
+// HasMethod reports whether the method is documented for the route
+func (r *Route) HasMethod(method string) bool {
+	for _, m := range r.Methods {
+		if m == method {
+			return true
+		}
+	}
+
+	return false
+}
+
+type document struct {
+	Swagger  string `yaml:"swagger" json:"swagger"`
+	OpenAPI  string `yaml:"openapi" json:"openapi"`
+	BasePath string `yaml:"basePath" json:"basePath"`
+	Servers  []struct {
+		URL string `yaml:"url" json:"url"`
+	} `yaml:"servers" json:"servers"`
+	Paths map[string]map[string]interface{} `yaml:"paths" json:"paths"`
+}
+
+// Load reads the OpenAPI 3 or Swagger 2 document of YAML or JSON, and returns the routes
+func Load(filename string) ([]*Route, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	routes, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", filename, err)
+	}
+
+	return routes, nil
+}
+
+// Parse parses the OpenAPI 3 or Swagger 2 document of YAML or JSON.
+// The routes are sorted in the order to match, the routes that have fewer path parameters come first.
+func Parse(data []byte) ([]*Route, error) {
+	var doc document
+	var err error
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte("{")) {
+		err = json.Unmarshal(data, &doc)
+	} else {
+		err = yaml.Unmarshal(data, &doc)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if doc.Swagger == "" && doc.OpenAPI == "" {
+		return nil, fmt.Errorf("neither swagger nor openapi version is found")
+	}
+
+	basePaths := doc.basePaths()
+
+	routes := make([]*Route, 0, len(doc.Paths)*len(basePaths))
+	for _, basePath := range basePaths {
+		for path, item := range doc.Paths {
+			methods := make([]string, 0, len(item))
+			for _, m := range httpMethods {
+				if _, ok := item[m]; ok {
+					methods = append(methods, strings.ToUpper(m))
+				}
+			}
+
+			if len(methods) == 0 {
+				continue
+			}
+
+			route, err := newRoute(basePath+path, methods)
+			if err != nil {
+				return nil, err
+			}
+			routes = append(routes, route)
+		}
+	}
+
+	sort.SliceStable(routes, func(i, j int) bool {
+		if routes[i].params != routes[j].params {
+			return routes[i].params < routes[j].params
+		}
+
+		return routes[i].Path < routes[j].Path
+	})
+
+	return routes, nil
+}
+
+// basePaths returns the distinct path prefixes of basePath (Swagger 2) or servers (OpenAPI 3)
+func (doc *document) basePaths() []string {
+	if doc.Swagger != "" {
+		return []string{strings.TrimSuffix(doc.BasePath, "/")}
+	}
+
+	if len(doc.Servers) == 0 {
+		return []string{""}
+	}
+
+	seen := make(map[string]struct{}, len(doc.Servers))
+	basePaths := make([]string, 0, len(doc.Servers))
+	for _, server := range doc.Servers {
+		// the server variables such as {basePath} are treated as the path parameters
+		basePath := server.URL
+		if i := strings.Index(basePath, "://"); i >= 0 {
+			basePath = basePath[i+len("://"):]
+			if j := strings.Index(basePath, "/"); j >= 0 {
+				basePath = basePath[j:]
+			} else {
+				basePath = ""
+			}
+		}
+		basePath = strings.TrimSuffix(basePath, "/")
+
+		if _, ok := seen[basePath]; ok {
+			continue
+		}
+		seen[basePath] = struct{}{}
+		basePaths = append(basePaths, basePath)
+	}
+
+	return basePaths
+}
+
+func newRoute(path string, methods []string) (*Route, error) {
+	var pattern strings.Builder
+	pattern.WriteString("^")
+
+	var params, last int
+	for _, loc := range pathParamRe.FindAllStringIndex(path, -1) {
+		pattern.WriteString(regexp.QuoteMeta(path[last:loc[0]]))
+		pattern.WriteString(paramSegment)
+		last = loc[1]
+		params++
+	}
+	pattern.WriteString(regexp.QuoteMeta(path[last:]))
+	pattern.WriteString("$")
+
+	re, err := regexp.Compile(pattern.String())
+	if err != nil {
+		return nil, err
+	}
+
+	return &Route{
+		Path:    path,
+		Methods: methods,
+		re:      re,
+		params:  params,
+	}, nil
+}
@@ -2,7 +2,9 @@ package options
 
 import (
 	"net"
+	"reflect"
 
+	"github.com/mitchellh/mapstructure"
 	"github.com/tkuchiki/alp/helpers"
 )
 
@@ -13,22 +15,38 @@ const (
 	DefaultLocationOption  = "Local"
 	DefaultOutputOption    = "all"
 	DefaultPaginationLimit = 100
+	DefaultWorkersOption   = 1
+	// follow
+	DefaultFollowIntervalOption = "5s"
+	// histogram
+	DefaultHistogramBucketsOption = 10
+	DefaultHistogramScaleOption   = "linear"
+	// metrics
+	DefaultMetricsBucketsOption = "0.005,0.01,0.025,0.05,0.1,0.25,0.5,1,2.5,5,10"
+	// percentile
+	DefaultPercentileEstimatorOption = "exact"
+	DefaultPercentileAccuracyOption  = 0.01
+	DefaultApdexThresholdOption      = 0.5
+	// diff
+	DefaultSignificanceLevelOption = 0.05
 	// ltsv
 	DefaultApptimeLabelOption = "apptime"
 	DefaultReqtimeLabelOption = "reqtime"
 	DefaultStatusLabelOption  = "status"
 	DefaultSizeLabelOption    = "size"
+	DefaultReqsizeLabelOption = "reqsize"
 	DefaultMethodLabelOption  = "method"
 	DefaultUriLabelOption     = "uri"
 	DefaultTimeLabelOption    = "time"
 	// json
-	DefaultUriKeyOption          = "uri"
-	DefaultMethodKeyOption       = "method"
-	DefaultTimeKeyOption         = "time"
-	DefaultResponseTimeKeyOption = "response_time"
-	DefaultRequestTimeKeyOption  = "request_time"
-	DefaultBodyBytesKeyOption    = "body_bytes"
-	DefaultStatusKeyOption       = "status"
+	DefaultUriKeyOption              = "uri"
+	DefaultMethodKeyOption           = "method"
+	DefaultTimeKeyOption             = "time"
+	DefaultResponseTimeKeyOption     = "response_time"
+	DefaultRequestTimeKeyOption      = "request_time"
+	DefaultBodyBytesKeyOption        = "body_bytes"
+	DefaultRequestBodyBytesKeyOption = "request_body_bytes"
+	DefaultStatusKeyOption           = "status"
 	// regexp
 	DefaultPatternOption = `^(\S+)\s` + // remote host
 		`\S+\s+` +
@@ -42,17 +60,20 @@ const (
 		`"((?:[^"]*(?:\\")?)*)"\s` + // referer
 		`"(?:.+)"` + // user agent
 		`\s(?P<response_time>\S+)(?:\s(?P<request_time>\S+))?$`
-	DefaultUriSubexpOption          = "uri"
-	DefaultMethodSubexpOption       = "method"
-	DefaultTimeSubexpOption         = "time"
-	DefaultResponseTimeSubexpOption = "response_time"
-	DefaultRequestTimeSubexpOption  = "request_time"
-	DefaultBodyBytesSubexpOption    = "body_bytes"
-	DefaultStatusSubexpOption       = "status"
+	DefaultUriSubexpOption              = "uri"
+	DefaultMethodSubexpOption           = "method"
+	DefaultTimeSubexpOption             = "time"
+	DefaultResponseTimeSubexpOption     = "response_time"
+	DefaultRequestTimeSubexpOption      = "request_time"
+	DefaultBodyBytesSubexpOption        = "body_bytes"
+	DefaultRequestBodyBytesSubexpOption = "request_body_bytes"
+	DefaultStatusSubexpOption           = "status"
 	// pcap
 	DefaultPcapServerPortOption = 80
 	// topN
 	DefaultTopNSortOption = "restime"
+	// suggest-groups
+	DefaultSuggestGroupsMinCardinalityOption = 10
 )
 
 var DefaultPercentilesOption = []int{90, 95, 99}
@@ -60,77 +81,160 @@ var DefaultPercentilesOption = []int{90, 95, 99}
 var DefaultPcapServerIPsOption = getDefaultPcapServerIPsOption()
 
 type Options struct {
-	File                    string         `mapstructure:"file"`
-	Dump                    string         `mapstructure:"dump"`
-	Load                    string         `mapstructure:"load"`
-	Sort                    string         `mapstructure:"sort"`
-	Reverse                 bool           `mapstructure:"reverse"`
-	QueryString             bool           `mapstructure:"query_string"`
-	QueryStringIgnoreValues bool           `mapstructure:"query_string_ignore_values"`
-	DecodeUri               bool           `mapstructure:"decode_uri"`
-	Format                  string         `mapstructure:"format"`
-	NoHeaders               bool           `mapstructure:"noheaders"`
-	ShowFooters             bool           `mapstructure:"show_footers"`
-	Limit                   int            `mapstructure:"limit"`
-	MatchingGroups          []string       `mapstructure:"matching_groups"`
-	Filters                 string         `mapstructure:"filters"`
-	PosFile                 string         `mapstructure:"pos_file"`
-	NoSavePos               bool           `mapstructure:"nosave_pos"`
-	Location                string         `mapstructure:"location"`
-	Output                  string         `mapstructure:"output"`
-	Percentiles             []int          `mapstructure:"percentiles"`
-	PaginationLimit         int            `mapstructure:"pagination_limit"`
-	LTSV                    *LTSVOptions   `mapstructure:"ltsv"`
-	Regexp                  *RegexpOptions `mapstructure:"regexp"`
-	JSON                    *JSONOptions   `mapstructure:"json"`
-	Pcap                    *PcapOptions   `mapstructure:"pcap"`
-	Count                   *CountOptions  `mapstructure:"count"`
-	TopN                    *TopNOptions   `mapstructure:"topN"`
+	File                    string                `mapstructure:"file" yaml:"file"`
+	Dump                    string                `mapstructure:"dump" yaml:"dump"`
+	Load                    string                `mapstructure:"load" yaml:"load"`
+	DumpFormat              string                `mapstructure:"dump_format" yaml:"dump_format"`
+	Sort                    string                `mapstructure:"sort" yaml:"sort"`
+	Reverse                 bool                  `mapstructure:"reverse" yaml:"reverse"`
+	QueryString             bool                  `mapstructure:"query_string" yaml:"query_string"`
+	QueryStringIgnoreValues bool                  `mapstructure:"query_string_ignore_values" yaml:"query_string_ignore_values"`
+	DecodeUri               bool                  `mapstructure:"decode_uri" yaml:"decode_uri"`
+	Format                  string                `mapstructure:"format" yaml:"format"`
+	NoHeaders               bool                  `mapstructure:"noheaders" yaml:"noheaders"`
+	ShowFooters             bool                  `mapstructure:"show_footers" yaml:"show_footers"`
+	Limit                   int                   `mapstructure:"limit" yaml:"limit"`
+	MatchingGroups          []*MatchingGroup      `mapstructure:"matching_groups" yaml:"matching_groups"`
+	Filters                 string                `mapstructure:"filters" yaml:"filters"`
+	PosFile                 string                `mapstructure:"pos_file" yaml:"pos_file"`
+	NoSavePos               bool                  `mapstructure:"nosave_pos" yaml:"nosave_pos"`
+	Location                string                `mapstructure:"location" yaml:"location"`
+	Output                  string                `mapstructure:"output" yaml:"output"`
+	Percentiles             []int                 `mapstructure:"percentiles" yaml:"percentiles"`
+	StatusCodes             []int                 `mapstructure:"status_codes" yaml:"status_codes"`
+	GroupBy                 []string              `mapstructure:"group_by" yaml:"group_by"`
+	PaginationLimit         int                   `mapstructure:"pagination_limit" yaml:"pagination_limit"`
+	PercentileEstimator     string                `mapstructure:"percentile_estimator" yaml:"percentile_estimator"`
+	PercentileAccuracy      float64               `mapstructure:"percentile_accuracy" yaml:"percentile_accuracy"`
+	ApdexThreshold          float64               `mapstructure:"apdex_threshold" yaml:"apdex_threshold"`
+	SignificanceLevel       float64               `mapstructure:"significance_level" yaml:"significance_level"`
+	Workers                 int                   `mapstructure:"workers" yaml:"workers"`
+	Timeline                string                `mapstructure:"timeline" yaml:"timeline"`
+	Follow                  bool                  `mapstructure:"follow" yaml:"follow"`
+	FollowInterval          string                `mapstructure:"follow_interval" yaml:"follow_interval"`
+	MetricsListen           string                `mapstructure:"metrics_listen" yaml:"metrics_listen"`
+	MetricsBuckets          string                `mapstructure:"metrics_buckets" yaml:"metrics_buckets"`
+	OpenAPI                 string                `mapstructure:"openapi" yaml:"openapi"`
+	Histogram               bool                  `mapstructure:"histogram" yaml:"histogram"`
+	HistogramBuckets        int                   `mapstructure:"histogram_buckets" yaml:"histogram_buckets"`
+	HistogramScale          string                `mapstructure:"histogram_scale" yaml:"histogram_scale"`
+	HistogramUri            string                `mapstructure:"histogram_uri" yaml:"histogram_uri"`
+	LTSV                    *LTSVOptions          `mapstructure:"ltsv" yaml:"ltsv"`
+	Regexp                  *RegexpOptions        `mapstructure:"regexp" yaml:"regexp"`
+	JSON                    *JSONOptions          `mapstructure:"json" yaml:"json"`
+	Pcap                    *PcapOptions          `mapstructure:"pcap" yaml:"pcap"`
+	Count                   *CountOptions         `mapstructure:"count" yaml:"count"`
+	TopN                    *TopNOptions          `mapstructure:"topN" yaml:"topN"`
+	SuggestGroups           *SuggestGroupsOptions `mapstructure:"suggest_groups" yaml:"suggest_groups"`
+	Check                   *CheckOptions         `mapstructure:"check" yaml:"check"`
+}
+
+// MatchingGroup is the URI matching group.
+// Name is displayed as the Uri instead of Pattern if it is set, and only the requests of Methods are grouped if they are set.
+type MatchingGroup struct {
+	Name    string   `mapstructure:"name" yaml:"name"`
+	Pattern string   `mapstructure:"pattern" yaml:"pattern"`
+	Methods []string `mapstructure:"methods" yaml:"methods"`
+}
+
+// NewMatchingGroups returns the unnamed matching groups of the patterns
+func NewMatchingGroups(patterns []string) []*MatchingGroup {
+	groups := make([]*MatchingGroup, 0, len(patterns))
+	for _, pattern := range patterns {
+		groups = append(groups, &MatchingGroup{
+			Pattern: pattern,
+		})
+	}
+
+	return groups
+}
+
+// StringToMatchingGroupHookFunc returns the mapstructure.DecodeHookFunc that decodes the string of matching_groups as the unnamed matching group
+func StringToMatchingGroupHookFunc() mapstructure.DecodeHookFunc {
+	return func(f reflect.Type, t reflect.Type, data interface{}) (interface{}, error) {
+		if f.Kind() != reflect.String {
+			return data, nil
+		}
+
+		if t != reflect.TypeOf(MatchingGroup{}) && t != reflect.TypeOf(&MatchingGroup{}) {
+			return data, nil
+		}
+
+		return map[string]interface{}{
+			"pattern": data,
+		}, nil
+	}
 }
 
 type LTSVOptions struct {
-	ApptimeLabel string `mapstructure:"apptime_label"`
-	ReqtimeLabel string `mapstructure:"reqtime_label"`
-	StatusLabel  string `mapstructure:"status_label"`
-	SizeLabel    string `mapstructure:"size_label"`
-	MethodLabel  string `mapstructure:"method_label"`
-	UriLabel     string `mapstructure:"uri_label"`
-	TimeLabel    string `mapstructure:"time_label"`
+	ApptimeLabel string `mapstructure:"apptime_label" yaml:"apptime_label"`
+	ReqtimeLabel string `mapstructure:"reqtime_label" yaml:"reqtime_label"`
+	StatusLabel  string `mapstructure:"status_label" yaml:"status_label"`
+	SizeLabel    string `mapstructure:"size_label" yaml:"size_label"`
+	ReqsizeLabel string `mapstructure:"reqsize_label" yaml:"reqsize_label"`
+	MethodLabel  string `mapstructure:"method_label" yaml:"method_label"`
+	UriLabel     string `mapstructure:"uri_label" yaml:"uri_label"`
+	TimeLabel    string `mapstructure:"time_label" yaml:"time_label"`
 }
 
 type RegexpOptions struct {
-	Pattern            string `mapstructure:"pattern"`
-	UriSubexp          string `mapstructure:"uri_subexp"`
-	MethodSubexp       string `mapstructure:"method_subexp"`
-	TimeSubexp         string `mapstructure:"time_subexp"`
-	ResponseTimeSubexp string `mapstructure:"response_time_subexp"`
-	RequestTimeSubexp  string `mapstructure:"request_time_subexp"`
-	BodyBytesSubexp    string `mapstructure:"body_bytes_subexp"`
-	StatusSubexp       string `mapstructure:"status_subexp"`
+	Pattern                string `mapstructure:"pattern" yaml:"pattern"`
+	UriSubexp              string `mapstructure:"uri_subexp" yaml:"uri_subexp"`
+	MethodSubexp           string `mapstructure:"method_subexp" yaml:"method_subexp"`
+	TimeSubexp             string `mapstructure:"time_subexp" yaml:"time_subexp"`
+	ResponseTimeSubexp     string `mapstructure:"response_time_subexp" yaml:"response_time_subexp"`
+	RequestTimeSubexp      string `mapstructure:"request_time_subexp" yaml:"request_time_subexp"`
+	BodyBytesSubexp        string `mapstructure:"body_bytes_subexp" yaml:"body_bytes_subexp"`
+	RequestBodyBytesSubexp string `mapstructure:"request_body_bytes_subexp" yaml:"request_body_bytes_subexp"`
+	StatusSubexp           string `mapstructure:"status_subexp" yaml:"status_subexp"`
 }
 
 type JSONOptions struct {
-	UriKey          string `mapstructure:"uri_key"`
-	MethodKey       string `mapstructure:"method_key"`
-	TimeKey         string `mapstructure:"time_key"`
-	ResponseTimeKey string `mapstructure:"response_time_key"`
-	RequestTimeKey  string `mapstructure:"request_time_key"`
-	BodyBytesKey    string `mapstructure:"body_bytes_key"`
-	StatusKey       string `mapstructure:"status_key"`
+	UriKey              string `mapstructure:"uri_key" yaml:"uri_key"`
+	MethodKey           string `mapstructure:"method_key" yaml:"method_key"`
+	TimeKey             string `mapstructure:"time_key" yaml:"time_key"`
+	ResponseTimeKey     string `mapstructure:"response_time_key" yaml:"response_time_key"`
+	RequestTimeKey      string `mapstructure:"request_time_key" yaml:"request_time_key"`
+	BodyBytesKey        string `mapstructure:"body_bytes_key" yaml:"body_bytes_key"`
+	RequestBodyBytesKey string `mapstructure:"request_body_bytes_key" yaml:"request_body_bytes_key"`
+	StatusKey           string `mapstructure:"status_key" yaml:"status_key"`
 }
 
 type PcapOptions struct {
-	ServerIPs  []string `mapstructure:"server_ips"`
-	ServerPort uint16   `mapstructure:"server_port"`
+	ServerIPs  []string `mapstructure:"server_ips" yaml:"server_ips"`
+	ServerPort uint16   `mapstructure:"server_port" yaml:"server_port"`
 }
 
 type CountOptions struct {
-	Keys []string `mapstructure:"keys"`
+	Keys []string `mapstructure:"keys" yaml:"keys"`
+}
+
+type SuggestGroupsOptions struct {
+	MinCardinality int `mapstructure:"min_cardinality" yaml:"min_cardinality"`
+}
+
+type CheckOptions struct {
+	Rules    []*CheckRule `mapstructure:"rules" yaml:"rules"`
+	Baseline string       `mapstructure:"baseline" yaml:"baseline"`
+}
+
+// CheckRule is the rule that the metric of each endpoint must satisfy.
+// Uri is the regular expression of the URIs, and the rule applies to every endpoint if Uri and Methods are not set.
+// Max and Min are the limits of the value, and MaxIncrease and MaxDecrease are the limits of the change from the baseline in percent.
+type CheckRule struct {
+	Name        string   `mapstructure:"name" yaml:"name"`
+	Uri         string   `mapstructure:"uri" yaml:"uri"`
+	Methods     []string `mapstructure:"methods" yaml:"methods"`
+	Metric      string   `mapstructure:"metric" yaml:"metric"`
+	Max         *float64 `mapstructure:"max" yaml:"max"`
+	Min         *float64 `mapstructure:"min" yaml:"min"`
+	MaxIncrease *float64 `mapstructure:"max_increase" yaml:"max_increase"`
+	MaxDecrease *float64 `mapstructure:"max_decrease" yaml:"max_decrease"`
 }
 
 type TopNOptions struct {
-	Sort    string `mapstructure:"sort"`
-	Reverse bool   `mapstructure:"reverse"`
+	Sort    string `mapstructure:"sort" yaml:"sort"`
+	Reverse bool   `mapstructure:"reverse" yaml:"reverse"`
 }
 
 type Option func(*Options)
@@ -151,6 +255,14 @@ func Dump(s string) Option {
 	}
 }
 
+func DumpFormat(s string) Option {
+	return func(opts *Options) {
+		if s != "" {
+			opts.DumpFormat = s
+		}
+	}
+}
+
 func Load(s string) Option {
 	return func(opts *Options) {
 		if s != "" {
@@ -231,7 +343,7 @@ func Limit(i int) Option {
 	}
 }
 
-func MatchingGroups(values []string) Option {
+func MatchingGroups(values []*MatchingGroup) Option {
 	return func(opts *Options) {
 		if len(values) > 0 {
 			opts.MatchingGroups = values
@@ -243,7 +355,7 @@ func CSVGroups(csv string) Option {
 	return func(opts *Options) {
 		a := helpers.SplitCSV(csv)
 		if len(a) > 0 {
-			opts.MatchingGroups = a
+			opts.MatchingGroups = NewMatchingGroups(a)
 		}
 	}
 }
@@ -296,6 +408,22 @@ func Percentiles(i []int) Option {
 	}
 }
 
+func StatusCodes(i []int) Option {
+	return func(opts *Options) {
+		if len(i) > 0 {
+			opts.StatusCodes = i
+		}
+	}
+}
+
+func GroupBy(ss []string) Option {
+	return func(opts *Options) {
+		if len(ss) > 0 {
+			opts.GroupBy = ss
+		}
+	}
+}
+
 func PaginationLimit(i int) Option {
 	return func(opts *Options) {
 		if i > 0 {
@@ -304,6 +432,127 @@ func PaginationLimit(i int) Option {
 	}
 }
 
+func PercentileEstimator(s string) Option {
+	return func(opts *Options) {
+		if s != "" {
+			opts.PercentileEstimator = s
+		}
+	}
+}
+
+func PercentileAccuracy(f float64) Option {
+	return func(opts *Options) {
+		if f > 0 {
+			opts.PercentileAccuracy = f
+		}
+	}
+}
+
+// ApdexThreshold sets the T of the Apdex score, and 0 disables the Apdex score
+func ApdexThreshold(f float64) Option {
+	return func(opts *Options) {
+		if f >= 0 {
+			opts.ApdexThreshold = f
+		}
+	}
+}
+
+func SignificanceLevel(f float64) Option {
+	return func(opts *Options) {
+		if f > 0 {
+			opts.SignificanceLevel = f
+		}
+	}
+}
+
+func Workers(i int) Option {
+	return func(opts *Options) {
+		if i > 0 {
+			opts.Workers = i
+		}
+	}
+}
+
+func Timeline(s string) Option {
+	return func(opts *Options) {
+		if s != "" {
+			opts.Timeline = s
+		}
+	}
+}
+
+func Follow(b bool) Option {
+	return func(opts *Options) {
+		if b {
+			opts.Follow = b
+		}
+	}
+}
+
+func FollowInterval(s string) Option {
+	return func(opts *Options) {
+		if s != "" {
+			opts.FollowInterval = s
+		}
+	}
+}
+
+func MetricsListen(s string) Option {
+	return func(opts *Options) {
+		if s != "" {
+			opts.MetricsListen = s
+		}
+	}
+}
+
+func MetricsBuckets(s string) Option {
+	return func(opts *Options) {
+		if s != "" {
+			opts.MetricsBuckets = s
+		}
+	}
+}
+
+func OpenAPI(s string) Option {
+	return func(opts *Options) {
+		if s != "" {
+			opts.OpenAPI = s
+		}
+	}
+}
+
+func Histogram(b bool) Option {
+	return func(opts *Options) {
+		if b {
+			opts.Histogram = b
+		}
+	}
+}
+
+func HistogramBuckets(i int) Option {
+	return func(opts *Options) {
+		if i > 0 {
+			opts.HistogramBuckets = i
+		}
+	}
+}
+
+func HistogramScale(s string) Option {
+	return func(opts *Options) {
+		if s != "" {
+			opts.HistogramScale = s
+		}
+	}
+}
+
+func HistogramUri(s string) Option {
+	return func(opts *Options) {
+		if s != "" {
+			opts.HistogramUri = s
+		}
+	}
+}
+
 // ltsv
 func ApptimeLabel(s string) Option {
 	return func(opts *Options) {
@@ -337,6 +586,14 @@ func SizeLabel(s string) Option {
 	}
 }
 
+func ReqsizeLabel(s string) Option {
+	return func(opts *Options) {
+		if s != "" {
+			opts.LTSV.ReqsizeLabel = s
+		}
+	}
+}
+
 func MethodLabel(s string) Option {
 	return func(opts *Options) {
 		if s != "" {
@@ -418,6 +675,14 @@ func BodyBytesSubexp(s string) Option {
 	}
 }
 
+func RequestBodyBytesSubexp(s string) Option {
+	return func(opts *Options) {
+		if s != "" {
+			opts.Regexp.RequestBodyBytesSubexp = s
+		}
+	}
+}
+
 func StatusSubexp(s string) Option {
 	return func(opts *Options) {
 		if s != "" {
@@ -475,6 +740,14 @@ func BodyBytesKey(s string) Option {
 	}
 }
 
+func RequestBodyBytesKey(s string) Option {
+	return func(opts *Options) {
+		if s != "" {
+			opts.JSON.RequestBodyBytesKey = s
+		}
+	}
+}
+
 func StatusKey(s string) Option {
 	return func(opts *Options) {
 		if s != "" {
@@ -509,6 +782,24 @@ func CountKeys(ss []string) Option {
 	}
 }
 
+// suggest-groups
+func SuggestGroupsMinCardinality(i int) Option {
+	return func(opts *Options) {
+		if i > 0 {
+			opts.SuggestGroups.MinCardinality = i
+		}
+	}
+}
+
+// check
+func CheckBaseline(s string) Option {
+	return func(opts *Options) {
+		if s != "" {
+			opts.Check.Baseline = s
+		}
+	}
+}
+
 // topN
 func TopNSort(s string) Option {
 	return func(opts *Options) {
@@ -532,30 +823,33 @@ func NewOptions(opt ...Option) *Options {
 		ReqtimeLabel: DefaultReqtimeLabelOption,
 		StatusLabel:  DefaultStatusLabelOption,
 		SizeLabel:    DefaultSizeLabelOption,
+		ReqsizeLabel: DefaultReqsizeLabelOption,
 		MethodLabel:  DefaultMethodLabelOption,
 		UriLabel:     DefaultUriLabelOption,
 		TimeLabel:    DefaultTimeLabelOption,
 	}
 
 	regexp := &RegexpOptions{
-		Pattern:            DefaultPatternOption,
-		UriSubexp:          DefaultUriSubexpOption,
-		MethodSubexp:       DefaultMethodSubexpOption,
-		TimeSubexp:         DefaultTimeSubexpOption,
-		ResponseTimeSubexp: DefaultResponseTimeSubexpOption,
-		RequestTimeSubexp:  DefaultRequestTimeSubexpOption,
-		BodyBytesSubexp:    DefaultBodyBytesSubexpOption,
-		StatusSubexp:       DefaultStatusSubexpOption,
+		Pattern:                DefaultPatternOption,
+		UriSubexp:              DefaultUriSubexpOption,
+		MethodSubexp:           DefaultMethodSubexpOption,
+		TimeSubexp:             DefaultTimeSubexpOption,
+		ResponseTimeSubexp:     DefaultResponseTimeSubexpOption,
+		RequestTimeSubexp:      DefaultRequestTimeSubexpOption,
+		BodyBytesSubexp:        DefaultBodyBytesSubexpOption,
+		RequestBodyBytesSubexp: DefaultRequestBodyBytesSubexpOption,
+		StatusSubexp:           DefaultStatusSubexpOption,
 	}
 
 	json := &JSONOptions{
-		UriKey:          DefaultUriKeyOption,
-		MethodKey:       DefaultMethodKeyOption,
-		TimeKey:         DefaultTimeKeyOption,
-		ResponseTimeKey: DefaultResponseTimeKeyOption,
-		RequestTimeKey:  DefaultRequestTimeKeyOption,
-		BodyBytesKey:    DefaultBodyBytesKeyOption,
-		StatusKey:       DefaultStatusKeyOption,
+		UriKey:              DefaultUriKeyOption,
+		MethodKey:           DefaultMethodKeyOption,
+		TimeKey:             DefaultTimeKeyOption,
+		ResponseTimeKey:     DefaultResponseTimeKeyOption,
+		RequestTimeKey:      DefaultRequestTimeKeyOption,
+		BodyBytesKey:        DefaultBodyBytesKeyOption,
+		RequestBodyBytesKey: DefaultRequestBodyBytesKeyOption,
+		StatusKey:           DefaultStatusKeyOption,
 	}
 
 	pcap := &PcapOptions{
@@ -569,20 +863,37 @@ func NewOptions(opt ...Option) *Options {
 		Sort: DefaultTopNSortOption,
 	}
 
+	suggestGroups := &SuggestGroupsOptions{
+		MinCardinality: DefaultSuggestGroupsMinCardinalityOption,
+	}
+
+	check := &CheckOptions{}
+
 	options := &Options{
-		Sort:            DefaultSortOption,
-		Format:          DefaultFormatOption,
-		Limit:           DefaultLimitOption,
-		Location:        DefaultLocationOption,
-		Output:          DefaultOutputOption,
-		Percentiles:     DefaultPercentilesOption,
-		PaginationLimit: DefaultPaginationLimit,
-		LTSV:            ltsv,
-		Regexp:          regexp,
-		JSON:            json,
-		Pcap:            pcap,
-		Count:           count,
-		TopN:            topN,
+		Sort:                DefaultSortOption,
+		Format:              DefaultFormatOption,
+		Limit:               DefaultLimitOption,
+		Location:            DefaultLocationOption,
+		Output:              DefaultOutputOption,
+		Percentiles:         DefaultPercentilesOption,
+		PaginationLimit:     DefaultPaginationLimit,
+		PercentileEstimator: DefaultPercentileEstimatorOption,
+		PercentileAccuracy:  DefaultPercentileAccuracyOption,
+		ApdexThreshold:      DefaultApdexThresholdOption,
+		SignificanceLevel:   DefaultSignificanceLevelOption,
+		Workers:             DefaultWorkersOption,
+		FollowInterval:      DefaultFollowIntervalOption,
+		MetricsBuckets:      DefaultMetricsBucketsOption,
+		HistogramBuckets:    DefaultHistogramBucketsOption,
+		HistogramScale:      DefaultHistogramScaleOption,
+		LTSV:                ltsv,
+		Regexp:              regexp,
+		JSON:                json,
+		Pcap:                pcap,
+		Count:               count,
+		TopN:                topN,
+		SuggestGroups:       suggestGroups,
+		Check:               check,
 	}
 
 	for _, o := range opt {
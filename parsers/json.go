@@ -16,7 +16,7 @@ type JSONParser struct {
 	readBytes      int
 }
 
-func NewJSONKeys(uri, method, time, responseTime, requestTime, size, status string) *statKeys {
+func NewJSONKeys(uri, method, time, responseTime, requestTime, size, requestSize, status string) *statKeys {
 	return newStatKeys(
 		uriKey(uri),
 		methodKey(method),
@@ -24,6 +24,7 @@ func NewJSONKeys(uri, method, time, responseTime, requestTime, size, status stri
 		responseTimeKey(responseTime),
 		requestTimeKey(requestTime),
 		bodyBytesKey(size),
+		requestBodyBytesKey(requestSize),
 		statusKey(status),
 	)
 }
@@ -38,19 +39,32 @@ func NewJSONParser(r io.Reader, keys *statKeys, query, qsIgnoreValues bool) Pars
 }
 
 func (j *JSONParser) Parse() (*ParsedHTTPStat, error) {
+	b, err := j.ReadLine()
+	if err != nil {
+		return nil, err
+	}
+
+	return j.ParseLine(b)
+}
+
+func (j *JSONParser) ReadLine() ([]byte, error) {
 	b, i, err := readline(j.reader)
 	if len(b) == 0 && err != nil {
 		return nil, err
 	}
 	j.readBytes += i
 
+	return b, nil
+}
+
+func (j *JSONParser) ParseLine(b []byte) (*ParsedHTTPStat, error) {
 	var tmp map[string]interface{}
-	err = json.Unmarshal(b, &tmp)
+	err := json.Unmarshal(b, &tmp)
 	if err != nil {
 		return nil, err
 	}
 
-	keys := make([]string, 6)
+	keys := make([]string, 8)
 	keys = []string{
 		j.keys.uri,
 		j.keys.method,
@@ -58,9 +72,10 @@ func (j *JSONParser) Parse() (*ParsedHTTPStat, error) {
 		j.keys.responseTime,
 		j.keys.requestTime,
 		j.keys.bodyBytes,
+		j.keys.requestBodyBytes,
 		j.keys.status,
 	}
-	parsedValue := make(map[string]string, 6)
+	parsedValue := make(map[string]string, 8)
 	for _, key := range keys {
 		val, ok := tmp[key]
 		if !ok {
@@ -0,0 +1,59 @@
+package parsers
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/tkuchiki/alp/errors"
+)
+
+func TestJSONParserRequestBodyBytes(t *testing.T) {
+	tests := []struct {
+		name string
+		line string
+		want float64
+		err  error
+	}{
+		{
+			name: "request size",
+			line: `{"time":"2015-09-06T05:58:05+09:00","method":"POST","uri":"/foo","status":200,"body_bytes":12,"request_body_bytes":345,"response_time":0.057}`,
+			want: 345,
+		},
+		{
+			name: "string",
+			line: `{"time":"2015-09-06T05:58:05+09:00","method":"POST","uri":"/foo","status":200,"body_bytes":12,"request_body_bytes":"345","response_time":0.057}`,
+			want: 345,
+		},
+		{
+			name: "no request size",
+			line: `{"time":"2015-09-06T05:58:05+09:00","method":"GET","uri":"/foo","status":200,"body_bytes":12,"response_time":0.057}`,
+			want: 0,
+		},
+		{
+			name: "invalid request size",
+			line: `{"time":"2015-09-06T05:58:05+09:00","method":"POST","uri":"/foo","status":200,"body_bytes":12,"request_body_bytes":"abc","response_time":0.057}`,
+			err:  errors.SkipReadLineErr,
+		},
+	}
+
+	keys := NewJSONKeys("uri", "method", "time", "response_time", "request_time", "body_bytes", "request_body_bytes", "status")
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parser := NewJSONParser(strings.NewReader(tt.line+"\n"), keys, false, false)
+			stat, err := parser.Parse()
+			if err != tt.err {
+				t.Fatalf("want: %v, got: %v", tt.err, err)
+			}
+			if err != nil {
+				return
+			}
+
+			if stat.RequestBodyBytes != tt.want {
+				t.Errorf("want: %v, got: %v", tt.want, stat.RequestBodyBytes)
+			}
+			if stat.BodyBytes != 12 {
+				t.Errorf("want the response body bytes: 12, got: %v", stat.BodyBytes)
+			}
+		})
+	}
+}
@@ -16,7 +16,7 @@ type LTSVParser struct {
 	readBytes      int
 }
 
-func NewLTSVLabel(uri, method, time, responseTime, requestTime, size, status string) *statKeys {
+func NewLTSVLabel(uri, method, time, responseTime, requestTime, size, requestSize, status string) *statKeys {
 	return newStatKeys(
 		uriKey(uri),
 		methodKey(method),
@@ -24,6 +24,7 @@ func NewLTSVLabel(uri, method, time, responseTime, requestTime, size, status str
 		responseTimeKey(responseTime),
 		requestTimeKey(requestTime),
 		bodyBytesKey(size),
+		requestBodyBytesKey(requestSize),
 		statusKey(status),
 	)
 }
@@ -38,16 +39,29 @@ func NewLTSVParser(r io.Reader, l *statKeys, query, qsIgnoreValues bool) Parser
 }
 
 func (l *LTSVParser) Parse() (*ParsedHTTPStat, error) {
+	b, err := l.ReadLine()
+	if err != nil {
+		return nil, err
+	}
+
+	return l.ParseLine(b)
+}
+
+func (l *LTSVParser) ReadLine() ([]byte, error) {
 	b, i, err := readline(l.reader)
 	if len(b) == 0 && err != nil {
 		return nil, err
 	}
 	l.readBytes += i
 
+	return b, nil
+}
+
+func (l *LTSVParser) ParseLine(b []byte) (*ParsedHTTPStat, error) {
 	parsedValue := make(map[string]string, 0)
 	err2 := ltsv.Unmarshal(b, &parsedValue)
 	if err2 != nil && l.strictMode {
-		return nil, err
+		return nil, err2
 	}
 
 	parsedHTTPStat, err := toStats(parsedValue, l.label, l.strictMode, l.queryString, l.qsIgnoreValues)
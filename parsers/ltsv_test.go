@@ -0,0 +1,59 @@
+package parsers
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/tkuchiki/alp/errors"
+)
+
+func TestLTSVParserRequestBodyBytes(t *testing.T) {
+	tests := []struct {
+		name string
+		line string
+		want float64
+		err  error
+	}{
+		{
+			name: "request size",
+			line: "time:2015-09-06T05:58:05+09:00\tmethod:POST\turi:/foo\tstatus:200\tsize:12\treqsize:345\tapptime:0.057",
+			want: 345,
+		},
+		{
+			name: "hyphen",
+			line: "time:2015-09-06T05:58:05+09:00\tmethod:GET\turi:/foo\tstatus:200\tsize:12\treqsize:-\tapptime:0.057",
+			want: 0,
+		},
+		{
+			name: "no request size",
+			line: "time:2015-09-06T05:58:05+09:00\tmethod:GET\turi:/foo\tstatus:200\tsize:12\tapptime:0.057",
+			want: 0,
+		},
+		{
+			name: "invalid request size",
+			line: "time:2015-09-06T05:58:05+09:00\tmethod:POST\turi:/foo\tstatus:200\tsize:12\treqsize:abc\tapptime:0.057",
+			err:  errors.SkipReadLineErr,
+		},
+	}
+
+	label := NewLTSVLabel("uri", "method", "time", "apptime", "reqtime", "size", "reqsize", "status")
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parser := NewLTSVParser(strings.NewReader(tt.line+"\n"), label, false, false)
+			stat, err := parser.Parse()
+			if err != tt.err {
+				t.Fatalf("want: %v, got: %v", tt.err, err)
+			}
+			if err != nil {
+				return
+			}
+
+			if stat.RequestBodyBytes != tt.want {
+				t.Errorf("want: %v, got: %v", tt.want, stat.RequestBodyBytes)
+			}
+			if stat.BodyBytes != 12 {
+				t.Errorf("want the response body bytes: 12, got: %v", stat.BodyBytes)
+			}
+		})
+	}
+}
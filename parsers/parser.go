@@ -17,18 +17,43 @@ type Parser interface {
 	Seek(n int) error
 }
 
+// LineParser is a Parser that can read a line and decode it separately,
+// so that the lines can be decoded concurrently.
+// ParseLine must be safe for concurrent use.
+type LineParser interface {
+	Parser
+	ReadLine() ([]byte, error)
+	ParseLine(b []byte) (*ParsedHTTPStat, error)
+}
+
 type ParsedHTTPStat struct {
 	Uri          string
 	Method       string
 	Time         string
 	ResponseTime float64
-	BodyBytes    float64
-	Status       int
-	Entries      LogEntries
+	// BodyBytes is the response body bytes
+	BodyBytes        float64
+	RequestBodyBytes float64
+	Status           int
+	Entries          LogEntries
 }
 
 type LogEntries map[string]string
 
+// Values returns the values of keys, and the value is empty if the key is not in the entries
+func (e LogEntries) Values(keys []string) []string {
+	if len(keys) == 0 {
+		return nil
+	}
+
+	values := make([]string, 0, len(keys))
+	for _, key := range keys {
+		values = append(values, e[key])
+	}
+
+	return values
+}
+
 type statKeys struct {
 	uri          string
 	method       string
@@ -36,7 +61,9 @@ type statKeys struct {
 	responseTime string
 	requestTime  string
 	bodyBytes    string
-	status       string
+	// requestBodyBytes is optional, and the request body bytes are 0 if it is not in the log
+	requestBodyBytes string
+	status           string
 }
 
 type statKey func(*statKeys)
@@ -89,6 +116,14 @@ func bodyBytesKey(s string) statKey {
 	}
 }
 
+func requestBodyBytesKey(s string) statKey {
+	return func(sk *statKeys) {
+		if s != "" {
+			sk.requestBodyBytes = s
+		}
+	}
+}
+
 func statusKey(s string) statKey {
 	return func(sk *statKeys) {
 		if s != "" {
@@ -99,13 +134,14 @@ func statusKey(s string) statKey {
 
 func newStatKeys(sk ...statKey) *statKeys {
 	sks := &statKeys{
-		uri:          "uri",
-		method:       "method",
-		time:         "time",
-		responseTime: "response_time",
-		requestTime:  "request_time",
-		bodyBytes:    "body_bytes",
-		status:       "status",
+		uri:              "uri",
+		method:           "method",
+		time:             "time",
+		responseTime:     "response_time",
+		requestTime:      "request_time",
+		bodyBytes:        "body_bytes",
+		requestBodyBytes: "request_body_bytes",
+		status:           "status",
 	}
 
 	for _, s := range sk {
@@ -147,14 +183,15 @@ func readline(reader *bufio.Reader) ([]byte, int, error) {
 	return b, i, err
 }
 
-func NewParsedHTTPStat(uri, method, time string, resTime, bodyBytes float64, status int) *ParsedHTTPStat {
+func NewParsedHTTPStat(uri, method, time string, resTime, bodyBytes, reqBodyBytes float64, status int) *ParsedHTTPStat {
 	return &ParsedHTTPStat{
-		Uri:          uri,
-		Method:       method,
-		Time:         time,
-		ResponseTime: resTime,
-		BodyBytes:    bodyBytes,
-		Status:       status,
+		Uri:              uri,
+		Method:           method,
+		Time:             time,
+		ResponseTime:     resTime,
+		BodyBytes:        bodyBytes,
+		RequestBodyBytes: reqBodyBytes,
+		Status:           status,
 	}
 }
 
@@ -182,6 +219,14 @@ func toStats(parsedValue map[string]string, keys *statKeys, strictMode, queryStr
 		return nil, errSkipReadLine(strictMode, err)
 	}
 
+	var reqBodyBytes float64
+	if val, ok := parsedValue[keys.requestBodyBytes]; ok && val != "" && val != "-" {
+		reqBodyBytes, err = helpers.StringToFloat64(val)
+		if err != nil {
+			return nil, errSkipReadLine(strictMode, err)
+		}
+	}
+
 	status, err := helpers.StringToInt(parsedValue[keys.status])
 	if err != nil {
 		return nil, errSkipReadLine(strictMode, err)
@@ -190,7 +235,7 @@ func toStats(parsedValue map[string]string, keys *statKeys, strictMode, queryStr
 	method := parsedValue[keys.method]
 	timestr := parsedValue[keys.time]
 
-	return NewParsedHTTPStat(uri, method, timestr, resTime, bodyBytes, status), nil
+	return NewParsedHTTPStat(uri, method, timestr, resTime, bodyBytes, reqBodyBytes, status), nil
 }
 
 func normalizeURL(src *url.URL, queryString, qsIgnoreValues bool) string {
@@ -94,7 +94,8 @@ func (j *PcapParser) Parse() (*ParsedHTTPStat, error) {
 	uri := normalizeURL(req.URL, j.queryString, j.qsIgnoreValues)
 
 	resBodyBytes := res.ContentLength
-	stat := NewParsedHTTPStat(uri, req.Method, reqTimestamp.Format(time.RFC3339), math.Abs(resTime.Seconds()), float64(resBodyBytes), res.StatusCode)
+	reqBodyBytes := req.ContentLength
+	stat := NewParsedHTTPStat(uri, req.Method, reqTimestamp.Format(time.RFC3339), math.Abs(resTime.Seconds()), float64(resBodyBytes), float64(reqBodyBytes), res.StatusCode)
 	return stat, nil
 }
 
@@ -421,14 +422,17 @@ func parseHTTPRequest(rs *tcpReaderStream, clientAddr *net.TCPAddr, reqCh chan *
 		req.Header.Set(conjoinPcapKeyHeader, req.RemoteAddr)
 		req.Header.Set(timestampPcapKeyHeader, timeToUnixNanoStr(timestamp))
 
-		// discard body
-		if req.ContentLength > 0 && req.Body != http.NoBody {
-			_, err = io.CopyBuffer(io.Discard, req.Body, copyBuf[:])
+		// discard body, including the chunked body
+		if req.Body != nil && req.Body != http.NoBody {
+			n, err := io.CopyBuffer(io.Discard, req.Body, copyBuf[:])
 			_ = req.Body.Close()
 			if err != nil {
 				log.Printf("Failed to read HTTP body from the client %v at %s: %v", clientAddr, timestamp.Format(time.RFC3339Nano), err)
 				return
 			}
+			req.ContentLength = n // real request length
+		} else {
+			req.ContentLength = 0
 		}
 
 		// send parsed request
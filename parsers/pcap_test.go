@@ -0,0 +1,174 @@
+package parsers
+
+import (
+	"bytes"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/google/gopacket"
+	"github.com/google/gopacket/layers"
+	"github.com/google/gopacket/pcapgo"
+)
+
+// pcapConn writes the TCP packets of a connection between the client and the server
+type pcapConn struct {
+	t          *testing.T
+	w          *pcapgo.Writer
+	clientIP   net.IP
+	clientPort layers.TCPPort
+	serverIP   net.IP
+	serverPort layers.TCPPort
+	clientSeq  uint32
+	serverSeq  uint32
+	ts         time.Time
+}
+
+func (c *pcapConn) write(fromClient bool, syn, ack bool, payload []byte) {
+	c.t.Helper()
+
+	ip := &layers.IPv4{
+		Version:  4,
+		TTL:      64,
+		Protocol: layers.IPProtocolTCP,
+		SrcIP:    c.serverIP,
+		DstIP:    c.clientIP,
+	}
+	tcp := &layers.TCP{
+		SrcPort: c.serverPort,
+		DstPort: c.clientPort,
+		Seq:     c.serverSeq,
+		Ack:     c.clientSeq,
+		SYN:     syn,
+		ACK:     ack,
+		PSH:     len(payload) > 0,
+		Window:  65535,
+	}
+	if fromClient {
+		ip.SrcIP, ip.DstIP = c.clientIP, c.serverIP
+		tcp.SrcPort, tcp.DstPort = c.clientPort, c.serverPort
+		tcp.Seq, tcp.Ack = c.clientSeq, c.serverSeq
+	}
+	if err := tcp.SetNetworkLayerForChecksum(ip); err != nil {
+		c.t.Fatal(err)
+	}
+
+	buf := gopacket.NewSerializeBuffer()
+	opts := gopacket.SerializeOptions{FixLengths: true, ComputeChecksums: true}
+	eth := &layers.Ethernet{
+		SrcMAC:       net.HardwareAddr{0, 0, 0, 0, 0, 1},
+		DstMAC:       net.HardwareAddr{0, 0, 0, 0, 0, 2},
+		EthernetType: layers.EthernetTypeIPv4,
+	}
+	if err := gopacket.SerializeLayers(buf, opts, eth, ip, tcp, gopacket.Payload(payload)); err != nil {
+		c.t.Fatal(err)
+	}
+
+	c.ts = c.ts.Add(10 * time.Millisecond)
+	ci := gopacket.CaptureInfo{
+		Timestamp:     c.ts,
+		CaptureLength: len(buf.Bytes()),
+		Length:        len(buf.Bytes()),
+	}
+	if err := c.w.WritePacket(ci, buf.Bytes()); err != nil {
+		c.t.Fatal(err)
+	}
+
+	n := uint32(len(payload))
+	if syn {
+		n = 1
+	}
+	if fromClient {
+		c.clientSeq += n
+	} else {
+		c.serverSeq += n
+	}
+}
+
+// exchange writes the handshake, the request and the response
+func (c *pcapConn) exchange(req, res string) {
+	c.write(true, true, false, nil)
+	c.write(false, true, true, nil)
+	c.write(true, false, true, []byte(req))
+	c.write(false, false, true, []byte(res))
+}
+
+func TestPcapParserRequestBodyBytes(t *testing.T) {
+	var b bytes.Buffer
+	w := pcapgo.NewWriter(&b)
+	if err := w.WriteFileHeader(65536, layers.LinkTypeEthernet); err != nil {
+		t.Fatal(err)
+	}
+
+	serverIP := net.ParseIP("192.168.0.1").To4()
+	ts := time.Date(2023, 9, 4, 15, 45, 14, 0, time.UTC)
+
+	tests := []struct {
+		uri  string
+		req  string
+		want float64
+	}{
+		{
+			uri:  "/content-length",
+			req:  "POST /content-length HTTP/1.1\r\nHost: example.com\r\nContent-Length: 11\r\n\r\nhello world",
+			want: 11,
+		},
+		{
+			// the length of the chunked body is the length of the decoded body
+			uri:  "/chunked",
+			req:  "POST /chunked HTTP/1.1\r\nHost: example.com\r\nTransfer-Encoding: chunked\r\n\r\n5\r\nhello\r\n7\r\n, world\r\n0\r\n\r\n",
+			want: 12,
+		},
+		{
+			uri:  "/no-body",
+			req:  "GET /no-body HTTP/1.1\r\nHost: example.com\r\n\r\n",
+			want: 0,
+		},
+	}
+
+	// each request is sent in the different connection
+	for i, tt := range tests {
+		conn := &pcapConn{
+			t:          t,
+			w:          w,
+			clientIP:   net.ParseIP("192.168.0.2").To4(),
+			clientPort: layers.TCPPort(50000 + i),
+			serverIP:   serverIP,
+			serverPort: 8080,
+			clientSeq:  1000,
+			serverSeq:  5000,
+			ts:         ts.Add(time.Duration(i) * time.Second),
+		}
+		conn.exchange(tt.req, "HTTP/1.1 200 OK\r\nContent-Length: 2\r\n\r\nok")
+	}
+
+	parser, err := NewPcapParser(&b, []string{serverIP.String()}, 8080, false, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := make(map[string]float64)
+	uris := make([]string, 0)
+	for {
+		stat, err := parser.Parse()
+		if err != nil {
+			break
+		}
+
+		if stat.BodyBytes != 2 {
+			t.Errorf("%s want the response body bytes: 2, got: %v", stat.Uri, stat.BodyBytes)
+		}
+		got[stat.Uri] = stat.RequestBodyBytes
+		uris = append(uris, stat.Uri)
+	}
+
+	if len(uris) != len(tests) {
+		t.Fatalf("want: %d requests, got: %v", len(tests), uris)
+	}
+
+	for _, tt := range tests {
+		if got[tt.uri] != tt.want {
+			t.Errorf("%s want: %v, got: %v", tt.uri, tt.want, got[tt.uri])
+		}
+	}
+}
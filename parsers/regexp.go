@@ -19,7 +19,7 @@ type RegexpParser struct {
 
 var errPatternNotMatched = errors.New("pattern not matched")
 
-func NewSubexpNames(uri, method, time, responseTime, requestTime, size, status string) *statKeys {
+func NewSubexpNames(uri, method, time, responseTime, requestTime, size, requestSize, status string) *statKeys {
 	return newStatKeys(
 		uriKey(uri),
 		methodKey(method),
@@ -27,6 +27,7 @@ func NewSubexpNames(uri, method, time, responseTime, requestTime, size, status s
 		responseTimeKey(responseTime),
 		requestTimeKey(requestTime),
 		bodyBytesKey(size),
+		requestBodyBytesKey(requestSize),
 		statusKey(status),
 	)
 }
@@ -47,12 +48,25 @@ func NewRegexpParser(r io.Reader, expr string, names *statKeys, query, qsIgnoreV
 }
 
 func (rp *RegexpParser) Parse() (*ParsedHTTPStat, error) {
+	b, err := rp.ReadLine()
+	if err != nil {
+		return nil, err
+	}
+
+	return rp.ParseLine(b)
+}
+
+func (rp *RegexpParser) ReadLine() ([]byte, error) {
 	b, i, err := readline(rp.reader)
 	if len(b) == 0 && err != nil {
 		return nil, err
 	}
 	rp.readBytes += i
 
+	return b, nil
+}
+
+func (rp *RegexpParser) ParseLine(b []byte) (*ParsedHTTPStat, error) {
 	groups := rp.re.FindStringSubmatch(string(b))
 	if len(groups) == 0 {
 		return nil, errSkipReadLine(rp.strictMode, errPatternNotMatched)
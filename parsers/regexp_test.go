@@ -0,0 +1,70 @@
+package parsers
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/tkuchiki/alp/errors"
+)
+
+func TestRegexpParserRequestBodyBytes(t *testing.T) {
+	pattern := `^\S+ \S+ \S+ \[(?P<time>[^\]]+)\] "(?P<method>\S+) (?P<uri>\S+) \S+" (?P<status>\d+) (?P<body_bytes>\d+) (?P<request_body_bytes>\S+) (?P<response_time>[\d.]+)$`
+
+	tests := []struct {
+		name    string
+		line    string
+		pattern string
+		want    float64
+		err     error
+	}{
+		{
+			name:    "request size",
+			line:    `127.0.0.1 - - [06/Sep/2015:05:58:05 +0900] "POST /foo HTTP/1.1" 200 12 345 0.057`,
+			pattern: pattern,
+			want:    345,
+		},
+		{
+			name:    "hyphen",
+			line:    `127.0.0.1 - - [06/Sep/2015:05:58:05 +0900] "GET /foo HTTP/1.1" 200 12 - 0.057`,
+			pattern: pattern,
+			want:    0,
+		},
+		{
+			name:    "no subexp",
+			line:    `127.0.0.1 - - [06/Sep/2015:05:58:05 +0900] "GET /foo HTTP/1.1" 200 12 0.057`,
+			pattern: `^\S+ \S+ \S+ \[(?P<time>[^\]]+)\] "(?P<method>\S+) (?P<uri>\S+) \S+" (?P<status>\d+) (?P<body_bytes>\d+) (?P<response_time>[\d.]+)$`,
+			want:    0,
+		},
+		{
+			name:    "invalid request size",
+			line:    `127.0.0.1 - - [06/Sep/2015:05:58:05 +0900] "POST /foo HTTP/1.1" 200 12 abc 0.057`,
+			pattern: pattern,
+			err:     errors.SkipReadLineErr,
+		},
+	}
+
+	names := NewSubexpNames("uri", "method", "time", "response_time", "request_time", "body_bytes", "request_body_bytes", "status")
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parser, err := NewRegexpParser(strings.NewReader(tt.line+"\n"), tt.pattern, names, false, false)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			stat, err := parser.Parse()
+			if err != tt.err {
+				t.Fatalf("want: %v, got: %v", tt.err, err)
+			}
+			if err != nil {
+				return
+			}
+
+			if stat.RequestBodyBytes != tt.want {
+				t.Errorf("want: %v, got: %v", tt.want, stat.RequestBodyBytes)
+			}
+			if stat.BodyBytes != 12 {
+				t.Errorf("want the response body bytes: 12, got: %v", stat.BodyBytes)
+			}
+		})
+	}
+}
@@ -0,0 +1,137 @@
+package profiler
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/tkuchiki/alp/parsers"
+	"github.com/tkuchiki/alp/stats"
+)
+
+const metricsShutdownTimeout = 5 * time.Second
+
+// export keeps reading the appended lines like follow, and exposes the results as the Prometheus metrics on /metrics.
+// When interrupted, it dumps the results if p.options.Dump is specified.
+func (p *Profiler) export(sortOptions *stats.SortOptions, parser parsers.Parser) error {
+	if p.options.Load != "" {
+		return fmt.Errorf("--metrics-listen cannot be used with --load")
+	}
+
+	if p.followReader == nil {
+		return fmt.Errorf("--metrics-listen requires the file opened by Profiler.Open")
+	}
+
+	sts, err := p.newExportStats(sortOptions)
+	if err != nil {
+		return err
+	}
+	sts.SetParser(parserName(parser))
+
+	posfile, err := p.seekPosFile(parser)
+	if err != nil {
+		return err
+	}
+	if posfile != nil {
+		defer posfile.Close()
+	}
+
+	ln, err := net.Listen("tcp", p.options.MetricsListen)
+	if err != nil {
+		return err
+	}
+
+	var mu sync.Mutex
+	mux := http.NewServeMux()
+	mux.HandleFunc("/metrics", func(w http.ResponseWriter, r *http.Request) {
+		openMetrics := strings.Contains(r.Header.Get("Accept"), "application/openmetrics-text")
+
+		var buf bytes.Buffer
+		mu.Lock()
+		err := sts.WriteMetrics(&buf, openMetrics)
+		mu.Unlock()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		if openMetrics {
+			w.Header().Set("Content-Type", stats.OpenMetricsContentType)
+		} else {
+			w.Header().Set("Content-Type", stats.PrometheusContentType)
+		}
+		w.Write(buf.Bytes())
+	})
+
+	srv := &http.Server{Handler: mux}
+	srvErr := make(chan error, 1)
+	go func() {
+		srvErr <- srv.Serve(ln)
+	}()
+
+	fmt.Fprintf(p.errWriter, "Serving the metrics on http://%s/metrics\n", ln.Addr())
+
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(sigCh)
+
+	done := make(chan error, 1)
+	go func() {
+		done <- p.followLines(sts, parser, &mu)
+	}()
+
+	select {
+	case <-sigCh:
+		// read the rest of the lines, and stop at the end
+		p.followReader.Stop()
+		err = <-done
+	case err = <-done:
+	case err = <-srvErr:
+		p.followReader.Stop()
+		<-done
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), metricsShutdownTimeout)
+	defer cancel()
+	srv.Shutdown(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	return p.saveFollowed(sts, parser, posfile)
+}
+
+// newExportStats returns the stats of the exporter.
+// The percentiles are estimated by the sketch, because the exporter runs for a long time and the samples would grow with every request.
+func (p *Profiler) newExportStats(sortOptions *stats.SortOptions) (*stats.HTTPStats, error) {
+	err := stats.ValidateMetricsLabels(p.options.GroupBy)
+	if err != nil {
+		return nil, err
+	}
+
+	sts, err := p.newHTTPStats(sortOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	err = sts.SetPercentileEstimator(stats.PercentileEstimatorSketch, p.options.PercentileAccuracy)
+	if err != nil {
+		return nil, err
+	}
+
+	err = sts.SetResponseTimeBuckets(p.options.MetricsBuckets)
+	if err != nil {
+		return nil, err
+	}
+
+	return sts, nil
+}
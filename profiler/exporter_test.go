@@ -0,0 +1,32 @@
+package profiler
+
+import (
+	"io"
+	"testing"
+
+	"github.com/tkuchiki/alp/options"
+	"github.com/tkuchiki/alp/stats"
+)
+
+func TestNewExportStats(t *testing.T) {
+	p := NewProfiler(io.Discard, io.Discard, options.NewOptions(options.MetricsListen(":0")))
+	sts, err := p.newExportStats(stats.NewSortOptions())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 1000; i++ {
+		sts.Set("/foo", "GET", 200, float64(i)/1000, 10, 0)
+	}
+
+	// the exporter does not keep every response time
+	s := sts.Stats()[0]
+	if len(s.ResponseTime.Percentiles) != 0 || s.ResponseTime.Sketch == nil {
+		t.Errorf("want the sketch instead of %d samples", len(s.ResponseTime.Percentiles))
+	}
+
+	p = NewProfiler(io.Discard, io.Discard, options.NewOptions(options.MetricsListen(":0"), options.GroupBy([]string{"uri"})))
+	if _, err = p.newExportStats(stats.NewSortOptions()); err == nil {
+		t.Error("the group-by key of the label of the metrics must be an error")
+	}
+}
@@ -0,0 +1,175 @@
+package profiler
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/tkuchiki/alp/errors"
+	"github.com/tkuchiki/alp/parsers"
+	"github.com/tkuchiki/alp/stats"
+)
+
+// followPollInterval is the interval to check whether lines have been appended to the file
+const followPollInterval = 200 * time.Millisecond
+
+const clearScreen = "\033[H\033[2J"
+
+// follow keeps reading the appended lines, and prints the results every p.options.FollowInterval.
+// When interrupted, it prints the final results, and dumps them if p.options.Dump is specified.
+func (p *Profiler) follow(sortOptions *stats.SortOptions, parser parsers.Parser) error {
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(sigCh)
+
+	return p.followUntil(sortOptions, parser, sigCh)
+}
+
+// followUntil is the same as follow, except that it stops when stop receives the signal
+func (p *Profiler) followUntil(sortOptions *stats.SortOptions, parser parsers.Parser, stop <-chan os.Signal) error {
+	if p.followReader == nil {
+		return fmt.Errorf("--follow requires the file opened by Profiler.Open")
+	}
+
+	interval, err := time.ParseDuration(p.options.FollowInterval)
+	if err != nil {
+		return err
+	}
+
+	if interval <= 0 {
+		return fmt.Errorf("follow interval must be greater than 0, got %s", p.options.FollowInterval)
+	}
+
+	sts, err := p.newHTTPStats(sortOptions)
+	if err != nil {
+		return err
+	}
+	sts.SetParser(parserName(parser))
+
+	posfile, err := p.seekPosFile(parser)
+	if err != nil {
+		return err
+	}
+	if posfile != nil {
+		defer posfile.Close()
+	}
+
+	var mu sync.Mutex
+	done := make(chan error, 1)
+	go func() {
+		done <- p.followLines(sts, parser, &mu)
+	}()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+Loop:
+	for {
+		select {
+		case <-ticker.C:
+			mu.Lock()
+			p.printSnapshot(sts)
+			mu.Unlock()
+		case <-stop:
+			// read the rest of the lines, and stop at the end
+			p.followReader.Stop()
+			err = <-done
+			break Loop
+		case err = <-done:
+			break Loop
+		}
+	}
+
+	if err != nil {
+		return err
+	}
+
+	err = p.saveFollowed(sts, parser, posfile)
+	if err != nil {
+		return err
+	}
+
+	p.printSnapshot(sts)
+
+	return nil
+}
+
+// saveFollowed dumps the results if p.options.Dump is specified, and saves the position of the followed file
+func (p *Profiler) saveFollowed(sts *stats.HTTPStats, parser parsers.Parser, posfile *os.File) error {
+	if p.options.Dump != "" {
+		err := p.dump(sts)
+		if err != nil {
+			return err
+		}
+	}
+
+	if !p.options.NoSavePos && posfile != nil {
+		return p.writePosFile(posfile, parser.ReadBytes())
+	}
+
+	return nil
+}
+
+// followLines is the same as profileSequential, except that sts is locked by mu while it is updated
+func (p *Profiler) followLines(sts *stats.HTTPStats, parser parsers.Parser, mu sync.Locker) error {
+	for {
+		s, err := parser.Parse()
+		if err != nil {
+			if err == io.EOF {
+				return nil
+			} else if err == errors.SkipReadLineErr {
+				mu.Lock()
+				sts.SkipLine()
+				mu.Unlock()
+				continue
+			}
+
+			return err
+		}
+
+		mu.Lock()
+		err = p.set(sts, s)
+		mu.Unlock()
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func (p *Profiler) set(sts *stats.HTTPStats, s *parsers.ParsedHTTPStat) error {
+	b, err := sts.DoFilter(s)
+	if err != nil {
+		return err
+	}
+
+	if !b {
+		return nil
+	}
+
+	err = sts.SetWithTime(s.Uri, s.Method, s.Time, s.Entries.Values(p.options.GroupBy), s.Status, s.ResponseTime, s.BodyBytes, s.RequestBodyBytes)
+	if err != nil {
+		return err
+	}
+
+	if sts.CountUris() > p.options.Limit {
+		return fmt.Errorf("Too many URI's (%d or less)", p.options.Limit)
+	}
+
+	return nil
+}
+
+// printSnapshot prints the sorted snapshot of sts, and clears the screen before printing the table
+func (p *Profiler) printSnapshot(sts *stats.HTTPStats) {
+	snapshot := sts.Snapshot()
+	snapshot.SortWithOptions()
+
+	if p.options.Format == "table" {
+		fmt.Fprint(p.outWriter, clearScreen)
+	}
+
+	p.printer.Print(snapshot, nil)
+}
@@ -0,0 +1,201 @@
+package profiler
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/tkuchiki/alp/options"
+	"github.com/tkuchiki/alp/parsers"
+	"github.com/tkuchiki/alp/stats"
+)
+
+// syncBuffer is the buffer that the test reads while follow writes the results
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (sb *syncBuffer) Write(p []byte) (int, error) {
+	sb.mu.Lock()
+	defer sb.mu.Unlock()
+
+	return sb.buf.Write(p)
+}
+
+func (sb *syncBuffer) String() string {
+	sb.mu.Lock()
+	defer sb.mu.Unlock()
+
+	return sb.buf.String()
+}
+
+// lastTable returns the last table printed by follow, which is printed after the screen is cleared
+func (sb *syncBuffer) lastTable() string {
+	tables := strings.Split(sb.String(), clearScreen)
+	return tables[len(tables)-1]
+}
+
+func ltsvLines(uri string, n int) string {
+	var b strings.Builder
+	for i := 0; i < n; i++ {
+		fmt.Fprintf(&b, "time:2015-09-06T05:58:05+09:00\tmethod:GET\turi:%s\tstatus:200\tsize:10\tapptime:0.100\n", uri)
+	}
+
+	return b.String()
+}
+
+func appendLines(t *testing.T, filename, lines string) {
+	t.Helper()
+
+	f, err := os.OpenFile(filename, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	if _, err = f.WriteString(lines); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// startFollow opens opts.File, or in if it is empty, and follows it until stop receives the signal
+func startFollow(t *testing.T, out io.Writer, opts *options.Options, in *os.File, stop chan os.Signal) <-chan error {
+	t.Helper()
+
+	p := NewProfiler(out, io.Discard, opts)
+	p.SetInReader(in)
+	f, err := p.Open(opts.File)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { f.Close() })
+
+	label := parsers.NewLTSVLabel(opts.LTSV.UriLabel, opts.LTSV.MethodLabel, opts.LTSV.TimeLabel,
+		opts.LTSV.ApptimeLabel, opts.LTSV.ReqtimeLabel, opts.LTSV.SizeLabel, opts.LTSV.ReqsizeLabel, opts.LTSV.StatusLabel,
+	)
+	parser := parsers.NewLTSVParser(f, label, false, false)
+
+	done := make(chan error, 1)
+	go func() {
+		done <- p.followUntil(stats.NewSortOptions(), parser, stop)
+	}()
+
+	return done
+}
+
+func waitDone(t *testing.T, done <-chan error) {
+	t.Helper()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("follow does not stop")
+	}
+}
+
+func TestFollowRefresh(t *testing.T) {
+	tempDir := t.TempDir()
+	log := filepath.Join(tempDir, "access.log")
+	appendLines(t, log, ltsvLines("/foo", 2))
+
+	opts := options.NewOptions(options.File(log), options.Follow(true), options.FollowInterval("20ms"), options.Output("count,uri"))
+
+	var out syncBuffer
+	stop := make(chan os.Signal, 1)
+	done := startFollow(t, &out, opts, nil, stop)
+
+	waitFor(t, "the lines of the file", func() bool {
+		return strings.Contains(out.lastTable(), "| 2     | /foo |")
+	})
+
+	// the results are refreshed with the appended lines
+	appendLines(t, log, ltsvLines("/foo", 1)+ltsvLines("/bar", 1))
+	waitFor(t, "the appended lines", func() bool {
+		table := out.lastTable()
+		return strings.Contains(table, "| 3     | /foo |") && strings.Contains(table, "| 1     | /bar |")
+	})
+
+	stop <- os.Interrupt
+	waitDone(t, done)
+}
+
+func TestFollowStop(t *testing.T) {
+	tempDir := t.TempDir()
+	log := filepath.Join(tempDir, "access.log")
+	dump := filepath.Join(tempDir, "dump.yaml")
+	appendLines(t, log, ltsvLines("/foo", 2))
+
+	// the results are not printed until the final report
+	opts := options.NewOptions(options.File(log), options.Follow(true), options.FollowInterval("1h"), options.Output("count,uri"), options.Dump(dump))
+
+	var out syncBuffer
+	stop := make(chan os.Signal, 1)
+	done := startFollow(t, &out, opts, nil, stop)
+
+	appendLines(t, log, ltsvLines("/bar", 1))
+	stop <- os.Interrupt
+	waitDone(t, done)
+
+	// the final report has the lines appended before stopping
+	table := out.lastTable()
+	if !strings.Contains(table, "| 2     | /foo |") || !strings.Contains(table, "| 1     | /bar |") {
+		t.Errorf("want the final report, got:\n%s", out.String())
+	}
+
+	b, err := os.ReadFile(dump)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(b), "/bar") {
+		t.Errorf("want the final results in the dump, got:\n%s", b)
+	}
+}
+
+func TestFollowStopStdin(t *testing.T) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+
+	if _, err = w.WriteString(ltsvLines("/foo", 2)); err != nil {
+		t.Fatal(err)
+	}
+
+	opts := options.NewOptions(options.Follow(true), options.FollowInterval("1h"), options.Output("count,uri"))
+
+	var out syncBuffer
+	stop := make(chan os.Signal, 1)
+	done := startFollow(t, &out, opts, r, stop)
+
+	// stdin blocks in the read, because the writer is not closed
+	time.Sleep(100 * time.Millisecond)
+	stop <- os.Interrupt
+	waitDone(t, done)
+
+	if !strings.Contains(out.lastTable(), "| 2     | /foo |") {
+		t.Errorf("want the final report, got:\n%s", out.String())
+	}
+}
@@ -0,0 +1,221 @@
+package profiler
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+
+	"github.com/tkuchiki/alp/errors"
+	"github.com/tkuchiki/alp/parsers"
+	"github.com/tkuchiki/alp/stats"
+)
+
+const pipelineBatchSize = 1024
+
+type lineBatch struct {
+	seq   int
+	lines [][]byte
+}
+
+// shard is the result of aggregation by a worker.
+// firstSeen[i] is the line number where shard.stats[i] first appeared.
+type shard struct {
+	sts       *stats.HTTPStats
+	firstSeen []int
+}
+
+type pipelineError struct {
+	mu   sync.Mutex
+	line int
+	err  error
+	once sync.Once
+	done chan struct{}
+}
+
+func newPipelineError() *pipelineError {
+	return &pipelineError{
+		done: make(chan struct{}),
+	}
+}
+
+// set keeps the error of the earliest line so that the result does not depend on scheduling
+func (pe *pipelineError) set(line int, err error) {
+	pe.mu.Lock()
+	if pe.err == nil || line < pe.line {
+		pe.line = line
+		pe.err = err
+	}
+	pe.mu.Unlock()
+
+	pe.once.Do(func() {
+		close(pe.done)
+	})
+}
+
+// profileParallel reads lines on one goroutine, and parses, filters and aggregates them on p.options.Workers goroutines.
+// Batches of lines are distributed to the workers in round-robin, and the shards are merged in the order of the first
+// appearance of each URI, so the result is the same as profileSequential except for the rounding error of the sums.
+func (p *Profiler) profileParallel(sts *stats.HTTPStats, sortOptions *stats.SortOptions, parser parsers.LineParser) error {
+	workers := p.options.Workers
+
+	shards := make([]*shard, workers)
+	chans := make([]chan *lineBatch, workers)
+	for i := 0; i < workers; i++ {
+		shardSts, err := p.newHTTPStats(sortOptions)
+		if err != nil {
+			return err
+		}
+
+		shards[i] = &shard{sts: shardSts}
+		chans[i] = make(chan *lineBatch, 2)
+	}
+
+	perr := newPipelineError()
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func(sh *shard, ch <-chan *lineBatch) {
+			defer wg.Done()
+			p.aggregate(parser, sh, ch, perr)
+		}(shards[i], chans[i])
+	}
+
+	p.readLines(sts, parser, chans, perr)
+
+	for _, ch := range chans {
+		close(ch)
+	}
+	wg.Wait()
+
+	if perr.err != nil {
+		return perr.err
+	}
+
+	return p.mergeShards(sts, shards)
+}
+
+// readLines counts the skipped lines in sts, which is not updated by the workers until they are merged
+func (p *Profiler) readLines(sts *stats.HTTPStats, parser parsers.LineParser, chans []chan *lineBatch, perr *pipelineError) {
+	seq := 0
+	batch := &lineBatch{seq: seq}
+
+	send := func() bool {
+		select {
+		case chans[batch.seq%len(chans)] <- batch:
+		case <-perr.done:
+			return false
+		}
+
+		seq++
+		batch = &lineBatch{seq: seq}
+
+		return true
+	}
+
+	for {
+		b, err := parser.ReadLine()
+		if err != nil {
+			if err == io.EOF {
+				break
+			} else if err == errors.SkipReadLineErr {
+				sts.SkipLine()
+				continue
+			}
+
+			perr.set(seq*pipelineBatchSize+len(batch.lines), err)
+			return
+		}
+
+		batch.lines = append(batch.lines, b)
+		if len(batch.lines) >= pipelineBatchSize {
+			if !send() {
+				return
+			}
+		}
+	}
+
+	if len(batch.lines) > 0 {
+		send()
+	}
+}
+
+func (p *Profiler) aggregate(parser parsers.LineParser, sh *shard, ch <-chan *lineBatch, perr *pipelineError) {
+	for batch := range ch {
+		select {
+		case <-perr.done:
+			continue
+		default:
+		}
+
+		for i, line := range batch.lines {
+			lineNum := batch.seq*pipelineBatchSize + i
+
+			s, err := parser.ParseLine(line)
+			if err == errors.SkipReadLineErr {
+				sh.sts.SkipLine()
+				continue
+			} else if err != nil {
+				perr.set(lineNum, err)
+				break
+			}
+
+			b, err := sh.sts.DoFilter(s)
+			if err != nil {
+				perr.set(lineNum, err)
+				break
+			}
+
+			if !b {
+				continue
+			}
+
+			n := sh.sts.CountUris()
+			err = sh.sts.SetWithTime(s.Uri, s.Method, s.Time, s.Entries.Values(p.options.GroupBy), s.Status, s.ResponseTime, s.BodyBytes, s.RequestBodyBytes)
+			if err != nil {
+				perr.set(lineNum, err)
+				break
+			}
+			if sh.sts.CountUris() > n {
+				sh.firstSeen = append(sh.firstSeen, lineNum)
+			}
+
+			if sh.sts.CountUris() > p.options.Limit {
+				perr.set(lineNum, fmt.Errorf("Too many URI's (%d or less)", p.options.Limit))
+				break
+			}
+		}
+	}
+}
+
+func (p *Profiler) mergeShards(sts *stats.HTTPStats, shards []*shard) error {
+	type entry struct {
+		stat      *stats.HTTPStat
+		firstSeen int
+	}
+
+	entries := make([]entry, 0)
+	for _, sh := range shards {
+		sts.MergeLogSummary(sh.sts.LogSummary())
+		for i, s := range sh.sts.Stats() {
+			entries = append(entries, entry{stat: s, firstSeen: sh.firstSeen[i]})
+		}
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].firstSeen < entries[j].firstSeen
+	})
+
+	for _, e := range entries {
+		if err := sts.MergeStat(e.stat); err != nil {
+			return err
+		}
+
+		if sts.CountUris() > p.options.Limit {
+			return fmt.Errorf("Too many URI's (%d or less)", p.options.Limit)
+		}
+	}
+
+	return nil
+}
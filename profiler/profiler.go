@@ -1,30 +1,38 @@
 package profiler
 
 import (
-	"bufio"
 	"fmt"
 	"io"
 	"os"
+	"slices"
 
 	"github.com/tkuchiki/alp/errors"
 	"github.com/tkuchiki/alp/helpers"
+	"github.com/tkuchiki/alp/openapi"
 	"github.com/tkuchiki/alp/options"
 	"github.com/tkuchiki/alp/parsers"
 	"github.com/tkuchiki/alp/stats"
 )
 
 type Profiler struct {
-	options     *options.Options
-	outWriter   io.Writer
-	errWriter   io.Writer
-	inReader    *os.File
-	printer     *stats.Printer
-	loadEnabled bool
+	options      *options.Options
+	outWriter    io.Writer
+	errWriter    io.Writer
+	inReader     *os.File
+	printer      *stats.Printer
+	loadEnabled  bool
+	posTracker   *helpers.PositionTracker
+	followReader *helpers.FollowReader
+	routes       []*openapi.Route
 }
 
 func NewProfiler(outw, errw io.Writer, opts *options.Options) *Profiler {
-	printOptions := stats.NewPrintOptions(opts.NoHeaders, opts.ShowFooters, opts.DecodeUri, opts.PaginationLimit)
-	printer := stats.NewPrinter(outw, opts.Output, opts.Format, opts.Percentiles, printOptions)
+	printOptions := stats.NewPrintOptions(opts.NoHeaders, opts.ShowFooters, opts.DecodeUri, opts.PaginationLimit, opts.Timeline != "")
+	printer := stats.NewPrinter(outw, opts.Output, opts.Format, opts.Percentiles, opts.StatusCodes, opts.GroupBy, printOptions)
+	printer.SetSignificanceLevel(opts.SignificanceLevel)
+	if opts.Histogram {
+		printer.SetHistogram(opts.HistogramBuckets, opts.HistogramScale, opts.HistogramUri)
+	}
 
 	return &Profiler{
 		options:     opts,
@@ -40,17 +48,27 @@ func (p *Profiler) SetInReader(f *os.File) {
 	p.inReader = f
 }
 
-func (p *Profiler) Open(filename string) (*os.File, error) {
-	var f *os.File
-	var err error
+func (p *Profiler) Open(filename string) (io.ReadCloser, error) {
+	r, err := p.open(filename)
+	if err != nil || (!p.options.Follow && p.options.MetricsListen == "") {
+		return r, err
+	}
+
+	p.followReader = helpers.NewFollowReader(r, followPollInterval)
+
+	return p.followReader, nil
+}
+
+func (p *Profiler) open(filename string) (io.ReadCloser, error) {
+	if filename != "" && p.options.PosFile != "" {
+		return p.openWithPosFile(filename)
+	}
 
 	if filename != "" {
-		f, err = os.Open(filename)
-	} else {
-		f = p.inReader
+		return helpers.OpenFiles(filename)
 	}
 
-	return f, err
+	return helpers.NewDecompressReader(p.inReader), nil
 }
 
 func (p *Profiler) OpenPosFile(filename string) (*os.File, error) {
@@ -58,13 +76,68 @@ func (p *Profiler) OpenPosFile(filename string) (*os.File, error) {
 }
 
 func (p *Profiler) ReadPosFile(f *os.File) (int, error) {
-	reader := bufio.NewReader(f)
-	pos, _, err := reader.ReadLine()
+	pos, err := helpers.ReadPosition(f)
 	if err != nil {
 		return 0, err
 	}
 
-	return helpers.StringToInt(string(pos))
+	return int(pos.Offset), nil
+}
+
+// openWithPosFile opens the file that resumes from the position file, even if the file has been rotated.
+// Multiple files are read from the beginning, and skipped by the number of bytes in the position file.
+func (p *Profiler) openWithPosFile(filename string) (io.ReadCloser, error) {
+	files, err := helpers.ExpandFiles(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(files) > 1 {
+		return helpers.OpenFiles(filename)
+	}
+
+	pos := &helpers.Position{}
+	posfile, err := os.Open(p.options.PosFile)
+	if err == nil {
+		pos, err = helpers.ReadPosition(posfile)
+		posfile.Close()
+		if err != nil {
+			return nil, err
+		}
+	} else if !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	r, tracker, err := helpers.OpenFileWithPosition(files[0], pos)
+	if err != nil {
+		return nil, err
+	}
+	p.posTracker = tracker
+
+	return r, nil
+}
+
+func (p *Profiler) writePosFile(posfile *os.File, readBytes int) error {
+	pos := &helpers.Position{Offset: int64(readBytes)}
+	if p.posTracker != nil {
+		var err error
+		pos, err = p.posTracker.Position()
+		if err != nil {
+			return err
+		}
+	}
+
+	err := posfile.Truncate(0)
+	if err != nil {
+		return err
+	}
+
+	_, err = posfile.Seek(0, 0)
+	if err != nil {
+		return err
+	}
+
+	return pos.Write(posfile)
 }
 
 func (p *Profiler) SetPrinter(printer *stats.Printer) {
@@ -92,34 +165,127 @@ func (p *Profiler) Load(sortOptions *stats.SortOptions) (*stats.HTTPStats, error
 
 	sts.SetOptions(p.options)
 	sts.SetSortOptions(sortOptions)
+	sts.SetGroupBy(p.options.GroupBy)
 
-	lf, err := os.Open(p.options.Load)
+	// the comma separated dumps are merged, e.g. the dumps taken from several servers behind a load balancer
+	files, err := helpers.ExpandFiles(p.options.Load)
 	if err != nil {
 		return nil, err
 	}
-	err = sts.LoadStats(lf)
-	if err != nil {
-		return nil, err
+
+	for _, file := range files {
+		warnings, err := loadStats(sts, file)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, w := range warnings {
+			fmt.Fprintf(p.errWriter, "Warning: %s: %s\n", file, w)
+		}
 	}
-	defer lf.Close()
 
 	sts.SortWithOptions()
 
 	return sts, nil
 }
 
-func (p *Profiler) profile(sortOptions *stats.SortOptions, parser parsers.Parser) (*stats.HTTPStats, error) {
-	sts := stats.NewHTTPStats(true, false, false)
+// dump writes sts to p.options.Dump in the format of p.options.DumpFormat or the extension of the file
+func (p *Profiler) dump(sts *stats.HTTPStats) error {
+	format, err := stats.DumpFormat(p.options.DumpFormat, p.options.Dump)
+	if err != nil {
+		return err
+	}
 
-	if p.options.Load != "" && p.loadEnabled {
-		return p.Load(sortOptions)
+	df, err := os.OpenFile(p.options.Dump, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	defer df.Close()
+
+	return sts.DumpStatsWithFormat(df, format)
+}
+
+// parserName returns the format of the logs that parser reads, and is written to the metadata of the dump
+func parserName(parser parsers.Parser) string {
+	switch parser.(type) {
+	case *parsers.JSONParser:
+		return "json"
+	case *parsers.LTSVParser:
+		return "ltsv"
+	case *parsers.RegexpParser:
+		return "regexp"
+	case *parsers.PcapParser:
+		return "pcap"
+	}
+
+	return ""
+}
+
+// loadStats loads the dump of filename into sts, and returns the warnings if it is aggregated differently from the dumps loaded before
+func loadStats(sts *stats.HTTPStats, filename string) ([]string, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return sts.LoadStatsWithWarnings(f)
+}
+
+func (p *Profiler) newHTTPStats(sortOptions *stats.SortOptions) (*stats.HTTPStats, error) {
+	// the body bytes are kept to calculate the percentiles only when they are printed or sorted
+	var sortType string
+	if sortOptions != nil {
+		sortType = sortOptions.SortType()
 	}
+	useReqBodyPercentile := p.printer.UseRequestBodyBytesPercentile() ||
+		sortType == stats.SortPNRequestBodyBytes || sortType == stats.SortStddevRequestBodyBytes
+	useResBodyPercentile := p.printer.UseResponseBodyBytesPercentile() ||
+		sortType == stats.SortPNResponseBodyBytes || sortType == stats.SortStddevResponseBodyBytes
+	sts := stats.NewHTTPStats(true, useReqBodyPercentile, useResBodyPercentile)
 
 	err := sts.InitFilter(p.options)
 	if err != nil {
 		return nil, err
 	}
 
+	err = sts.SetPercentileEstimator(p.options.PercentileEstimator, p.options.PercentileAccuracy)
+	if err != nil {
+		return nil, err
+	}
+
+	err = sts.SetTimelineInterval(p.options.Timeline)
+	if err != nil {
+		return nil, err
+	}
+
+	sts.SetGroupBy(p.options.GroupBy)
+
+	// the exact status codes are counted only when they are printed or sorted
+	statusCodes := p.options.StatusCodes
+	if sortType == stats.SortStatusCode && !slices.Contains(statusCodes, sortOptions.StatusCode()) {
+		statusCodes = append(slices.Clone(statusCodes), sortOptions.StatusCode())
+	}
+	sts.SetStatusCodes(statusCodes)
+
+	err = sts.SetApdexThreshold(p.options.ApdexThreshold)
+	if err != nil {
+		return nil, err
+	}
+
+	if p.printer.UseRequestsPerSecond() || (sortOptions != nil && sortOptions.SortType() == stats.SortRequestsPerSecond) {
+		sts.EnableRequestsPerSecond()
+	}
+
+	if p.printer.UseRequestsOverTime() {
+		sts.EnableRequestsOverTime()
+	}
+
+	// the time range of the logs is written to the metadata of the dump
+	if p.options.Dump != "" {
+		sts.EnableLogTimeRange()
+	}
+
 	sts.SetOptions(p.options)
 	sts.SetSortOptions(sortOptions)
 
@@ -130,27 +296,104 @@ func (p *Profiler) profile(sortOptions *stats.SortOptions, parser parsers.Parser
 		}
 	}
 
-	var posfile *os.File
-	if p.options.PosFile != "" {
-		posfile, err = p.OpenPosFile(p.options.PosFile)
-		if err != nil {
-			return nil, err
-		}
+	routes, err := p.loadRoutes()
+	if err != nil {
+		return nil, err
+	}
+	sts.SetRoutes(routes)
+
+	return sts, nil
+}
+
+// loadRoutes loads the routes of the OpenAPI document only once, because the shards of --workers share them
+func (p *Profiler) loadRoutes() ([]*openapi.Route, error) {
+	if p.options.OpenAPI == "" || p.routes != nil {
+		return p.routes, nil
+	}
+
+	routes, err := openapi.Load(p.options.OpenAPI)
+	if err != nil {
+		return nil, err
+	}
+	p.routes = routes
+
+	return routes, nil
+}
+
+func (p *Profiler) profile(sortOptions *stats.SortOptions, parser parsers.Parser) (*stats.HTTPStats, error) {
+	if p.options.Load != "" && p.loadEnabled {
+		return p.Load(sortOptions)
+	}
+
+	sts, err := p.newHTTPStats(sortOptions)
+	if err != nil {
+		return nil, err
+	}
+	sts.SetParser(parserName(parser))
+
+	posfile, err := p.seekPosFile(parser)
+	if err != nil {
+		return nil, err
+	}
+	if posfile != nil {
 		defer posfile.Close()
+	}
 
-		pos, err := p.ReadPosFile(posfile)
-		if err != nil && err != io.EOF {
-			return nil, err
-		}
+	lineParser, ok := parser.(parsers.LineParser)
+	if ok && p.options.Workers > 1 {
+		err = p.profileParallel(sts, sortOptions, lineParser)
+	} else {
+		err = p.profileSequential(sts, parser)
+	}
+	if err != nil {
+		return nil, err
+	}
 
-		err = parser.Seek(pos)
+	if !p.options.NoSavePos && p.options.PosFile != "" {
+		err = p.writePosFile(posfile, parser.ReadBytes())
 		if err != nil {
 			return nil, err
 		}
+	}
 
-		parser.SetReadBytes(pos)
+	return sts, nil
+}
+
+// seekPosFile opens the position file, and skips the lines that have been read.
+// It returns nil if the position file is not specified.
+func (p *Profiler) seekPosFile(parser parsers.Parser) (*os.File, error) {
+	if p.options.PosFile == "" {
+		return nil, nil
+	}
+
+	posfile, err := p.OpenPosFile(p.options.PosFile)
+	if err != nil {
+		return nil, err
+	}
+
+	// the file opened with the position file has been seeked already
+	if p.posTracker != nil {
+		return posfile, nil
+	}
+
+	pos, err := p.ReadPosFile(posfile)
+	if err != nil && err != io.EOF {
+		posfile.Close()
+		return nil, err
 	}
 
+	err = parser.Seek(pos)
+	if err != nil {
+		posfile.Close()
+		return nil, err
+	}
+
+	parser.SetReadBytes(pos)
+
+	return posfile, nil
+}
+
+func (p *Profiler) profileSequential(sts *stats.HTTPStats, parser parsers.Parser) error {
 Loop:
 	for {
 		s, err := parser.Parse()
@@ -158,38 +401,34 @@ Loop:
 			if err == io.EOF {
 				break
 			} else if err == errors.SkipReadLineErr {
+				sts.SkipLine()
 				continue Loop
 			}
 
-			return nil, err
+			return err
 		}
 
 		var b bool
 		b, err = sts.DoFilter(s)
 		if err != nil {
-			return nil, err
+			return err
 		}
 
 		if !b {
 			continue Loop
 		}
 
-		sts.Set(s.Uri, s.Method, s.Status, s.ResponseTime, s.BodyBytes, 0)
-
-		if sts.CountUris() > p.options.Limit {
-			return nil, fmt.Errorf("Too many URI's (%d or less)", p.options.Limit)
+		err = sts.SetWithTime(s.Uri, s.Method, s.Time, s.Entries.Values(p.options.GroupBy), s.Status, s.ResponseTime, s.BodyBytes, s.RequestBodyBytes)
+		if err != nil {
+			return err
 		}
-	}
 
-	if !p.options.NoSavePos && p.options.PosFile != "" {
-		posfile.Seek(0, 0)
-		_, err = posfile.Write([]byte(fmt.Sprint(parser.ReadBytes())))
-		if err != nil {
-			return nil, err
+		if sts.CountUris() > p.options.Limit {
+			return fmt.Errorf("Too many URI's (%d or less)", p.options.Limit)
 		}
 	}
 
-	return sts, nil
+	return nil
 }
 
 func (p *Profiler) Profile(sortOptions *stats.SortOptions, parser parsers.Parser) (*stats.HTTPStats, error) {
@@ -204,16 +443,71 @@ func (p *Profiler) Profile(sortOptions *stats.SortOptions, parser parsers.Parser
 }
 
 func (p *Profiler) Run(sortOptions *stats.SortOptions, parser parsers.Parser, from *stats.HTTPStats) error {
+	// validate the dump format before profiling
+	if _, err := stats.DumpFormat(p.options.DumpFormat, p.options.Dump); err != nil {
+		return err
+	}
+
+	if p.options.Format == "tui" {
+		if from != nil {
+			return fmt.Errorf("--format tui cannot be used with diff")
+		}
+
+		return p.browse(sortOptions, parser)
+	}
+
+	if p.options.Format == "sqlite" {
+		if from != nil {
+			return fmt.Errorf("--format sqlite cannot be used with diff")
+		}
+
+		return p.exportSQLite(sortOptions, parser)
+	}
+
+	if p.options.Histogram {
+		if from != nil {
+			return fmt.Errorf("--histogram cannot be used with diff")
+		}
+
+		// the histogram needs every response time, which the sketch does not retain
+		if p.options.PercentileEstimator == stats.PercentileEstimatorSketch {
+			return fmt.Errorf("--histogram cannot be used with --percentile-estimator %s", stats.PercentileEstimatorSketch)
+		}
+	}
+
+	// the timeline buckets are not compared between the two results
+	if p.options.Timeline != "" && from != nil {
+		return fmt.Errorf("--timeline cannot be used with diff")
+	}
+
+	if p.options.MetricsListen != "" && from == nil {
+		return p.export(sortOptions, parser)
+	}
+
+	if p.options.Follow && p.options.Load == "" && from == nil {
+		return p.follow(sortOptions, parser)
+	}
+
 	sts, err := p.profile(sortOptions, parser)
 	if err != nil {
 		return err
 	}
 
+	if from != nil {
+		// to sort by the relative changes from the stats of from
+		sts.SetDiffFrom(from)
+
+		for _, w := range stats.GroupingWarnings(from.Metadata(), sts.Metadata()) {
+			fmt.Fprintf(p.errWriter, "Warning: %s\n", w)
+		}
+	}
+
 	if p.options.Load != "" {
 		if from == nil {
 			p.printer.Print(sts, nil)
 		} else {
 			// diff
+			sts.SortWithOptions()
 			p.printer.Print(from, sts)
 		}
 
@@ -221,12 +515,10 @@ func (p *Profiler) Run(sortOptions *stats.SortOptions, parser parsers.Parser, fr
 	}
 
 	if p.options.Dump != "" {
-		df, err := os.OpenFile(p.options.Dump, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0644)
-		err = sts.DumpStats(df)
+		err = p.dump(sts)
 		if err != nil {
 			return err
 		}
-		defer df.Close()
 	}
 
 	sts.SortWithOptions()
@@ -0,0 +1,118 @@
+package profiler
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/tkuchiki/alp/options"
+	"github.com/tkuchiki/alp/stats"
+)
+
+func TestNewHTTPStatsStatusCodes(t *testing.T) {
+	tests := []struct {
+		name        string
+		statusCodes []int
+		sort        string
+		want        map[int]int
+	}{
+		{
+			name: "no status codes",
+			sort: "count",
+		},
+		{
+			name:        "status codes",
+			statusCodes: []int{200},
+			sort:        "count",
+			want:        map[int]int{200: 2},
+		},
+		{
+			// the status code of the sort key is counted as well
+			name:        "sort by the status code",
+			statusCodes: []int{200},
+			sort:        "404",
+			want:        map[int]int{200: 2, 404: 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := options.NewOptions(options.StatusCodes(tt.statusCodes))
+			p := NewProfiler(io.Discard, io.Discard, opts)
+
+			sortOptions := stats.NewSortOptions()
+			if err := sortOptions.SetAndValidate(tt.sort); err != nil {
+				t.Fatal(err)
+			}
+
+			sts, err := p.newHTTPStats(sortOptions)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for _, status := range []int{200, 200, 404, 502} {
+				sts.Set("/foo", "GET", status, 0.1, 0, 0)
+			}
+
+			if got := sts.Stats()[0].StatusCodes; !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("want: %v, got: %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestLoadWarnings(t *testing.T) {
+	dir := t.TempDir()
+	dump := func(name string, opts *options.Options) string {
+		sts := stats.NewHTTPStats(true, false, false)
+		sts.SetOptions(opts)
+		sts.Set("/foo", "GET", 200, 0.1, 0, 0)
+
+		filename := filepath.Join(dir, name)
+		f, err := os.Create(filename)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer f.Close()
+
+		if err = sts.DumpStats(f); err != nil {
+			t.Fatal(err)
+		}
+
+		return filename
+	}
+
+	dump1 := dump("dump1.yaml", options.NewOptions())
+	dump2 := dump("dump2.yaml", options.NewOptions(options.GroupBy([]string{"host"})))
+
+	// the dumps aggregated by the different keys are merged with the warnings
+	var errBuf bytes.Buffer
+	p := NewProfiler(io.Discard, &errBuf, options.NewOptions(options.Load(dump1+","+dump2)))
+	sts, err := p.Load(stats.NewSortOptions())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if sts.Stats()[0].Cnt != 2 {
+		t.Errorf("count want: 2, got: %d", sts.Stats()[0].Cnt)
+	}
+
+	want := fmt.Sprintf("Warning: %s: the group by keys are different: [] and [host]\n", dump2)
+	if got := errBuf.String(); got != want {
+		t.Errorf("want: %s, got: %s", want, got)
+	}
+}
+
+func TestRunTimelineDiff(t *testing.T) {
+	p := NewProfiler(io.Discard, io.Discard, options.NewOptions(options.Timeline("1m")))
+
+	// the time column of the buckets would be empty in the diff
+	err := p.Run(stats.NewSortOptions(), nil, stats.NewHTTPStats(true, false, false))
+	if err == nil || err.Error() != "--timeline cannot be used with diff" {
+		t.Errorf("want the error of --timeline with diff, got: %v", err)
+	}
+}
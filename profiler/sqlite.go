@@ -0,0 +1,272 @@
+package profiler
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/tkuchiki/alp/errors"
+	"github.com/tkuchiki/alp/parsers"
+	"github.com/tkuchiki/alp/sqlite"
+	"github.com/tkuchiki/alp/stats"
+)
+
+// sqliteTimeFormat is the time format that the date and time functions of SQLite can read, and is sorted as text
+const sqliteTimeFormat = "2006-01-02T15:04:05.000Z07:00"
+
+var requestColumns = []sqlite.Column{
+	{Name: "id", Type: sqlite.TypeInteger, PrimaryKey: true},
+	{Name: "time", Type: sqlite.TypeText},
+	{Name: "method", Type: sqlite.TypeText},
+	{Name: "uri", Type: sqlite.TypeText},
+	{Name: "status", Type: sqlite.TypeInteger},
+	{Name: "response_time", Type: sqlite.TypeReal},
+	{Name: "request_body_bytes", Type: sqlite.TypeReal},
+	{Name: "response_body_bytes", Type: sqlite.TypeReal},
+	{Name: "entries", Type: sqlite.TypeText},
+}
+
+// statColumns returns the columns of the aggregated results, which are named after the keywords of --output
+func (p *Profiler) statColumns() []sqlite.Column {
+	columns := []sqlite.Column{
+		{Name: "id", Type: sqlite.TypeInteger, PrimaryKey: true},
+		{Name: "method", Type: sqlite.TypeText},
+		{Name: "uri", Type: sqlite.TypeText},
+	}
+
+	for _, key := range p.options.GroupBy {
+		columns = append(columns, sqlite.Column{Name: key, Type: sqlite.TypeText})
+	}
+
+	columns = append(columns,
+		sqlite.Column{Name: "count", Type: sqlite.TypeInteger},
+		sqlite.Column{Name: "status_1xx", Type: sqlite.TypeInteger},
+		sqlite.Column{Name: "status_2xx", Type: sqlite.TypeInteger},
+		sqlite.Column{Name: "status_3xx", Type: sqlite.TypeInteger},
+		sqlite.Column{Name: "status_4xx", Type: sqlite.TypeInteger},
+		sqlite.Column{Name: "status_5xx", Type: sqlite.TypeInteger},
+		sqlite.Column{Name: "min", Type: sqlite.TypeReal},
+		sqlite.Column{Name: "max", Type: sqlite.TypeReal},
+		sqlite.Column{Name: "sum", Type: sqlite.TypeReal},
+		sqlite.Column{Name: "avg", Type: sqlite.TypeReal},
+		sqlite.Column{Name: "stddev", Type: sqlite.TypeReal},
+	)
+
+	for _, n := range p.options.Percentiles {
+		columns = append(columns, sqlite.Column{Name: fmt.Sprintf("p%d", n), Type: sqlite.TypeReal})
+	}
+
+	return append(columns,
+		sqlite.Column{Name: "min_body", Type: sqlite.TypeReal},
+		sqlite.Column{Name: "max_body", Type: sqlite.TypeReal},
+		sqlite.Column{Name: "sum_body", Type: sqlite.TypeReal},
+		sqlite.Column{Name: "avg_body", Type: sqlite.TypeReal},
+		sqlite.Column{Name: "min_req_body", Type: sqlite.TypeReal},
+		sqlite.Column{Name: "max_req_body", Type: sqlite.TypeReal},
+		sqlite.Column{Name: "sum_req_body", Type: sqlite.TypeReal},
+		sqlite.Column{Name: "avg_req_body", Type: sqlite.TypeReal},
+	)
+}
+
+func (p *Profiler) statValues(s *stats.HTTPStat) []interface{} {
+	values := []interface{}{nil, s.Method, s.Uri}
+
+	for i := range p.options.GroupBy {
+		values = append(values, s.Group(i))
+	}
+
+	values = append(values,
+		s.Count(),
+		s.Status1xx,
+		s.Status2xx,
+		s.Status3xx,
+		s.Status4xx,
+		s.Status5xx,
+		s.MinResponseTime(),
+		s.MaxResponseTime(),
+		s.SumResponseTime(),
+		s.AvgResponseTime(),
+		s.StddevResponseTime(),
+	)
+
+	for _, n := range p.options.Percentiles {
+		values = append(values, s.PNResponseTime(n))
+	}
+
+	return append(values,
+		s.MinResponseBodyBytes(),
+		s.MaxResponseBodyBytes(),
+		s.SumResponseBodyBytes(),
+		s.AvgResponseBodyBytes(),
+		s.MinRequestBodyBytes(),
+		s.MaxRequestBodyBytes(),
+		s.SumRequestBodyBytes(),
+		s.AvgRequestBodyBytes(),
+	)
+}
+
+// requestValues returns the values of the request, and the time is NULL if it cannot be parsed
+func requestValues(sts *stats.HTTPStats, s *parsers.ParsedHTTPStat) ([]interface{}, error) {
+	var timestr interface{}
+	if s.Time != "" {
+		if t, err := sts.ParseTime(s.Time); err == nil {
+			timestr = t.Format(sqliteTimeFormat)
+		}
+	}
+
+	var entries interface{}
+	if len(s.Entries) > 0 {
+		b, err := json.Marshal(s.Entries)
+		if err != nil {
+			return nil, err
+		}
+		entries = string(b)
+	}
+
+	return []interface{}{
+		nil,
+		timestr,
+		s.Method,
+		s.Uri,
+		s.Status,
+		s.ResponseTime,
+		s.RequestBodyBytes,
+		s.BodyBytes,
+		entries,
+	}, nil
+}
+
+// exportSQLite writes every request and the aggregated results to the SQLite database of --dump,
+// so that they can be queried with SQL
+func (p *Profiler) exportSQLite(sortOptions *stats.SortOptions, parser parsers.Parser) error {
+	if p.options.Dump == "" {
+		return fmt.Errorf("--format sqlite requires --dump to write the database")
+	}
+
+	if p.options.Load != "" {
+		return fmt.Errorf("--format sqlite cannot be used with --load")
+	}
+
+	if p.options.Follow || p.options.MetricsListen != "" {
+		return fmt.Errorf("--format sqlite cannot be used with --follow and --metrics-listen")
+	}
+
+	sts, err := p.newHTTPStats(sortOptions)
+	if err != nil {
+		return err
+	}
+	sts.SetParser(parserName(parser))
+
+	posfile, err := p.seekPosFile(parser)
+	if err != nil {
+		return err
+	}
+	if posfile != nil {
+		defer posfile.Close()
+	}
+
+	db, err := sqlite.Create(p.options.Dump)
+	if err != nil {
+		return err
+	}
+
+	err = p.writeSQLite(db, sts, parser)
+	if err != nil {
+		db.Close()
+		return err
+	}
+
+	err = db.Close()
+	if err != nil {
+		return err
+	}
+
+	if !p.options.NoSavePos && posfile != nil {
+		err = p.writePosFile(posfile, parser.ReadBytes())
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// writeSQLite writes the requests to the requests table while profiling them, and then the results to the stats table
+func (p *Profiler) writeSQLite(db *sqlite.DB, sts *stats.HTTPStats, parser parsers.Parser) error {
+	requests, err := db.CreateTable("requests", requestColumns...)
+	if err != nil {
+		return err
+	}
+
+	for _, column := range []string{"uri", "method", "time"} {
+		_, err = requests.CreateIndex("requests_"+column, column)
+		if err != nil {
+			return err
+		}
+	}
+
+	statsTable, err := db.CreateTable("stats", p.statColumns()...)
+	if err != nil {
+		return err
+	}
+
+	for _, column := range []string{"uri", "method"} {
+		_, err = statsTable.CreateIndex("stats_"+column, column)
+		if err != nil {
+			return err
+		}
+	}
+
+	for {
+		s, err := parser.Parse()
+		if err != nil {
+			if err == io.EOF {
+				break
+			} else if err == errors.SkipReadLineErr {
+				sts.SkipLine()
+				continue
+			}
+
+			return err
+		}
+
+		b, err := sts.DoFilter(s)
+		if err != nil {
+			return err
+		}
+
+		if !b {
+			continue
+		}
+
+		err = sts.SetWithTime(s.Uri, s.Method, s.Time, s.Entries.Values(p.options.GroupBy), s.Status, s.ResponseTime, s.BodyBytes, s.RequestBodyBytes)
+		if err != nil {
+			return err
+		}
+
+		if sts.CountUris() > p.options.Limit {
+			return fmt.Errorf("Too many URI's (%d or less)", p.options.Limit)
+		}
+
+		values, err := requestValues(sts, s)
+		if err != nil {
+			return err
+		}
+
+		err = requests.Insert(values...)
+		if err != nil {
+			return err
+		}
+	}
+
+	sts.SortWithOptions()
+
+	for _, s := range sts.Stats() {
+		err = statsTable.Insert(p.statValues(s)...)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,84 @@
+package profiler
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/tkuchiki/alp/errors"
+	"github.com/tkuchiki/alp/parsers"
+	"github.com/tkuchiki/alp/stats"
+	"github.com/tkuchiki/alp/tui"
+)
+
+// browse reads all the requests, and shows the results in the terminal UI.
+// Unlike the other formats, every request is kept in memory to filter and list them in the terminal UI.
+func (p *Profiler) browse(sortOptions *stats.SortOptions, parser parsers.Parser) error {
+	if p.options.Load != "" {
+		return fmt.Errorf("--format tui cannot be used with --load")
+	}
+
+	if p.options.Follow || p.options.MetricsListen != "" {
+		return fmt.Errorf("--format tui cannot be used with --follow and --metrics-listen")
+	}
+
+	sts, err := p.newHTTPStats(sortOptions)
+	if err != nil {
+		return err
+	}
+
+	posfile, err := p.seekPosFile(parser)
+	if err != nil {
+		return err
+	}
+	if posfile != nil {
+		defer posfile.Close()
+	}
+
+	requests := make([]*parsers.ParsedHTTPStat, 0)
+	for {
+		s, err := parser.Parse()
+		if err != nil {
+			if err == io.EOF {
+				break
+			} else if err == errors.SkipReadLineErr {
+				continue
+			}
+
+			return err
+		}
+
+		b, err := sts.DoFilter(s)
+		if err != nil {
+			return err
+		}
+
+		if !b {
+			continue
+		}
+
+		requests = append(requests, s)
+	}
+
+	if !p.options.NoSavePos && posfile != nil {
+		err = p.writePosFile(posfile, parser.ReadBytes())
+		if err != nil {
+			return err
+		}
+	}
+
+	routes, err := p.loadRoutes()
+	if err != nil {
+		return err
+	}
+
+	screen, err := tcell.NewScreen()
+	if err != nil {
+		return err
+	}
+
+	browser := tui.NewBrowser(screen, p.options, sortOptions, requests)
+	browser.SetRoutes(routes)
+
+	return browser.Run()
+}
@@ -0,0 +1,44 @@
+package profiler
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/tkuchiki/alp/options"
+	"github.com/tkuchiki/alp/stats"
+)
+
+func TestBrowseOptions(t *testing.T) {
+	tests := []struct {
+		opts *options.Options
+		want string
+	}{
+		{
+			opts: options.NewOptions(options.Format("tui"), options.Load("dump.yaml")),
+			want: "--format tui cannot be used with --load",
+		},
+		{
+			opts: options.NewOptions(options.Format("tui"), options.Follow(true)),
+			want: "--format tui cannot be used with --follow and --metrics-listen",
+		},
+		{
+			opts: options.NewOptions(options.Format("tui"), options.MetricsListen(":9101")),
+			want: "--format tui cannot be used with --follow and --metrics-listen",
+		},
+	}
+
+	for _, tt := range tests {
+		p := NewProfiler(io.Discard, io.Discard, tt.opts)
+		err := p.Run(stats.NewSortOptions(), nil, nil)
+		if err == nil || err.Error() != tt.want {
+			t.Errorf("want: %s, got: %v", tt.want, err)
+		}
+	}
+
+	p := NewProfiler(io.Discard, io.Discard, options.NewOptions(options.Format("tui")))
+	err := p.Run(stats.NewSortOptions(), nil, stats.NewHTTPStats(true, false, false))
+	if err == nil || !strings.Contains(err.Error(), "cannot be used with diff") {
+		t.Errorf("want the error of diff, got: %v", err)
+	}
+}
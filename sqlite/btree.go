@@ -0,0 +1,256 @@
+package sqlite
+
+import (
+	"encoding/binary"
+)
+
+const (
+	pageSize = 4096
+
+	pageTypeIndexInterior = 0x02
+	pageTypeTableInterior = 0x05
+	pageTypeIndexLeaf     = 0x0a
+	pageTypeTableLeaf     = 0x0d
+
+	// the payloads larger than these are spilled to the overflow pages
+	maxLocalTable = pageSize - 35
+	maxLocalIndex = (pageSize-12)*64/255 - 23
+	minLocal      = (pageSize-12)*32/255 - 23
+)
+
+// page is a b-tree page being built, and offset is 100 for the first page that has the database header
+type page struct {
+	typ       byte
+	offset    int
+	cells     [][]byte
+	used      int
+	rightmost uint32
+}
+
+func newPage(typ byte, offset int) *page {
+	return &page{
+		typ:    typ,
+		offset: offset,
+	}
+}
+
+func (pg *page) leaf() bool {
+	return pg.typ == pageTypeIndexLeaf || pg.typ == pageTypeTableLeaf
+}
+
+func (pg *page) headerSize() int {
+	if pg.leaf() {
+		return 8
+	}
+
+	return 12
+}
+
+// fits reports whether the cell and its pointer fit in the page
+func (pg *page) fits(cell []byte) bool {
+	return pg.offset+pg.headerSize()+2*(len(pg.cells)+1)+pg.used+len(cell) <= pageSize
+}
+
+func (pg *page) add(cell []byte) {
+	pg.cells = append(pg.cells, cell)
+	pg.used += len(cell)
+}
+
+func (pg *page) pop() []byte {
+	cell := pg.cells[len(pg.cells)-1]
+	pg.cells = pg.cells[:len(pg.cells)-1]
+	pg.used -= len(cell)
+
+	return cell
+}
+
+// bytes returns the page, whose cells are placed from the end of the page in order
+func (pg *page) bytes() []byte {
+	buf := make([]byte, pageSize)
+	h := pg.offset
+
+	buf[h] = pg.typ
+	binary.BigEndian.PutUint16(buf[h+3:], uint16(len(pg.cells)))
+
+	content := pageSize
+	ptr := h + pg.headerSize()
+	for _, cell := range pg.cells {
+		content -= len(cell)
+		copy(buf[content:], cell)
+		binary.BigEndian.PutUint16(buf[ptr:], uint16(content))
+		ptr += 2
+	}
+	binary.BigEndian.PutUint16(buf[h+5:], uint16(content))
+
+	if !pg.leaf() {
+		binary.BigEndian.PutUint32(buf[h+8:], pg.rightmost)
+	}
+
+	return buf
+}
+
+// localSize returns the size of the payload stored in the cell
+func localSize(size, maxLocal int) int {
+	if size <= maxLocal {
+		return size
+	}
+
+	k := minLocal + (size-minLocal)%(pageSize-4)
+	if k <= maxLocal {
+		return k
+	}
+
+	return minLocal
+}
+
+// payloadCell returns the size and the payload of the cell, and writes the rest of the payload to the overflow pages
+func (db *DB) payloadCell(prefix []byte, payload []byte, maxLocal int) ([]byte, error) {
+	local := localSize(len(payload), maxLocal)
+
+	cell := make([]byte, 0, len(prefix)+9+local+4)
+	cell = append(cell, putVarint(uint64(len(payload)))...)
+	cell = append(cell, prefix...)
+	cell = append(cell, payload[:local]...)
+
+	if local == len(payload) {
+		return cell, nil
+	}
+
+	first, err := db.writeOverflow(payload[local:])
+	if err != nil {
+		return nil, err
+	}
+
+	var buf [4]byte
+	binary.BigEndian.PutUint32(buf[:], first)
+
+	return append(cell, buf[:]...), nil
+}
+
+// writeOverflow writes the data to the linked list of the overflow pages, and returns the first page
+func (db *DB) writeOverflow(data []byte) (uint32, error) {
+	const size = pageSize - 4
+
+	n := (len(data) + size - 1) / size
+	first := db.allocPages(n)
+
+	for i := 0; i < n; i++ {
+		buf := make([]byte, pageSize)
+		if i < n-1 {
+			binary.BigEndian.PutUint32(buf, first+uint32(i)+1)
+		}
+
+		end := (i + 1) * size
+		if end > len(data) {
+			end = len(data)
+		}
+		copy(buf[4:], data[i*size:end])
+
+		if err := db.writePage(first+uint32(i), buf); err != nil {
+			return 0, err
+		}
+	}
+
+	return first, nil
+}
+
+// child is a page of a b-tree, and key is the largest rowid in the page of a table
+type child struct {
+	page uint32
+	key  int64
+}
+
+// maxTableInteriorCells is the number of the largest cells of the left child pointer and the rowid varint in a page
+const maxTableInteriorCells = (pageSize - 12) / (4 + 9 + 2)
+
+// buildTableInterior builds the interior pages on the leaves, and returns the root page
+func (db *DB) buildTableInterior(children []child) (uint32, error) {
+	for len(children) > 1 {
+		// the children are split evenly so that every page has at least one cell
+		n := (len(children) + maxTableInteriorCells) / (maxTableInteriorCells + 1)
+		parents := make([]child, 0, n)
+
+		start := 0
+		for i := 0; i < n; i++ {
+			end := start + (len(children)-start)/(n-i)
+			group := children[start:end]
+
+			pg := newPage(pageTypeTableInterior, 0)
+			for _, c := range group[:len(group)-1] {
+				var buf [4]byte
+				binary.BigEndian.PutUint32(buf[:], c.page)
+				pg.add(append(buf[:], putVarint(uint64(c.key))...))
+			}
+			last := group[len(group)-1]
+			pg.rightmost = last.page
+
+			p, err := db.flushPage(pg)
+			if err != nil {
+				return 0, err
+			}
+			parents = append(parents, child{page: p, key: last.key})
+
+			start = end
+		}
+
+		children = parents
+	}
+
+	return children[0].page, nil
+}
+
+// buildIndexInterior builds the interior pages on the leaves and the separator cells between them, and returns the root page.
+// The separator cells are the cells of the leaves, which are the entries that are not in the leaves.
+func (db *DB) buildIndexInterior(children []uint32, separators [][]byte) (uint32, error) {
+	for len(children) > 1 {
+		parents := make([]uint32, 0)
+		parentSeparators := make([][]byte, 0)
+
+		cellOf := func(i int) []byte {
+			cell := make([]byte, 4, 4+len(separators[i]))
+			binary.BigEndian.PutUint32(cell, children[i])
+			return append(cell, separators[i]...)
+		}
+
+		pg := newPage(pageTypeIndexInterior, 0)
+		for i := range separators {
+			cell := cellOf(i)
+			if pg.fits(cell) {
+				pg.add(cell)
+				continue
+			}
+
+			sep := i
+			if i == len(separators)-1 {
+				// the next page needs at least one cell before the right-most child
+				pg.pop()
+				sep = i - 1
+			}
+
+			pg.rightmost = children[sep]
+			p, err := db.flushPage(pg)
+			if err != nil {
+				return 0, err
+			}
+			parents = append(parents, p)
+			parentSeparators = append(parentSeparators, separators[sep])
+
+			pg = newPage(pageTypeIndexInterior, 0)
+			if sep != i {
+				pg.add(cell)
+			}
+		}
+
+		pg.rightmost = children[len(children)-1]
+		p, err := db.flushPage(pg)
+		if err != nil {
+			return 0, err
+		}
+		parents = append(parents, p)
+
+		children = parents
+		separators = parentSeparators
+	}
+
+	return children[0], nil
+}
@@ -0,0 +1,287 @@
+package sqlite
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// putVarint returns the variable-length integer of SQLite, which is big-endian unlike encoding/binary
+func putVarint(v uint64) []byte {
+	if v <= 0x7f {
+		return []byte{byte(v)}
+	}
+
+	// the 9th byte has all 8 bits
+	if v > 0x00ffffffffffffff {
+		buf := make([]byte, 9)
+		buf[8] = byte(v)
+		v >>= 8
+		for i := 7; i >= 0; i-- {
+			buf[i] = byte(v&0x7f) | 0x80
+			v >>= 7
+		}
+
+		return buf
+	}
+
+	var tmp [9]byte
+	n := 0
+	for v != 0 {
+		tmp[n] = byte(v&0x7f) | 0x80
+		v >>= 7
+		n++
+	}
+	tmp[0] &= 0x7f
+
+	buf := make([]byte, n)
+	for i := 0; i < n; i++ {
+		buf[i] = tmp[n-1-i]
+	}
+
+	return buf
+}
+
+// getVarint decodes the variable-length integer of SQLite, and returns the value and the number of the bytes read, or 0 if buf is too short
+func getVarint(buf []byte) (uint64, int) {
+	var v uint64
+	for i := 0; i < 9 && i < len(buf); i++ {
+		if i == 8 {
+			return v<<8 | uint64(buf[i]), 9
+		}
+
+		v = v<<7 | uint64(buf[i]&0x7f)
+		if buf[i]&0x80 == 0 {
+			return v, i + 1
+		}
+	}
+
+	return 0, 0
+}
+
+func varintLen(v uint64) int {
+	return len(putVarint(v))
+}
+
+// integerSerialType returns the serial type and the size of the two's complement integer
+func integerSerialType(v int64) (uint64, int) {
+	switch {
+	case v == 0:
+		return 8, 0
+	case v == 1:
+		return 9, 0
+	case v >= math.MinInt8 && v <= math.MaxInt8:
+		return 1, 1
+	case v >= math.MinInt16 && v <= math.MaxInt16:
+		return 2, 2
+	case v >= -(1<<23) && v < 1<<23:
+		return 3, 3
+	case v >= math.MinInt32 && v <= math.MaxInt32:
+		return 4, 4
+	case v >= -(1<<47) && v < 1<<47:
+		return 5, 6
+	}
+
+	return 6, 8
+}
+
+// encodeRecord encodes the values in the record format, which is the header of the serial types followed by the values.
+// The values must be nil, int64, float64 or string.
+func encodeRecord(values []interface{}) []byte {
+	types := make([]uint64, 0, len(values))
+	body := make([]byte, 0)
+
+	for _, v := range values {
+		switch val := v.(type) {
+		case nil:
+			types = append(types, 0)
+		case int64:
+			t, size := integerSerialType(val)
+			types = append(types, t)
+
+			var buf [8]byte
+			binary.BigEndian.PutUint64(buf[:], uint64(val))
+			body = append(body, buf[8-size:]...)
+		case float64:
+			types = append(types, 7)
+
+			var buf [8]byte
+			binary.BigEndian.PutUint64(buf[:], math.Float64bits(val))
+			body = append(body, buf[:]...)
+		case string:
+			types = append(types, uint64(len(val))*2+13)
+			body = append(body, val...)
+		}
+	}
+
+	typesLen := 0
+	for _, t := range types {
+		typesLen += varintLen(t)
+	}
+
+	// the size of the header includes the varint of the size itself
+	headerLen := typesLen + 1
+	for headerLen != typesLen+varintLen(uint64(headerLen)) {
+		headerLen = typesLen + varintLen(uint64(headerLen))
+	}
+
+	record := make([]byte, 0, headerLen+len(body))
+	record = append(record, putVarint(uint64(headerLen))...)
+	for _, t := range types {
+		record = append(record, putVarint(t)...)
+	}
+
+	return append(record, body...)
+}
+
+// integerSizes are the sizes of the integers of the serial types from 1 to 6
+var integerSizes = [...]int{1, 2, 3, 4, 6, 8}
+
+// decodeRecord decodes the record encoded by encodeRecord
+func decodeRecord(record []byte) ([]interface{}, error) {
+	headerLen, n := getVarint(record)
+	if n == 0 || headerLen > uint64(len(record)) {
+		return nil, fmt.Errorf("malformed record header")
+	}
+
+	types := make([]uint64, 0)
+	for pos := n; pos < int(headerLen); pos += n {
+		var t uint64
+		t, n = getVarint(record[pos:headerLen])
+		if n == 0 {
+			return nil, fmt.Errorf("malformed record header")
+		}
+		types = append(types, t)
+	}
+
+	values := make([]interface{}, 0, len(types))
+	body := record[headerLen:]
+	for _, t := range types {
+		var size int
+		switch {
+		case t >= 1 && t <= 6:
+			size = integerSizes[t-1]
+		case t == 7:
+			size = 8
+		case t >= 13 && t%2 == 1:
+			size = int(t-13) / 2
+		}
+		if size > len(body) {
+			return nil, fmt.Errorf("malformed record body")
+		}
+
+		switch {
+		case t == 0:
+			values = append(values, nil)
+		case t >= 1 && t <= 6:
+			// sign-extend the big-endian two's complement integer
+			v := int64(int8(body[0]))
+			for _, b := range body[1:size] {
+				v = v<<8 | int64(b)
+			}
+			values = append(values, v)
+		case t == 7:
+			values = append(values, math.Float64frombits(binary.BigEndian.Uint64(body)))
+		case t == 8:
+			values = append(values, int64(0))
+		case t == 9:
+			values = append(values, int64(1))
+		case t >= 13 && t%2 == 1:
+			values = append(values, string(body[:size]))
+		default:
+			return nil, fmt.Errorf("unsupported serial type %d", t)
+		}
+
+		body = body[size:]
+	}
+
+	return values, nil
+}
+
+// storageClass returns the order of the storage classes, NULL < INTEGER and REAL < TEXT
+func storageClass(v interface{}) int {
+	switch v.(type) {
+	case nil:
+		return 0
+	case int64, float64:
+		return 1
+	}
+
+	return 2
+}
+
+// compareNumeric compares the numbers without the loss of the precision of the integers larger than 2^53
+func compareNumeric(a, b interface{}) int {
+	ia, aok := a.(int64)
+	ib, bok := b.(int64)
+
+	switch {
+	case aok && bok:
+		return compareInt(ia, ib)
+	case aok:
+		return compareIntFloat(ia, b.(float64))
+	case bok:
+		return -compareIntFloat(ib, a.(float64))
+	}
+
+	fa, fb := a.(float64), b.(float64)
+	if fa < fb {
+		return -1
+	} else if fa > fb {
+		return 1
+	}
+
+	return 0
+}
+
+func compareInt(a, b int64) int {
+	if a < b {
+		return -1
+	} else if a > b {
+		return 1
+	}
+
+	return 0
+}
+
+// compareIntFloat compares the integer and the real as sqlite3IntFloatCompare does
+func compareIntFloat(i int64, r float64) int {
+	if r < -9223372036854775808.0 {
+		return 1
+	}
+	if r >= 9223372036854775808.0 {
+		return -1
+	}
+
+	// the fraction of r is compared after the integer part
+	if c := compareInt(i, int64(r)); c != 0 {
+		return c
+	}
+
+	f := float64(i)
+	if f < r {
+		return -1
+	} else if f > r {
+		return 1
+	}
+
+	return 0
+}
+
+// compareValues compares the values in the order of SQLite with the BINARY collation
+func compareValues(a, b interface{}) int {
+	ca, cb := storageClass(a), storageClass(b)
+	if ca != cb {
+		return ca - cb
+	}
+
+	switch ca {
+	case 1:
+		return compareNumeric(a, b)
+	case 2:
+		return strings.Compare(a.(string), b.(string))
+	}
+
+	return 0
+}
@@ -0,0 +1,263 @@
+package sqlite
+
+import (
+	"bufio"
+	"container/heap"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+)
+
+// indexSpillSize is the size of the index entries kept in memory.
+// The entries beyond it are sorted and spilled to a temporary file as a run, and the runs are merged when the index is written.
+var indexSpillSize = 32 << 20
+
+// size returns the approximate size of the entry in memory
+func (e *indexEntry) size() int {
+	n := 48
+	for _, v := range e.values {
+		n += 16
+		if s, ok := v.(string); ok {
+			n += len(s)
+		}
+	}
+
+	return n
+}
+
+// record returns the record of the entry, which is the values followed by the rowid
+func (e *indexEntry) record() []byte {
+	values := make([]interface{}, 0, len(e.values)+1)
+	values = append(values, e.values...)
+
+	return encodeRecord(append(values, e.rowid))
+}
+
+func (e *indexEntry) compare(other *indexEntry) int {
+	for i, v := range e.values {
+		if c := compareValues(v, other.values[i]); c != 0 {
+			return c
+		}
+	}
+
+	return compareInt(e.rowid, other.rowid)
+}
+
+// add adds the entry, and spills the entries in memory if they exceed indexSpillSize
+func (idx *Index) add(e *indexEntry) error {
+	idx.entries = append(idx.entries, e)
+	idx.size += e.size()
+
+	if idx.size < indexSpillSize {
+		return nil
+	}
+
+	return idx.spill()
+}
+
+func (idx *Index) sortEntries() {
+	sort.Slice(idx.entries, func(i, j int) bool {
+		return idx.entries[i].compare(idx.entries[j]) < 0
+	})
+}
+
+// spill sorts the entries in memory, and appends them to the temporary file as a run of the length-prefixed records
+func (idx *Index) spill() error {
+	if idx.runs == nil {
+		f, err := os.CreateTemp("", "alp-sqlite-index-")
+		if err != nil {
+			return err
+		}
+		idx.runs = f
+	}
+
+	idx.sortEntries()
+
+	w := bufio.NewWriter(idx.runs)
+	end := idx.runsSize()
+	var buf []byte
+	for _, e := range idx.entries {
+		record := e.record()
+		buf = binary.AppendUvarint(buf[:0], uint64(len(record)))
+		if _, err := w.Write(buf); err != nil {
+			return err
+		}
+		if _, err := w.Write(record); err != nil {
+			return err
+		}
+		end += int64(len(buf) + len(record))
+	}
+
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	idx.runEnds = append(idx.runEnds, end)
+	idx.entries = nil
+	idx.size = 0
+
+	return nil
+}
+
+func (idx *Index) runsSize() int64 {
+	if len(idx.runEnds) == 0 {
+		return 0
+	}
+
+	return idx.runEnds[len(idx.runEnds)-1]
+}
+
+// close closes and removes the temporary file of the runs
+func (idx *Index) close() error {
+	if idx.runs == nil {
+		return nil
+	}
+
+	f := idx.runs
+	idx.runs = nil
+	idx.runEnds = nil
+	f.Close()
+
+	return os.Remove(f.Name())
+}
+
+// entryIterator iterates over the index entries in order, and next returns nil after the last entry
+type entryIterator interface {
+	next() (*indexEntry, error)
+}
+
+type sliceIterator struct {
+	entries []*indexEntry
+}
+
+func (it *sliceIterator) next() (*indexEntry, error) {
+	if len(it.entries) == 0 {
+		return nil, nil
+	}
+
+	e := it.entries[0]
+	// the entries are released after read
+	it.entries[0] = nil
+	it.entries = it.entries[1:]
+
+	return e, nil
+}
+
+// runReader reads a run of the temporary file, and entry is the current entry or nil at the end of the run
+type runReader struct {
+	r     *bufio.Reader
+	entry *indexEntry
+}
+
+func (rr *runReader) read() error {
+	size, err := binary.ReadUvarint(rr.r)
+	if err == io.EOF {
+		rr.entry = nil
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	record := make([]byte, size)
+	if _, err = io.ReadFull(rr.r, record); err != nil {
+		return err
+	}
+
+	values, err := decodeRecord(record)
+	if err != nil {
+		return err
+	}
+
+	if len(values) == 0 {
+		return fmt.Errorf("the index entry has no rowid")
+	}
+	rowid, ok := values[len(values)-1].(int64)
+	if !ok {
+		return fmt.Errorf("the rowid of the index entry is not an integer")
+	}
+
+	rr.entry = &indexEntry{
+		values: values[:len(values)-1],
+		rowid:  rowid,
+	}
+
+	return nil
+}
+
+// mergeIterator merges the runs by the heap of their current entries
+type mergeIterator []*runReader
+
+func (it mergeIterator) Len() int           { return len(it) }
+func (it mergeIterator) Less(i, j int) bool { return it[i].entry.compare(it[j].entry) < 0 }
+func (it mergeIterator) Swap(i, j int)      { it[i], it[j] = it[j], it[i] }
+
+func (it *mergeIterator) Push(x interface{}) {
+	*it = append(*it, x.(*runReader))
+}
+
+func (it *mergeIterator) Pop() interface{} {
+	old := *it
+	rr := old[len(old)-1]
+	*it = old[:len(old)-1]
+
+	return rr
+}
+
+func (it *mergeIterator) next() (*indexEntry, error) {
+	if it.Len() == 0 {
+		return nil, nil
+	}
+
+	rr := (*it)[0]
+	e := rr.entry
+	if err := rr.read(); err != nil {
+		return nil, err
+	}
+
+	if rr.entry == nil {
+		heap.Pop(it)
+	} else {
+		heap.Fix(it, 0)
+	}
+
+	return e, nil
+}
+
+// iterator returns the iterator of the sorted entries, which merges the runs if the entries have been spilled
+func (idx *Index) iterator() (entryIterator, error) {
+	if idx.runs == nil {
+		idx.sortEntries()
+		it := &sliceIterator{entries: idx.entries}
+		idx.entries = nil
+
+		return it, nil
+	}
+
+	if len(idx.entries) > 0 {
+		if err := idx.spill(); err != nil {
+			return nil, err
+		}
+	}
+
+	it := make(mergeIterator, 0, len(idx.runEnds))
+	var start int64
+	for _, end := range idx.runEnds {
+		rr := &runReader{
+			r: bufio.NewReaderSize(io.NewSectionReader(idx.runs, start, end-start), 64<<10),
+		}
+		if err := rr.read(); err != nil {
+			return nil, err
+		}
+		if rr.entry != nil {
+			it = append(it, rr)
+		}
+
+		start = end
+	}
+	heap.Init(&it)
+
+	return &it, nil
+}
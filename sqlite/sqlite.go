@@ -0,0 +1,458 @@
+// Package sqlite writes the database files of SQLite 3 without cgo.
+// It only creates a new database of the tables and the indexes, which is enough to query the exported data with SQL.
+package sqlite
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"os"
+	"strings"
+)
+
+const (
+	TypeInteger = "INTEGER"
+	TypeReal    = "REAL"
+	TypeText    = "TEXT"
+)
+
+type Column struct {
+	Name string
+	Type string
+	// PrimaryKey is the INTEGER PRIMARY KEY column, which is the alias of the rowid
+	PrimaryKey bool
+}
+
+// DB is the database being written, and the file is completed by Close
+type DB struct {
+	f      *os.File
+	npages uint32
+	tables []*Table
+}
+
+type Table struct {
+	db      *DB
+	name    string
+	columns []Column
+	rowid   int64
+	leaf    *page
+	leafKey int64
+	leaves  []child
+	indexes []*Index
+}
+
+type Index struct {
+	name    string
+	columns []int
+	entries []*indexEntry
+	// size is the size of the entries in memory
+	size int
+	// runs is the temporary file of the sorted runs of the spilled entries, and runEnds are the offsets of the ends of the runs
+	runs    *os.File
+	runEnds []int64
+}
+
+type indexEntry struct {
+	values []interface{}
+	rowid  int64
+}
+
+// Create creates the database file, and truncates it if it exists
+func Create(filename string) (*DB, error) {
+	f, err := os.Create(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	return &DB{
+		f: f,
+		// the first page is for the database header and the schema table
+		npages: 1,
+	}, nil
+}
+
+func (db *DB) allocPages(n int) uint32 {
+	first := db.npages + 1
+	db.npages += uint32(n)
+
+	return first
+}
+
+func (db *DB) writePage(pgno uint32, buf []byte) error {
+	_, err := db.f.WriteAt(buf, int64(pgno-1)*pageSize)
+	return err
+}
+
+func (db *DB) flushPage(pg *page) (uint32, error) {
+	pgno := db.allocPages(1)
+	return pgno, db.writePage(pgno, pg.bytes())
+}
+
+func quote(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+// CreateTable creates the table of the columns
+func (db *DB) CreateTable(name string, columns ...Column) (*Table, error) {
+	for _, t := range db.tables {
+		if t.name == name {
+			return nil, fmt.Errorf("table %s already exists", name)
+		}
+	}
+
+	for i, c := range columns {
+		if c.PrimaryKey && c.Type != TypeInteger {
+			return nil, fmt.Errorf("%s.%s: the primary key must be INTEGER", name, c.Name)
+		}
+
+		for _, other := range columns[:i] {
+			if strings.EqualFold(c.Name, other.Name) {
+				return nil, fmt.Errorf("%s.%s: duplicate column name", name, c.Name)
+			}
+		}
+	}
+
+	t := &Table{
+		db:      db,
+		name:    name,
+		columns: columns,
+		leaf:    newPage(pageTypeTableLeaf, 0),
+	}
+	db.tables = append(db.tables, t)
+
+	return t, nil
+}
+
+// CreateIndex creates the index of the columns
+func (t *Table) CreateIndex(name string, columns ...string) (*Index, error) {
+	idx := &Index{
+		name: name,
+	}
+
+	for _, column := range columns {
+		pos := -1
+		for i, c := range t.columns {
+			if c.Name == column {
+				pos = i
+			}
+		}
+
+		if pos < 0 {
+			return nil, fmt.Errorf("%s: no such column: %s", name, column)
+		}
+
+		idx.columns = append(idx.columns, pos)
+	}
+
+	t.indexes = append(t.indexes, idx)
+
+	return idx, nil
+}
+
+// affinity converts the value to the type of the column as SQLite does
+func affinity(v interface{}, typ string) (interface{}, error) {
+	switch val := v.(type) {
+	case nil:
+		return nil, nil
+	case int:
+		v = int64(val)
+	case uint32:
+		v = int64(val)
+	case float32:
+		v = float64(val)
+	case float64:
+		// SQLite has no NaN
+		if math.IsNaN(val) {
+			return nil, nil
+		}
+	case bool:
+		if val {
+			v = int64(1)
+		} else {
+			v = int64(0)
+		}
+	case int64, string:
+	default:
+		return nil, fmt.Errorf("unsupported type %T", v)
+	}
+
+	switch typ {
+	case TypeInteger:
+		if f, ok := v.(float64); ok && f == float64(int64(f)) {
+			return int64(f), nil
+		}
+	case TypeReal:
+		if i, ok := v.(int64); ok {
+			return float64(i), nil
+		}
+	case TypeText:
+		switch val := v.(type) {
+		case int64:
+			return fmt.Sprint(val), nil
+		case float64:
+			return fmt.Sprint(val), nil
+		}
+	}
+
+	return v, nil
+}
+
+// Insert inserts the row of the values in the order of the columns, and the value of the primary key is ignored.
+// The rowids are assigned in ascending order from 1.
+func (t *Table) Insert(values ...interface{}) error {
+	if len(values) != len(t.columns) {
+		return fmt.Errorf("table %s has %d columns but %d values were supplied", t.name, len(t.columns), len(values))
+	}
+
+	t.rowid++
+
+	// the primary key is stored as NULL in the record, and is read from the rowid
+	row := make([]interface{}, len(values))
+	keys := make([]interface{}, len(values))
+	for i, v := range values {
+		c := t.columns[i]
+		if c.PrimaryKey {
+			keys[i] = t.rowid
+			continue
+		}
+
+		val, err := affinity(v, c.Type)
+		if err != nil {
+			return fmt.Errorf("%s.%s: %w", t.name, c.Name, err)
+		}
+		row[i] = val
+		keys[i] = val
+	}
+
+	cell, err := t.db.payloadCell(putVarint(uint64(t.rowid)), encodeRecord(row), maxLocalTable)
+	if err != nil {
+		return err
+	}
+
+	if !t.leaf.fits(cell) {
+		if err := t.flushLeaf(); err != nil {
+			return err
+		}
+	}
+	t.leaf.add(cell)
+	t.leafKey = t.rowid
+
+	for _, idx := range t.indexes {
+		entry := &indexEntry{
+			values: make([]interface{}, len(idx.columns)),
+			rowid:  t.rowid,
+		}
+		for i, pos := range idx.columns {
+			entry.values[i] = keys[pos]
+		}
+		if err := idx.add(entry); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (t *Table) flushLeaf() error {
+	pgno, err := t.db.flushPage(t.leaf)
+	if err != nil {
+		return err
+	}
+
+	t.leaves = append(t.leaves, child{page: pgno, key: t.leafKey})
+	t.leaf = newPage(pageTypeTableLeaf, 0)
+
+	return nil
+}
+
+// build writes the rest of the table, and returns the root page
+func (t *Table) build() (uint32, error) {
+	if len(t.leaf.cells) > 0 || len(t.leaves) == 0 {
+		if err := t.flushLeaf(); err != nil {
+			return 0, err
+		}
+	}
+
+	return t.db.buildTableInterior(t.leaves)
+}
+
+// build writes the index of the sorted entries, and returns the root page
+func (idx *Index) build(db *DB) (uint32, error) {
+	it, err := idx.iterator()
+	if err != nil {
+		return 0, err
+	}
+
+	children := make([]uint32, 0)
+	separators := make([][]byte, 0)
+
+	leaf := newPage(pageTypeIndexLeaf, 0)
+	e, err := it.next()
+	if err != nil {
+		return 0, err
+	}
+
+	for e != nil {
+		// the last entry is found by reading the next entry ahead
+		next, err := it.next()
+		if err != nil {
+			return 0, err
+		}
+		last := next == nil
+
+		cell, err := db.payloadCell(nil, e.record(), maxLocalIndex)
+		if err != nil {
+			return 0, err
+		}
+		e = next
+
+		if leaf.fits(cell) {
+			leaf.add(cell)
+			continue
+		}
+
+		// the entry between the leaves is moved to the parent
+		sep := cell
+		if last {
+			// the last leaf needs at least one cell
+			sep = leaf.pop()
+		}
+
+		pgno, err := db.flushPage(leaf)
+		if err != nil {
+			return 0, err
+		}
+		children = append(children, pgno)
+		separators = append(separators, sep)
+
+		leaf = newPage(pageTypeIndexLeaf, 0)
+		if last {
+			leaf.add(cell)
+		}
+	}
+
+	pgno, err := db.flushPage(leaf)
+	if err != nil {
+		return 0, err
+	}
+	children = append(children, pgno)
+
+	if err = idx.close(); err != nil {
+		return 0, err
+	}
+
+	return db.buildIndexInterior(children, separators)
+}
+
+func (t *Table) sql() string {
+	defs := make([]string, 0, len(t.columns))
+	for _, c := range t.columns {
+		def := fmt.Sprintf("%s %s", quote(c.Name), c.Type)
+		if c.PrimaryKey {
+			def += " PRIMARY KEY"
+		}
+		defs = append(defs, def)
+	}
+
+	return fmt.Sprintf("CREATE TABLE %s (%s)", quote(t.name), strings.Join(defs, ", "))
+}
+
+func (idx *Index) sql(t *Table) string {
+	columns := make([]string, 0, len(idx.columns))
+	for _, pos := range idx.columns {
+		columns = append(columns, quote(t.columns[pos].Name))
+	}
+
+	return fmt.Sprintf("CREATE INDEX %s ON %s (%s)", quote(idx.name), quote(t.name), strings.Join(columns, ", "))
+}
+
+// Close writes the rest of the tables and the indexes, and the schema and the header to the first page
+func (db *DB) Close() error {
+	// the temporary files of the indexes are removed even if the database is not completed
+	defer func() {
+		for _, t := range db.tables {
+			for _, idx := range t.indexes {
+				idx.close()
+			}
+		}
+	}()
+
+	schema := newPage(pageTypeTableLeaf, 100)
+
+	var rowid int64
+	addSchema := func(typ, name, tblName string, rootpage uint32, sql string) error {
+		rowid++
+		record := encodeRecord([]interface{}{typ, name, tblName, int64(rootpage), sql})
+		cell, err := db.payloadCell(putVarint(uint64(rowid)), record, maxLocalTable)
+		if err != nil {
+			return err
+		}
+
+		if !schema.fits(cell) {
+			return fmt.Errorf("too many tables and indexes")
+		}
+		schema.add(cell)
+
+		return nil
+	}
+
+	for _, t := range db.tables {
+		root, err := t.build()
+		if err != nil {
+			db.f.Close()
+			return err
+		}
+
+		if err = addSchema("table", t.name, t.name, root, t.sql()); err != nil {
+			db.f.Close()
+			return err
+		}
+
+		for _, idx := range t.indexes {
+			root, err := idx.build(db)
+			if err != nil {
+				db.f.Close()
+				return err
+			}
+
+			if err = addSchema("index", idx.name, t.name, root, idx.sql(t)); err != nil {
+				db.f.Close()
+				return err
+			}
+		}
+	}
+
+	buf := schema.bytes()
+	db.header(buf)
+
+	if err := db.writePage(1, buf); err != nil {
+		db.f.Close()
+		return err
+	}
+
+	return db.f.Close()
+}
+
+// header writes the database header of the file format 4 in UTF-8
+func (db *DB) header(buf []byte) {
+	copy(buf, "SQLite format 3\x00")
+	binary.BigEndian.PutUint16(buf[16:], pageSize)
+	// the file format write and read versions of the legacy rollback journal
+	buf[18] = 1
+	buf[19] = 1
+	// the maximum and the minimum embedded payload fractions, and the leaf payload fraction
+	buf[21] = 64
+	buf[22] = 32
+	buf[23] = 32
+	// the file change counter
+	binary.BigEndian.PutUint32(buf[24:], 1)
+	binary.BigEndian.PutUint32(buf[28:], db.npages)
+	// the schema cookie and the schema format number
+	binary.BigEndian.PutUint32(buf[40:], 1)
+	binary.BigEndian.PutUint32(buf[44:], 4)
+	// UTF-8
+	binary.BigEndian.PutUint32(buf[56:], 1)
+	// the version-valid-for number is the same as the file change counter so that the database size is valid
+	binary.BigEndian.PutUint32(buf[92:], 1)
+	binary.BigEndian.PutUint32(buf[96:], 3045000)
+}
@@ -0,0 +1,274 @@
+package sqlite
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// sqlite3 returns the path of the sqlite3 command, and skips the test if it is not installed
+func sqlite3(t *testing.T) string {
+	t.Helper()
+
+	path, err := exec.LookPath("sqlite3")
+	if err != nil {
+		t.Skip("sqlite3 is not installed")
+	}
+
+	return path
+}
+
+// query runs the SQL with the sqlite3 command, and returns the output without the trailing newline
+func query(t *testing.T, cmd, filename, sql string) string {
+	t.Helper()
+
+	var stderr bytes.Buffer
+	c := exec.Command(cmd, "-batch", "-bail", filename, sql)
+	c.Stderr = &stderr
+	out, err := c.Output()
+	if err != nil {
+		t.Fatalf("%s: %v: %s", sql, err, stderr.String())
+	}
+
+	return strings.TrimRight(string(out), "\n")
+}
+
+func integrityCheck(t *testing.T, cmd, filename string) {
+	t.Helper()
+
+	if got := query(t, cmd, filename, "PRAGMA integrity_check"); got != "ok" {
+		t.Fatalf("want: ok, got: %s", got)
+	}
+}
+
+func TestCompareValues(t *testing.T) {
+	tests := []struct {
+		a, b interface{}
+		want int
+	}{
+		{a: nil, b: int64(0), want: -1},
+		{a: int64(1), b: "1", want: -1},
+		{a: "a", b: "b", want: -1},
+		// the integers above 2^53 are not equal as float64
+		{a: int64(1<<53 + 1), b: int64(1 << 53), want: 1},
+		{a: int64(1<<62 + 1), b: int64(1<<62 + 2), want: -1},
+		{a: int64(1<<53 + 1), b: float64(1 << 53), want: 1},
+		{a: float64(1 << 53), b: int64(1<<53 + 1), want: -1},
+		{a: int64(1), b: 1.5, want: -1},
+		{a: int64(-1), b: -1.5, want: 1},
+		{a: int64(2), b: 2.0, want: 0},
+		{a: int64(-1 << 63), b: -1e19, want: 1},
+		{a: int64(1<<63 - 1), b: 1e19, want: -1},
+		{a: 0.5, b: 0.25, want: 1},
+	}
+
+	for _, tt := range tests {
+		if got := compareValues(tt.a, tt.b); got != tt.want {
+			t.Errorf("%v <=> %v want: %d, got: %d", tt.a, tt.b, tt.want, got)
+		}
+	}
+}
+
+func TestPutVarint(t *testing.T) {
+	tests := []struct {
+		v    uint64
+		want []byte
+	}{
+		{v: 0, want: []byte{0x00}},
+		{v: 0x7f, want: []byte{0x7f}},
+		{v: 0x80, want: []byte{0x81, 0x00}},
+		{v: 0x3fff, want: []byte{0xff, 0x7f}},
+		{v: 1<<64 - 1, want: bytes.Repeat([]byte{0xff}, 9)},
+	}
+
+	for _, tt := range tests {
+		if got := putVarint(tt.v); !bytes.Equal(got, tt.want) {
+			t.Errorf("%d want: %x, got: %x", tt.v, tt.want, got)
+		}
+	}
+}
+
+func TestDecodeRecord(t *testing.T) {
+	values := []interface{}{nil, int64(0), int64(1), int64(-1), int64(-129), int64(1 << 40), int64(-1 << 63), 1.5, "", "foo", strings.Repeat("x", 1000)}
+
+	got, err := decodeRecord(encodeRecord(values))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(got, values) {
+		t.Errorf("want: %v, got: %v", values, got)
+	}
+}
+
+func TestCreate(t *testing.T) {
+	tests := []struct {
+		name      string
+		spillSize int
+	}{
+		{name: "in memory", spillSize: indexSpillSize},
+		// the entries are spilled to the runs of the temporary file, and merged
+		{name: "spilled", spillSize: 64 << 10},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spillSize := indexSpillSize
+			indexSpillSize = tt.spillSize
+			defer func() { indexSpillSize = spillSize }()
+
+			tmpdir := t.TempDir()
+			t.Setenv("TMPDIR", tmpdir)
+
+			testCreate(t, tt.spillSize < spillSize)
+
+			// the temporary files of the runs are removed
+			files, err := os.ReadDir(tmpdir)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(files) != 0 {
+				t.Errorf("want no temporary files, got: %d", len(files))
+			}
+		})
+	}
+}
+
+func testCreate(t *testing.T, spilled bool) {
+	cmd := sqlite3(t)
+	filename := filepath.Join(t.TempDir(), "test.db")
+
+	db, err := Create(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tbl, err := db.CreateTable("rows",
+		Column{Name: "id", Type: TypeInteger, PrimaryKey: true},
+		Column{Name: "name", Type: TypeText},
+		Column{Name: "value", Type: TypeInteger},
+		Column{Name: "ratio", Type: TypeReal},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = tbl.CreateIndex("rows_name", "name"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = tbl.CreateIndex("rows_value", "value"); err != nil {
+		t.Fatal(err)
+	}
+
+	// the large rows make the table and the indexes have the interior pages of several levels,
+	// and the rows are inserted in the order different from the index
+	const n = 1500
+	ids := make(map[int]int, n)
+	for i := 0; i < n; i++ {
+		k := i * 7919 % n
+		ids[k] = i + 1
+
+		name := fmt.Sprintf("%05d", k) + strings.Repeat("x", 800)
+		// the rows of the overflow pages of the table and the index
+		if i%100 == 0 {
+			name = fmt.Sprintf("%05d", k) + strings.Repeat("y", 10000)
+		}
+
+		if err = tbl.Insert(nil, name, int64(1<<53+k), float64(k)/2); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// the values of the other storage classes
+	tbl2, err := db.CreateTable("mixed",
+		Column{Name: "id", Type: TypeInteger, PrimaryKey: true},
+		Column{Name: "v", Type: TypeInteger},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = tbl2.CreateIndex("mixed_v", "v"); err != nil {
+		t.Fatal(err)
+	}
+	for _, v := range []interface{}{"a", int64(1<<53 + 1), 1.5, nil, float64(1 << 53), int64(2)} {
+		if err = tbl2.Insert(nil, v); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if got := len(tbl.indexes[0].runEnds) > 1; got != spilled {
+		t.Errorf("spilled want: %v, got: %v", spilled, got)
+	}
+
+	if err = db.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	integrityCheck(t, cmd, filename)
+
+	if got := query(t, cmd, filename, "SELECT count(*), sum(ratio) FROM rows"); got != fmt.Sprintf("%d|%.1f", n, float64(n*(n-1))/4) {
+		t.Errorf("want the count and the sum of the rows, got: %s", got)
+	}
+
+	// the index is ordered by the integers above 2^53
+	if got := query(t, cmd, filename, "SELECT id FROM rows INDEXED BY rows_value WHERE value = 9007199254740993"); got != fmt.Sprint(ids[1]) {
+		t.Errorf("want: %d, got: %s", ids[1], got)
+	}
+	got := query(t, cmd, filename, "SELECT group_concat(value - 9007199254740992) FROM (SELECT value FROM rows INDEXED BY rows_value ORDER BY value)")
+	want := make([]string, n)
+	for i := range want {
+		want[i] = fmt.Sprint(i)
+	}
+	if got != strings.Join(want, ",") {
+		t.Errorf("want the values in order, got: %s", got)
+	}
+
+	// the overflow payloads are read from the table and the index
+	k := 100 * 7919 % n
+	got = query(t, cmd, filename, fmt.Sprintf("SELECT id, length(name) FROM rows INDEXED BY rows_name WHERE name >= '%05d' ORDER BY name LIMIT 1", k))
+	if got != fmt.Sprintf("%d|10005", ids[k]) {
+		t.Errorf("want the length of the overflow payload, got: %s", got)
+	}
+	got = query(t, cmd, filename, "SELECT count(*) FROM rows INDEXED BY rows_name WHERE name > '00100' AND name < '00200'")
+	if got != "100" {
+		t.Errorf("want the rows between the names, got: %s", got)
+	}
+
+	// NULL < INTEGER and REAL < TEXT, and the integral REAL is stored as INTEGER
+	indexed := query(t, cmd, filename, "SELECT group_concat(quote(v)) FROM (SELECT v FROM mixed INDEXED BY mixed_v ORDER BY v)")
+	if indexed != "NULL,1.5,2,9007199254740992,9007199254740993,'a'" {
+		t.Errorf("want the values in the order of SQLite, got: %s", indexed)
+	}
+}
+
+func TestCreateEmpty(t *testing.T) {
+	cmd := sqlite3(t)
+	filename := filepath.Join(t.TempDir(), "test.db")
+
+	db, err := Create(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tbl, err := db.CreateTable("empty", Column{Name: "name", Type: TypeText})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = tbl.CreateIndex("empty_name", "name"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = db.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	integrityCheck(t, cmd, filename)
+
+	if got := query(t, cmd, filename, "SELECT count(*) FROM empty"); got != "0" {
+		t.Errorf("want: 0, got: %s", got)
+	}
+}
@@ -1,10 +1,20 @@
 package stats
 
-import "fmt"
+import (
+	"fmt"
+	"math"
+)
 
 type Differ struct {
 	From *HTTPStat
 	To   *HTTPStat
+	// shifts caches the results of Shift by the significance level, because the columns of the significance share them
+	shifts map[float64]*shiftResult
+}
+
+type shiftResult struct {
+	shift, lower, upper float64
+	ok                  bool
 }
 
 func NewDiffer(from, to *HTTPStat) *Differ {
@@ -68,6 +78,24 @@ func (d *Differ) DiffStatus5xx() string {
 	return fmt.Sprintf("%d", v)
 }
 
+func (d *Differ) DiffStatusCode(code int) string {
+	v := d.To.StatusCode(code) - d.From.StatusCode(code)
+	if v >= 0 {
+		return fmt.Sprintf("+%d", v)
+	}
+
+	return fmt.Sprintf("%d", v)
+}
+
+func (d *Differ) DiffStatusOther(codes []int) string {
+	v := d.To.StatusOther(codes) - d.From.StatusOther(codes)
+	if v >= 0 {
+		return fmt.Sprintf("+%d", v)
+	}
+
+	return fmt.Sprintf("%d", v)
+}
+
 func (d *Differ) DiffMaxResponseTime() string {
 	v := d.To.MaxResponseTime() - d.From.MaxResponseTime()
 	if v >= 0 {
@@ -232,16 +260,148 @@ func (d *Differ) DiffStddevResponseBodyBytes() string {
 	return fmt.Sprintf("%.3f", v)
 }
 
+func (d *Differ) DiffStatus4xxRate() string {
+	v := d.To.Status4xxRate() - d.From.Status4xxRate()
+	if v >= 0 {
+		return fmt.Sprintf("+%.3f", v)
+	}
+
+	return fmt.Sprintf("%.3f", v)
+}
+
+func (d *Differ) DiffStatus5xxRate() string {
+	v := d.To.Status5xxRate() - d.From.Status5xxRate()
+	if v >= 0 {
+		return fmt.Sprintf("+%.3f", v)
+	}
+
+	return fmt.Sprintf("%.3f", v)
+}
+
+func (d *Differ) DiffRequestsPerSecond() string {
+	v := d.To.RequestsPerSecond() - d.From.RequestsPerSecond()
+	if v >= 0 {
+		return fmt.Sprintf("+%.3f", v)
+	}
+
+	return fmt.Sprintf("%.3f", v)
+}
+
+func (d *Differ) DiffApdex() string {
+	v := d.To.Apdex() - d.From.Apdex()
+	if v >= 0 {
+		return fmt.Sprintf("+%.3f", v)
+	}
+
+	return fmt.Sprintf("%.3f", v)
+}
+
+// Change returns the relative change of the average response time in percent
+func (d *Differ) Change() float64 {
+	return relativeChange(d.From.AvgResponseTime(), d.To.AvgResponseTime())
+}
+
+func (d *Differ) DiffChange() string {
+	v := d.Change()
+	if v >= 0 {
+		return fmt.Sprintf("+%.3f%%", v)
+	}
+
+	return fmt.Sprintf("%.3f%%", v)
+}
+
+// PValue returns the p-value of the Mann-Whitney U test of the response times, and NaN if they are not retained
+func (d *Differ) PValue() float64 {
+	from := d.From.ResponseTimeSamples()
+	to := d.To.ResponseTimeSamples()
+	if from == nil || to == nil {
+		return math.NaN()
+	}
+
+	return mannWhitneyU(from, to)
+}
+
+func (d *Differ) StrPValue() string {
+	v := d.PValue()
+	if math.IsNaN(v) {
+		return "-"
+	}
+
+	return fmt.Sprintf("%.3f", v)
+}
+
+// Shift returns the Hodges-Lehmann estimate of the shift of the response times and its confidence interval
+// at the confidence level of 1 - the significance level. ok is false if they cannot be estimated
+func (d *Differ) Shift(level float64) (shift, lower, upper float64, ok bool) {
+	if r, ok := d.shifts[level]; ok {
+		return r.shift, r.lower, r.upper, r.ok
+	}
+
+	r := &shiftResult{}
+	from := d.From.ResponseTimeSamples()
+	to := d.To.ResponseTimeSamples()
+	if from != nil && to != nil {
+		r.shift, r.lower, r.upper, r.ok = shiftCI(from, to, 1-level)
+	}
+
+	if d.shifts == nil {
+		d.shifts = make(map[float64]*shiftResult, 1)
+	}
+	d.shifts[level] = r
+
+	return r.shift, r.lower, r.upper, r.ok
+}
+
+// Significant reports whether the response times differ significantly at the significance level,
+// that is, the confidence interval of the shift excludes 0
+func (d *Differ) Significant(level float64) bool {
+	_, lower, upper, ok := d.Shift(level)
+
+	return ok && (lower > 0 || upper < 0)
+}
+
+// Significance reports whether the response times differ significantly at the significance level
+func (d *Differ) Significance(level float64) string {
+	if math.IsNaN(d.PValue()) {
+		return "-"
+	}
+
+	if d.Significant(level) {
+		return "significant"
+	}
+
+	return "insignificant"
+}
+
+// ConfidenceInterval returns the Hodges-Lehmann estimate of the shift of the response times
+// and its confidence interval at the confidence level of 1 - the significance level
+func (d *Differ) ConfidenceInterval(level float64) string {
+	shift, lower, upper, ok := d.Shift(level)
+	if !ok {
+		return "-"
+	}
+
+	return fmt.Sprintf("%+.3f [%+.3f, %+.3f]", shift, lower, upper)
+}
+
 func DiffCountAll(from, to map[string]int) map[string]string {
-	counts := make(map[string]string, 6)
-	keys := []string{"count", "1xx", "2xx", "3xx", "4xx", "5xx"}
+	counts := make(map[string]string, len(to))
+
+	// the keys of the exact status codes differ between from and to
+	keys := make(map[string]struct{}, len(to))
+	for key := range from {
+		keys[key] = struct{}{}
+	}
+	for key := range to {
+		keys[key] = struct{}{}
+	}
 
-	for _, key := range keys {
+	for key := range keys {
 		v := to[key] - from[key]
 		if v >= 0 {
 			counts[key] = fmt.Sprintf("+%d", v)
 		} else {
-			counts[key] = fmt.Sprintf("-%d", v)
+			counts[key] = fmt.Sprintf("%d", v)
 		}
 	}
 
@@ -1,13 +1,71 @@
 package stats
 
 import (
+	"compress/gzip"
+	"encoding/gob"
+	"fmt"
 	"io"
+	"strings"
+	"time"
 
 	"gopkg.in/yaml.v2"
 )
 
+// DumpVersion is the schema version of the dump written by DumpStats.
+// The dumps without the version are the bare lists of the stats written by the older versions of alp.
+const DumpVersion = 1
+
+const (
+	DumpFormatYAML = "yaml"
+	// DumpFormatBinary is the Dump encoded by gob and compressed by gzip,
+	// which is smaller and faster to load than YAML when the percentiles retain every sample
+	DumpFormatBinary = "binary"
+)
+
+// binaryDumpMagic is written at the beginning of the binary dump to detect the format on load
+var binaryDumpMagic = []byte("alp-binary-dump\n")
+
+// timeNow is replaced in the tests
+var timeNow = time.Now
+
+// Dump is the envelope of the dumped stats
+type Dump struct {
+	Version  int           `yaml:"version"`
+	Metadata *DumpMetadata `yaml:"metadata"`
+	Stats    []*HTTPStat   `yaml:"stats"`
+}
+
+// DumpFormat returns format if it is specified, or the format of filename by the extension,
+// that is binary for .bin and yaml for the others
+func DumpFormat(format, filename string) (string, error) {
+	switch format {
+	case DumpFormatYAML, DumpFormatBinary:
+		return format, nil
+	case "":
+		if strings.HasSuffix(filename, ".bin") {
+			return DumpFormatBinary, nil
+		}
+
+		return DumpFormatYAML, nil
+	}
+
+	return "", fmt.Errorf("enum value must be one of yaml,binary, got '%s'", format)
+}
+
+func (hs *HTTPStats) dump() *Dump {
+	md := *hs.Metadata()
+	md.CreatedAt = timeNow()
+
+	return &Dump{
+		Version:  DumpVersion,
+		Metadata: &md,
+		Stats:    hs.stats,
+	}
+}
+
+// DumpStats writes the stats in YAML
 func (hs *HTTPStats) DumpStats(w io.Writer) error {
-	buf, err := yaml.Marshal(&hs.stats)
+	buf, err := yaml.Marshal(hs.dump())
 	if err != nil {
 		return err
 	}
@@ -16,3 +74,29 @@ func (hs *HTTPStats) DumpStats(w io.Writer) error {
 
 	return err
 }
+
+// DumpStatsWithFormat writes the stats in the format of DumpFormat
+func (hs *HTTPStats) DumpStatsWithFormat(w io.Writer, format string) error {
+	switch format {
+	case DumpFormatYAML:
+		return hs.DumpStats(w)
+	case DumpFormatBinary:
+		return hs.dumpBinaryStats(w)
+	}
+
+	return fmt.Errorf("enum value must be one of yaml,binary, got '%s'", format)
+}
+
+func (hs *HTTPStats) dumpBinaryStats(w io.Writer) error {
+	gw := gzip.NewWriter(w)
+
+	if _, err := gw.Write(binaryDumpMagic); err != nil {
+		return err
+	}
+
+	if err := gob.NewEncoder(gw).Encode(hs.dump()); err != nil {
+		return err
+	}
+
+	return gw.Close()
+}
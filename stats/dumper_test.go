@@ -3,13 +3,22 @@ package stats
 import (
 	"bytes"
 	"testing"
+	"time"
 
 	godiff "github.com/kylelemons/godebug/diff"
 )
 
 func TestDumpStats(t *testing.T) {
+	timeNow = func() time.Time {
+		return time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	}
+	defer func() {
+		timeNow = time.Now
+	}()
+
 	got := new(bytes.Buffer)
 	stats := NewHTTPStats(true, false, false)
+	stats.SetStatusCodes([]int{200})
 	stats.Set("/foo/bar", "POST", 200, 0.057, 12, 0)
 
 	err := stats.DumpStats(got)
@@ -17,13 +26,23 @@ func TestDumpStats(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	want := bytes.NewBufferString(`- uri: /foo/bar
+	want := bytes.NewBufferString(`version: 1
+metadata:
+  created_at: 2026-01-02T03:04:05Z
+  log:
+    lines: 0
+    skipped_lines: 0
+    filtered_lines: 0
+stats:
+- uri: /foo/bar
   count: 1
   status1xx: 0
   status2xx: 1
   status3xx: 0
   status4xx: 0
   status5xx: 0
+  status_codes:
+    200: 1
   method: POST
   response_time:
     max: 0.057
@@ -33,21 +52,97 @@ func TestDumpStats(t *testing.T) {
     percentiles:
     - 0.057
   request_body_bytes:
-    max: 12
-    min: 12
-    sum: 12
-    usepercentile: false
-    percentiles: []
-  response_body_bytes:
     max: 0
     min: 0
     sum: 0
     usepercentile: false
     percentiles: []
-  time: ""
+  response_body_bytes:
+    max: 12
+    min: 12
+    sum: 12
+    usepercentile: false
+    percentiles: []
 `)
 
 	if diff := godiff.Diff(got.String(), want.String()); diff != "" {
 		t.Errorf("diff\n%s", diff)
 	}
 }
+
+func TestDumpStatsBinary(t *testing.T) {
+	sts := NewHTTPStats(true, true, false)
+	sts.Set("/foo/bar", "POST", 200, 0.057, 12, 3)
+	sts.Set("/foo/bar", "POST", 500, 0.1, 34, 0)
+	sts.Set("/baz", "GET", 200, 0, 0, 0)
+
+	skSts := NewHTTPStats(true, false, false)
+	if err := skSts.SetPercentileEstimator("sketch", 0.01); err != nil {
+		t.Fatal(err)
+	}
+	skSts.Set("/sketch", "GET", 200, 0.2, 10, 0)
+
+	for _, hs := range []*HTTPStats{sts, skSts} {
+		bin := new(bytes.Buffer)
+		if err := hs.DumpStatsWithFormat(bin, DumpFormatBinary); err != nil {
+			t.Fatal(err)
+		}
+
+		yml := new(bytes.Buffer)
+		if err := hs.DumpStatsWithFormat(yml, DumpFormatYAML); err != nil {
+			t.Fatal(err)
+		}
+
+		fromBin := NewHTTPStats(true, false, false)
+		if err := fromBin.LoadStats(bin); err != nil {
+			t.Fatal(err)
+		}
+
+		fromYAML := NewHTTPStats(true, false, false)
+		if err := fromYAML.LoadStats(yml); err != nil {
+			t.Fatal(err)
+		}
+
+		if len(fromBin.Stats()) != len(fromYAML.Stats()) {
+			t.Fatalf("stats want: %d, got: %d", len(fromYAML.Stats()), len(fromBin.Stats()))
+		}
+
+		for i, want := range fromYAML.Stats() {
+			got := fromBin.Stats()[i]
+			if got.Cnt != want.Cnt || got.Status5xx != want.Status5xx ||
+				got.MaxResponseTime() != want.MaxResponseTime() || got.PNResponseTime(99) != want.PNResponseTime(99) ||
+				got.PNRequestBodyBytes(90) != want.PNRequestBodyBytes(90) || got.SumResponseBodyBytes() != want.SumResponseBodyBytes() {
+				t.Errorf("%s %s: binary and YAML are different", want.Method, want.Uri)
+			}
+		}
+	}
+}
+
+func TestDumpFormat(t *testing.T) {
+	tests := []struct {
+		format   string
+		filename string
+		want     string
+		wantErr  bool
+	}{
+		{filename: "dump.yaml", want: DumpFormatYAML},
+		{filename: "dump.bin", want: DumpFormatBinary},
+		{format: DumpFormatYAML, filename: "dump.bin", want: DumpFormatYAML},
+		{format: DumpFormatBinary, filename: "dump.yaml", want: DumpFormatBinary},
+		{format: "msgpack", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := DumpFormat(tt.format, tt.filename)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("%s: want error, got nil", tt.format)
+			}
+			continue
+		}
+
+		if err != nil || got != tt.want {
+			t.Errorf("%s %s: want %s, got %s (%v)", tt.format, tt.filename, tt.want, got, err)
+		}
+	}
+}
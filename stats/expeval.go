@@ -1,6 +1,12 @@
 package stats
 
 import (
+	"math"
+	"net"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/antonmedv/expr"
@@ -21,6 +27,7 @@ type ExpEvalEnv struct {
 	ResponseTime                     float64
 	BodyBytes                        float64
 	Status                           int
+	Entries                          map[string]string
 	TimeStringEqualTime              func(l time.Time, r string) bool
 	TimeStringNotEqualTime           func(l time.Time, r string) bool
 	TimeStringGreaterThanTime        func(l time.Time, r string) bool
@@ -33,12 +40,30 @@ type ExpEvalEnv struct {
 	StringTimeGreaterThanOrEqualTime func(l string, r time.Time) bool
 	StringTimeLessThanTime           func(l string, r time.Time) bool
 	StringTimeLessThanOrEqualTime    func(l string, r time.Time) bool
+	TimeTimeEqualTime                func(l, r time.Time) bool
+	TimeTimeNotEqualTime             func(l, r time.Time) bool
+	TimeTimeGreaterThanTime          func(l, r time.Time) bool
+	TimeTimeGreaterThanOrEqualTime   func(l, r time.Time) bool
+	TimeTimeLessThanTime             func(l, r time.Time) bool
+	TimeTimeLessThanOrEqualTime      func(l, r time.Time) bool
 	TimeAgo                          func(s string) time.Time
 	BetweenTime                      func(t, start, end string) bool
+	ToNumber                         func(s string) float64
+	ToTime                           func(s string) time.Time
+	MatchRegexp                      func(s, pattern string) bool
+	InCIDR                           func(ip, cidr string) bool
+	HasAnyPrefix                     func(s string, prefixes ...string) bool
+	HasAnySuffix                     func(s string, suffixes ...string) bool
 }
 
 var parseTime parsetime.ParseTime
 
+// regexps and cidrs cache the compiled patterns, because the filter is evaluated for every line on the multiple goroutines
+var (
+	regexps sync.Map
+	cidrs   sync.Map
+)
+
 // =
 func TimeStringEqualTime(l time.Time, r string) bool {
 	t, err := parseTime.Parse(r)
@@ -159,6 +184,36 @@ func StringTimeLessThanOrEqualTime(l string, r time.Time) bool {
 	return t.Before(r) || t.Equal(r)
 }
 
+// =
+func TimeTimeEqualTime(l, r time.Time) bool {
+	return l.Equal(r)
+}
+
+// !=
+func TimeTimeNotEqualTime(l, r time.Time) bool {
+	return !l.Equal(r)
+}
+
+// >
+func TimeTimeGreaterThanTime(l, r time.Time) bool {
+	return l.After(r)
+}
+
+// >=
+func TimeTimeGreaterThanOrEqualTime(l, r time.Time) bool {
+	return l.After(r) || l.Equal(r)
+}
+
+// <
+func TimeTimeLessThanTime(l, r time.Time) bool {
+	return l.Before(r)
+}
+
+// <=
+func TimeTimeLessThanOrEqualTime(l, r time.Time) bool {
+	return l.Before(r) || l.Equal(r)
+}
+
 func TimeAgo(s string) time.Time {
 	d, err := time.ParseDuration(s)
 	if err != nil {
@@ -187,6 +242,106 @@ func BetweenTime(t, start, end string) bool {
 	return st.UnixNano() <= val.UnixNano() && val.UnixNano() <= et.UnixNano()
 }
 
+// ToNumber converts the string to the number.
+// The empty string and "-", which are the placeholders of the missing values of the access logs, are 0,
+// and the other strings that are not the numbers are NaN, which is false in any comparison
+func ToNumber(s string) float64 {
+	if s == "" || s == "-" {
+		return 0
+	}
+
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return math.NaN()
+	}
+
+	return f
+}
+
+// ToTime converts the string to the time, and the missing values and the strings that are not the times are the zero time
+func ToTime(s string) time.Time {
+	if s == "" || s == "-" {
+		return time.Time{}
+	}
+
+	t, err := parseTime.Parse(s)
+	if err != nil {
+		return time.Time{}
+	}
+
+	return t
+}
+
+// MatchRegexp reports whether the string matches the pattern, and the invalid pattern matches nothing
+func MatchRegexp(s, pattern string) bool {
+	v, ok := regexps.Load(pattern)
+	if !ok {
+		// the invalid pattern is cached as nil not to compile it for every line
+		re, _ := regexp.Compile(pattern)
+		v, _ = regexps.LoadOrStore(pattern, re)
+	}
+
+	re := v.(*regexp.Regexp)
+
+	return re != nil && re.MatchString(s)
+}
+
+// InCIDR reports whether the IP address is in the CIDR.
+// The IP address may have the port such as upstream_addr, and the CIDR may be the IP address.
+// The invalid CIDR contains no IP address
+func InCIDR(ip, cidr string) bool {
+	if !strings.Contains(cidr, "/") {
+		if strings.Contains(cidr, ":") {
+			cidr += "/128"
+		} else {
+			cidr += "/32"
+		}
+	}
+
+	v, ok := cidrs.Load(cidr)
+	if !ok {
+		// the invalid CIDR is cached as nil not to parse it for every line
+		_, ipnet, _ := net.ParseCIDR(cidr)
+		v, _ = cidrs.LoadOrStore(cidr, ipnet)
+	}
+
+	ipnet := v.(*net.IPNet)
+	if ipnet == nil {
+		return false
+	}
+
+	addr := net.ParseIP(ip)
+	if addr == nil {
+		host, _, err := net.SplitHostPort(ip)
+		if err != nil {
+			return false
+		}
+		addr = net.ParseIP(host)
+	}
+
+	return addr != nil && ipnet.Contains(addr)
+}
+
+func HasAnyPrefix(s string, prefixes ...string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(s, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func HasAnySuffix(s string, suffixes ...string) bool {
+	for _, suffix := range suffixes {
+		if strings.HasSuffix(s, suffix) {
+			return true
+		}
+	}
+
+	return false
+}
+
 func NewExpEval(input string, pt parsetime.ParseTime) (*ExpEval, error) {
 	program, err := expr.Compile(input, expr.Env(&ExpEvalEnv{}), expr.AsBool(),
 		expr.Operator("=", "TimeStringEqualTime"),
@@ -201,6 +356,12 @@ func NewExpEval(input string, pt parsetime.ParseTime) (*ExpEval, error) {
 		expr.Operator(">=", "StringTimeGreaterThanOrEqualTime"),
 		expr.Operator("<", "StringTimeLessThanTime"),
 		expr.Operator("<=", "StringTimeLessThanOrEqualTime"),
+		expr.Operator("=", "TimeTimeEqualTime"),
+		expr.Operator("!=", "TimeTimeNotEqualTime"),
+		expr.Operator(">", "TimeTimeGreaterThanTime"),
+		expr.Operator(">=", "TimeTimeGreaterThanOrEqualTime"),
+		expr.Operator("<", "TimeTimeLessThanTime"),
+		expr.Operator("<=", "TimeTimeLessThanOrEqualTime"),
 	)
 	if err != nil {
 		return nil, err
@@ -221,6 +382,7 @@ func (ee *ExpEval) Run(stat *parsers.ParsedHTTPStat) (bool, error) {
 		ResponseTime:                     stat.ResponseTime,
 		BodyBytes:                        stat.BodyBytes,
 		Status:                           stat.Status,
+		Entries:                          stat.Entries,
 		TimeStringEqualTime:              TimeStringEqualTime,
 		TimeStringNotEqualTime:           TimeStringNotEqualTime,
 		TimeStringGreaterThanTime:        TimeStringGreaterThanTime,
@@ -233,8 +395,20 @@ func (ee *ExpEval) Run(stat *parsers.ParsedHTTPStat) (bool, error) {
 		StringTimeGreaterThanOrEqualTime: StringTimeGreaterThanOrEqualTime,
 		StringTimeLessThanTime:           StringTimeLessThanTime,
 		StringTimeLessThanOrEqualTime:    StringTimeLessThanOrEqualTime,
+		TimeTimeEqualTime:                TimeTimeEqualTime,
+		TimeTimeNotEqualTime:             TimeTimeNotEqualTime,
+		TimeTimeGreaterThanTime:          TimeTimeGreaterThanTime,
+		TimeTimeGreaterThanOrEqualTime:   TimeTimeGreaterThanOrEqualTime,
+		TimeTimeLessThanTime:             TimeTimeLessThanTime,
+		TimeTimeLessThanOrEqualTime:      TimeTimeLessThanOrEqualTime,
 		TimeAgo:                          TimeAgo,
 		BetweenTime:                      BetweenTime,
+		ToNumber:                         ToNumber,
+		ToTime:                           ToTime,
+		MatchRegexp:                      MatchRegexp,
+		InCIDR:                           InCIDR,
+		HasAnyPrefix:                     HasAnyPrefix,
+		HasAnySuffix:                     HasAnySuffix,
 	}
 
 	output, err := expr.Run(ee.program, env)
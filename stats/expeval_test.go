@@ -0,0 +1,120 @@
+package stats
+
+import (
+	"math"
+	"net"
+	"testing"
+
+	"github.com/tkuchiki/alp/parsers"
+	"github.com/tkuchiki/parsetime"
+)
+
+func TestExpEvalEntries(t *testing.T) {
+	pt, err := parsetime.NewParseTime("UTC")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stat := &parsers.ParsedHTTPStat{
+		Uri:    "/foo",
+		Method: "GET",
+		Status: 200,
+		Entries: parsers.LogEntries{
+			"ua":                     "Mozilla/5.0 (compatible; Googlebot/2.1)",
+			"host":                   "api.example.com",
+			"upstream_addr":          "10.1.2.3:8080",
+			"upstream_response_time": "0.600",
+			"time":                   "2015-09-06T05:58:05+09:00",
+			"bytes":                  "-",
+		},
+	}
+
+	tests := []struct {
+		input string
+		want  bool
+	}{
+		{`Entries.ua contains "bot"`, true},
+		{`Entries.host == "api.example.com"`, true},
+		{`Entries.missing == ""`, true},
+		{`ToNumber(Entries.upstream_response_time) > 0.5`, true},
+		{`ToNumber(Entries.bytes) == 0`, true},
+		{`ToTime(Entries.time) > ToTime("2015-09-06T05:58:00+09:00")`, true},
+		{`ToTime(Entries.time) >= TimeAgo("5m")`, false},
+		{`MatchRegexp(Entries.host, "^api\\.")`, true},
+		{`MatchRegexp(Entries.host, "^www\\.")`, false},
+		{`InCIDR(Entries.upstream_addr, "10.0.0.0/8")`, true},
+		{`InCIDR(Entries.upstream_addr, "192.168.0.0/16")`, false},
+		{`InCIDR(Entries.missing, "10.0.0.0/8")`, false},
+		{`HasAnyPrefix(Entries.host, "www.", "api.")`, true},
+		{`HasAnySuffix(Entries.host, ".example.org")`, false},
+		// the invalid values are false in any comparison instead of the error
+		{`ToNumber(Entries.ua) > 1`, false},
+		{`ToNumber(Entries.ua) <= 1`, false},
+		{`MatchRegexp(Entries.host, "[")`, false},
+		{`InCIDR(Entries.upstream_addr, "10.0.0.0/33")`, false},
+		{`InCIDR(Entries.upstream_addr, "10.1.2.3")`, true},
+	}
+
+	for _, tt := range tests {
+		ee, err := NewExpEval(tt.input, pt)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.input, err)
+		}
+
+		got, err := ee.Run(stat)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.input, err)
+		}
+
+		if got != tt.want {
+			t.Errorf("%s: want %v, got %v", tt.input, tt.want, got)
+		}
+	}
+}
+
+func TestExpEvalInvalidValues(t *testing.T) {
+	if got := ToNumber("UA1"); !math.IsNaN(got) {
+		t.Errorf("want NaN, got: %v", got)
+	}
+
+	for _, s := range []string{"", "-"} {
+		if got := ToTime(s); !got.IsZero() {
+			t.Errorf("want the zero time of %q, got: %v", s, got)
+		}
+	}
+
+	// the invalid pattern is cached, and matches nothing every time
+	for i := 0; i < 2; i++ {
+		if MatchRegexp("foo", "(") {
+			t.Error("the invalid pattern must match nothing")
+		}
+		if InCIDR("10.0.0.1", "10.0.0.0/33") {
+			t.Error("the invalid CIDR must contain nothing")
+		}
+	}
+}
+
+func TestInCIDRCache(t *testing.T) {
+	for i := 0; i < 2; i++ {
+		if !InCIDR("192.0.2.1:8080", "192.0.2.1") {
+			t.Fatal("want the IP address to be in itself")
+		}
+		if !InCIDR("2001:db8::1", "2001:db8::1") {
+			t.Fatal("want the IPv6 address to be in itself")
+		}
+	}
+
+	// the CIDR is cached by the normalized key
+	for _, key := range []string{"192.0.2.1/32", "2001:db8::1/128"} {
+		v, ok := cidrs.Load(key)
+		if !ok {
+			t.Fatalf("want the cache of %s", key)
+		}
+		if _, ok = v.(*net.IPNet); !ok {
+			t.Errorf("want the IPNet of %s, got: %v", key, v)
+		}
+	}
+	if _, ok := cidrs.Load("192.0.2.1"); ok {
+		t.Error("the CIDR must not be cached by the key without the prefix length")
+	}
+}
@@ -0,0 +1,261 @@
+package stats
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+)
+
+const (
+	HistogramScaleLinear = "linear"
+	HistogramScaleLog    = "log"
+)
+
+// histogramBarWidth is the number of the characters of the longest bar in the table
+const histogramBarWidth = 40
+
+// HistogramBucket is the number of the response times between Min and Max.
+// Min is inclusive and Max is exclusive, except that the last bucket includes Max.
+type HistogramBucket struct {
+	Min   float64 `json:"min"`
+	Max   float64 `json:"max"`
+	Count int     `json:"count"`
+}
+
+// Histogram is the distribution of the response times of the stat
+type Histogram struct {
+	Method  string             `json:"method"`
+	Uri     string             `json:"uri"`
+	Groups  map[string]string  `json:"groups,omitempty"`
+	Count   int                `json:"count"`
+	Buckets []*HistogramBucket `json:"buckets"`
+}
+
+type histogramOptions struct {
+	buckets int
+	scale   string
+	uri     string
+	uriRe   *regexp.Regexp
+}
+
+// NewHistogramBuckets divides the range of values into n buckets of the same width in the scale.
+// The log scale starts at the smallest positive value, and the values at or below it are in the first bucket.
+func NewHistogramBuckets(values []float64, n int, scale string) []*HistogramBucket {
+	if len(values) == 0 || n <= 0 {
+		return []*HistogramBucket{}
+	}
+
+	min, max := values[0], values[0]
+	lower := math.Inf(1)
+	for _, v := range values {
+		min = math.Min(min, v)
+		max = math.Max(max, v)
+		if v > 0 {
+			lower = math.Min(lower, v)
+		}
+	}
+
+	// edge returns the lower bound of the i-th bucket, and index returns the bucket of v
+	var edge func(i int) float64
+	var index func(v float64) int
+	if scale == HistogramScaleLog {
+		ratio := math.Log(max / lower)
+		if math.IsInf(lower, 1) || ratio == 0 {
+			n = 1
+		}
+		edge = func(i int) float64 {
+			return lower * math.Exp(ratio*float64(i)/float64(n))
+		}
+		index = func(v float64) int {
+			if v <= lower {
+				return 0
+			}
+			return int(float64(n) * math.Log(v/lower) / ratio)
+		}
+	} else {
+		width := (max - min) / float64(n)
+		if width == 0 {
+			n = 1
+		}
+		edge = func(i int) float64 {
+			return min + width*float64(i)
+		}
+		index = func(v float64) int {
+			if width == 0 {
+				return 0
+			}
+			return int((v - min) / width)
+		}
+	}
+
+	buckets := make([]*HistogramBucket, n)
+	for i := range buckets {
+		buckets[i] = &HistogramBucket{
+			Min: edge(i),
+			Max: edge(i + 1),
+		}
+	}
+	buckets[0].Min = min
+	buckets[n-1].Max = max
+
+	for _, v := range values {
+		// the maximum value is in the last bucket
+		i := index(v)
+		if i >= n {
+			i = n - 1
+		}
+		// the rounding errors of the index are corrected, so that v is between the bounds of the bucket
+		for i < n-1 && v >= buckets[i+1].Min {
+			i++
+		}
+		for i > 0 && v < buckets[i].Min {
+			i--
+		}
+		buckets[i].Count++
+	}
+
+	return buckets
+}
+
+// SetHistogram prints the histograms of the response times of the stats whose URIs match uri, instead of the profile results
+func (p *Printer) SetHistogram(buckets int, scale, uri string) {
+	p.histogram = &histogramOptions{
+		buckets: buckets,
+		scale:   scale,
+		uri:     uri,
+	}
+}
+
+func (p *Printer) validateHistogram() error {
+	if p.histogram == nil {
+		return nil
+	}
+
+	if p.format != "table" && p.format != "json" {
+		return fmt.Errorf("--histogram can only be used with --format table or json, got %s", p.format)
+	}
+
+	if p.printOptions.timeline {
+		return fmt.Errorf("--histogram cannot be used with --timeline")
+	}
+
+	if p.histogram.buckets <= 0 {
+		return fmt.Errorf("the number of the histogram buckets must be greater than 0, got %d", p.histogram.buckets)
+	}
+
+	if p.histogram.scale != HistogramScaleLinear && p.histogram.scale != HistogramScaleLog {
+		return fmt.Errorf("the histogram scale must be %s or %s, got %s", HistogramScaleLinear, HistogramScaleLog, p.histogram.scale)
+	}
+
+	if p.histogram.uri != "" {
+		re, err := regexp.Compile(p.histogram.uri)
+		if err != nil {
+			return err
+		}
+		p.histogram.uriRe = re
+	}
+
+	return nil
+}
+
+// histograms returns the histograms of the stats of hs in order.
+// The stats whose response times are estimated by the sketch are skipped, because the histogram needs every response time.
+func (p *Printer) histograms(hs *HTTPStats) []*Histogram {
+	histograms := make([]*Histogram, 0, len(hs.stats))
+	for _, s := range hs.stats {
+		if p.histogram.uriRe != nil && !p.histogram.uriRe.MatchString(s.Uri) {
+			continue
+		}
+
+		samples := s.ResponseTimeSamples()
+		if samples == nil {
+			continue
+		}
+
+		h := &Histogram{
+			Method:  s.Method,
+			Uri:     s.UriWithOptions(p.printOptions.decodeUri),
+			Count:   s.Cnt,
+			Buckets: NewHistogramBuckets(samples, p.histogram.buckets, p.histogram.scale),
+		}
+		if len(p.groupBy) > 0 {
+			h.Groups = make(map[string]string, len(p.groupBy))
+			for _, key := range p.groupBy {
+				h.Groups[key] = p.group(s, key, false)
+			}
+		}
+
+		histograms = append(histograms, h)
+	}
+
+	return histograms
+}
+
+func (p *Printer) printHistograms(hs *HTTPStats) {
+	histograms := p.histograms(hs)
+
+	switch p.format {
+	case "table":
+		p.printHistogramTable(histograms)
+	case "json":
+		b, _ := json.Marshal(histograms)
+		fmt.Println(string(b))
+	}
+}
+
+func (p *Printer) printHistogramTable(histograms []*Histogram) {
+	table := tablewriter.NewWriter(p.writer)
+
+	headers := []string{"Method", "Uri"}
+	for _, key := range p.groupBy {
+		headers = append(headers, p.headersMap[key])
+	}
+	headers = append(headers, "Range", "Count", "%", "Histogram")
+	if !p.printOptions.noHeaders {
+		table.SetHeader(headers)
+	}
+
+	for _, h := range histograms {
+		maxCount := 0
+		for _, b := range h.Buckets {
+			maxCount = max(maxCount, b.Count)
+		}
+
+		label := []string{h.Method, h.Uri}
+		for _, key := range p.groupBy {
+			label = append(label, h.Groups[key])
+		}
+
+		for i, b := range h.Buckets {
+			// the method and the URI are printed only in the first bucket of each stat
+			if i == 1 {
+				label = make([]string, len(label))
+			}
+
+			bar := 0
+			if maxCount > 0 {
+				bar = int(math.Round(float64(b.Count) / float64(maxCount) * histogramBarWidth))
+			}
+			// the few response times in the tail are shown as well
+			if b.Count > 0 {
+				bar = max(bar, 1)
+			}
+
+			row := append(append([]string{}, label...),
+				fmt.Sprintf("%s - %s", round(b.Min), round(b.Max)),
+				fmt.Sprint(b.Count),
+				fmt.Sprintf("%.1f", float64(b.Count)/float64(h.Count)*100),
+				strings.Repeat("#", bar),
+			)
+			table.Append(row)
+		}
+	}
+
+	table.SetAutoWrapText(false)
+	table.SetAlignment(tablewriter.ALIGN_LEFT)
+	table.Render()
+}
@@ -0,0 +1,121 @@
+package stats
+
+import (
+	"bytes"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestNewHistogramBuckets(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []float64
+		n      int
+		scale  string
+		// want is the pairs of the lower bound and the count of each bucket
+		want [][2]float64
+	}{
+		{
+			name:   "linear",
+			values: []float64{0.1, 0.15, 0.2, 0.5, 0.9, 0.5},
+			n:      4,
+			scale:  HistogramScaleLinear,
+			want:   [][2]float64{{0.1, 3}, {0.3, 0}, {0.5, 2}, {0.7, 1}},
+		},
+		{
+			name:   "log",
+			values: []float64{0.001, 0.005, 0.02, 0.05, 0.5, 1},
+			n:      3,
+			scale:  HistogramScaleLog,
+			want:   [][2]float64{{0.001, 2}, {0.01, 2}, {0.1, 2}},
+		},
+		{
+			name:   "log on the bounds",
+			values: []float64{0.001, 0.01, 0.1, 1},
+			n:      3,
+			scale:  HistogramScaleLog,
+			want:   [][2]float64{{0.001, 1}, {0.01, 1}, {0.1, 2}},
+		},
+		{
+			name:   "log with zero",
+			values: []float64{0, 0.01, 0.1},
+			n:      2,
+			scale:  HistogramScaleLog,
+			want:   [][2]float64{{0, 2}, {0.01 * math.Sqrt(10), 1}},
+		},
+		{
+			name:   "same values",
+			values: []float64{0.2, 0.2, 0.2},
+			n:      10,
+			scale:  HistogramScaleLinear,
+			want:   [][2]float64{{0.2, 3}},
+		},
+		{
+			name:  "empty",
+			n:     10,
+			scale: HistogramScaleLinear,
+			want:  [][2]float64{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buckets := NewHistogramBuckets(tt.values, tt.n, tt.scale)
+			if len(buckets) != len(tt.want) {
+				t.Fatalf("want %d buckets, got: %d", len(tt.want), len(buckets))
+			}
+
+			for i, b := range buckets {
+				if math.Abs(b.Min-tt.want[i][0]) > 1e-9 || float64(b.Count) != tt.want[i][1] {
+					t.Errorf("bucket %d want: %v, got: %+v", i, tt.want[i], b)
+				}
+			}
+		})
+	}
+}
+
+func TestPrinterHistogram(t *testing.T) {
+	hs := NewHTTPStats(true, false, false)
+	for i := 0; i < 9; i++ {
+		hs.Set("/foo", "GET", 200, 0.01, 0, 0)
+	}
+	hs.Set("/foo", "GET", 200, 1, 0, 0)
+	hs.Set("/bar", "GET", 200, 0.1, 0, 0)
+
+	var buf bytes.Buffer
+	printer := NewPrinter(&buf, "all", "table", nil, nil, nil, NewPrintOptions(false, false, false, 0, false))
+	printer.SetHistogram(2, HistogramScaleLinear, "^/fo")
+	if err := printer.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	printer.Print(hs, nil)
+
+	out := buf.String()
+	for _, want := range []string{
+		"| GET    | /foo | 0.010 - 0.505 | 9     | 90.0 | " + strings.Repeat("#", histogramBarWidth) + " |",
+		"|        |      | 0.505 - 1.000 | 1     | 10.0 | #",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("want: %s, got:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "/bar") {
+		t.Errorf("the URI that does not match must not be printed, got:\n%s", out)
+	}
+
+	for _, format := range []string{"csv", "html"} {
+		printer = NewPrinter(&buf, "all", format, nil, nil, nil, NewPrintOptions(false, false, false, 0, false))
+		printer.SetHistogram(10, HistogramScaleLinear, "")
+		if err := printer.Validate(); err == nil {
+			t.Errorf("--format %s must be an error", format)
+		}
+	}
+
+	printer = NewPrinter(&buf, "all", "json", nil, nil, nil, NewPrintOptions(false, false, false, 0, false))
+	printer.SetHistogram(10, "exp", "")
+	if err := printer.Validate(); err == nil {
+		t.Error("the unknown scale must be an error")
+	}
+}
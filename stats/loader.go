@@ -1,20 +1,207 @@
 package stats
 
 import (
+	"bufio"
+	"bytes"
+	"encoding/gob"
+	"fmt"
 	"io"
 
+	"github.com/tkuchiki/alp/helpers"
 	"gopkg.in/yaml.v2"
 )
 
+// LoadStats loads the dumped stats, and merges them into hs by the method, the URI and the groups.
+// The counts, the sums, the min/max values, the status codes and the retained percentile samples are merged,
+// so that the dumps taken from several servers can be loaded into one HTTPStats.
+// The bare lists of the stats written by the older versions of alp are migrated.
 func (hs *HTTPStats) LoadStats(r io.Reader) error {
-	buf, err := io.ReadAll(r)
+	_, err := hs.LoadStatsWithWarnings(r)
+	return err
+}
+
+// LoadStatsWithWarnings loads the dumped stats like LoadStats, and returns the warnings if the dump is aggregated
+// by the different URIs or keys from the dumps loaded before, because the merged stats of them are not meaningful.
+func (hs *HTTPStats) LoadStatsWithWarnings(r io.Reader) ([]string, error) {
+	// the binary dumps and the compressed YAML dumps are decompressed by the magic bytes, and decoded from the stream
+	dump, err := decodeDump(bufio.NewReader(helpers.NewDecompressReader(io.NopCloser(r))))
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	var warnings []string
+	if hs.metadata == nil {
+		hs.metadata = &DumpMetadata{}
+	} else {
+		warnings = GroupingWarnings(hs.metadata, dump.Metadata)
 	}
+	hs.metadata.merge(dump.Metadata)
 
-	var stats []*HTTPStat
-	err = yaml.Unmarshal(buf, &stats)
-	hs.stats = stats
+	for _, s := range dump.Stats {
+		if err = hs.MergeStat(s); err != nil {
+			return nil, err
+		}
+	}
 
-	return err
+	return warnings, nil
+}
+
+func decodeDump(br *bufio.Reader) (*Dump, error) {
+	dump := &Dump{}
+
+	isBinary, err := isBinaryDump(br)
+	if err != nil {
+		return nil, err
+	}
+
+	if isBinary {
+		if _, err = br.Discard(len(binaryDumpMagic)); err != nil {
+			return nil, err
+		}
+
+		err = gob.NewDecoder(br).Decode(dump)
+		if err != nil {
+			return nil, fmt.Errorf("not a dump of alp: %w", err)
+		}
+
+		if dump.Version > DumpVersion {
+			return nil, fmt.Errorf("unsupported dump version %d, this alp supports up to %d", dump.Version, DumpVersion)
+		}
+
+		// gob does not encode the structs whose fields are all zero, such as the response times of 0 seconds
+		for _, s := range dump.Stats {
+			restoreZeroResponseTime(s)
+		}
+	} else if isLegacyDump(br) {
+		err = yaml.NewDecoder(br).Decode(&dump.Stats)
+		if err != nil && err != io.EOF {
+			return nil, err
+		}
+
+		migrateLegacyStats(dump.Stats)
+	} else {
+		err = yaml.NewDecoder(br).Decode(dump)
+		// the empty dump has no stats
+		if err == io.EOF {
+			return dump, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("not a dump of alp: %w", err)
+		}
+
+		if dump.Version == 0 {
+			return nil, fmt.Errorf("not a dump of alp: the version is missing")
+		}
+
+		if dump.Version > DumpVersion {
+			return nil, fmt.Errorf("unsupported dump version %d, this alp supports up to %d", dump.Version, DumpVersion)
+		}
+	}
+
+	for _, s := range dump.Stats {
+		if err := validateStat(s); err != nil {
+			return nil, err
+		}
+	}
+
+	return dump, nil
+}
+
+// isBinaryDump reports whether br begins with the magic bytes of the binary dump
+func isBinaryDump(br *bufio.Reader) (bool, error) {
+	magic, err := br.Peek(len(binaryDumpMagic))
+	if err != nil && err != io.EOF {
+		return false, err
+	}
+
+	return bytes.Equal(magic, binaryDumpMagic), nil
+}
+
+// isLegacyDump reports whether br is the bare list of the stats, which is written without the envelope.
+// Only the first line that is neither empty nor a comment is peeked, within the buffer of br.
+func isLegacyDump(br *bufio.Reader) bool {
+	buf, _ := br.Peek(br.Size())
+	for _, line := range bytes.Split(buf, []byte("\n")) {
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 || line[0] == '#' || bytes.Equal(line, []byte("---")) {
+			continue
+		}
+
+		return line[0] == '-' || line[0] == '['
+	}
+
+	return false
+}
+
+// migrateLegacyStats moves the response body bytes of the legacy dumps to response_body_bytes.
+// The legacy dumps wrote the response body bytes to request_body_bytes, and left response_body_bytes empty.
+func migrateLegacyStats(stats []*HTTPStat) {
+	for _, s := range stats {
+		if s == nil {
+			continue
+		}
+
+		res := s.ResponseBodyBytes
+		if res == nil || (res.Max == 0 && res.Sum == 0) {
+			s.RequestBodyBytes, s.ResponseBodyBytes = s.ResponseBodyBytes, s.RequestBodyBytes
+		}
+
+		migrateLegacyStats(s.Timeline)
+	}
+}
+
+func restoreZeroResponseTime(s *HTTPStat) {
+	if s == nil {
+		return
+	}
+
+	if s.ResponseTime == nil {
+		s.ResponseTime = &responseTime{}
+	}
+
+	for _, b := range s.Timeline {
+		restoreZeroResponseTime(b)
+	}
+}
+
+// validateStat validates the loaded stat, so that the values of the stat can be calculated
+func validateStat(s *HTTPStat) error {
+	if s == nil {
+		return fmt.Errorf("invalid stat: the stat is empty")
+	}
+
+	if s.Cnt < 0 {
+		return fmt.Errorf("invalid stat of %s %s: the count is negative", s.Method, s.Uri)
+	}
+
+	if s.ResponseTime == nil {
+		return fmt.Errorf("invalid stat of %s %s: response_time is missing", s.Method, s.Uri)
+	}
+
+	if s.RequestBodyBytes == nil {
+		s.RequestBodyBytes = &bodyBytes{}
+	}
+
+	if s.ResponseBodyBytes == nil {
+		s.ResponseBodyBytes = &bodyBytes{}
+	}
+
+	if !validSamples(s.ResponseTime.UsePercentile, s.ResponseTime.Sketch, len(s.ResponseTime.Percentiles), s.Cnt) ||
+		!validSamples(s.RequestBodyBytes.UsePercentile, s.RequestBodyBytes.Sketch, len(s.RequestBodyBytes.Percentiles), s.Cnt) ||
+		!validSamples(s.ResponseBodyBytes.UsePercentile, s.ResponseBodyBytes.Sketch, len(s.ResponseBodyBytes.Percentiles), s.Cnt) {
+		return fmt.Errorf("invalid stat of %s %s: the number of the percentiles does not match the count", s.Method, s.Uri)
+	}
+
+	for _, b := range s.Timeline {
+		if err := validateStat(b); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// validSamples reports whether the exact percentiles retain every sample
+func validSamples(usePercentile bool, sk *sketch, n, cnt int) bool {
+	return !usePercentile || sk != nil || n == cnt
 }
@@ -2,7 +2,12 @@ package stats
 
 import (
 	"bytes"
+	"compress/gzip"
+	"strings"
 	"testing"
+	"testing/iotest"
+
+	"github.com/tkuchiki/alp/options"
 )
 
 func TestLoadStats(t *testing.T) {
@@ -60,9 +65,14 @@ func TestLoadStats(t *testing.T) {
 		t.Errorf(`uri want: %s, got: %s`, uri, s[0].Uri)
 	}
 
+	// the legacy dumps wrote the response body bytes to request_body_bytes
 	bodyMax := float64(12)
-	if bodyMax != s[0].RequestBodyBytes.Max {
-		t.Errorf(`request body bytes max want: %f, got: %f`, bodyMax, s[0].RequestBodyBytes.Max)
+	if bodyMax != s[0].ResponseBodyBytes.Max {
+		t.Errorf(`response body bytes max want: %f, got: %f`, bodyMax, s[0].ResponseBodyBytes.Max)
+	}
+
+	if s[0].RequestBodyBytes.Max != 0 {
+		t.Errorf(`request body bytes max want: 0, got: %f`, s[0].RequestBodyBytes.Max)
 	}
 
 	restimeMax := float64(0.057)
@@ -95,3 +105,374 @@ func TestLoadStats(t *testing.T) {
 		t.Errorf(`status5xx want: %d, got: %d`, status5xx, s[0].Status5xx)
 	}
 }
+
+func TestLoadStatsMerge(t *testing.T) {
+	dump1 := bytes.NewBufferString(`- uri: /foo/bar
+  count: 2
+  status2xx: 1
+  status5xx: 1
+  method: GET
+  response_time:
+    max: 0.2
+    min: 0.1
+    sum: 0.3
+    usepercentile: true
+    percentiles:
+    - 0.1
+    - 0.2
+- uri: /baz
+  count: 1
+  status2xx: 1
+  method: GET
+  response_time:
+    max: 0.5
+    min: 0.5
+    sum: 0.5
+    usepercentile: true
+    percentiles:
+    - 0.5
+`)
+	dump2 := bytes.NewBufferString(`- uri: /foo/bar
+  count: 2
+  status2xx: 2
+  method: GET
+  response_time:
+    max: 0.4
+    min: 0.05
+    sum: 0.45
+    usepercentile: true
+    percentiles:
+    - 0.05
+    - 0.4
+- uri: /foo/bar
+  count: 1
+  status2xx: 1
+  method: POST
+  response_time:
+    max: 0.3
+    min: 0.3
+    sum: 0.3
+    usepercentile: false
+    percentiles: []
+`)
+
+	sts := NewHTTPStats(true, false, false)
+	if err := sts.LoadStats(dump1); err != nil {
+		t.Fatal(err)
+	}
+	if err := sts.LoadStats(dump2); err != nil {
+		t.Fatal(err)
+	}
+
+	if sts.CountUris() != 3 {
+		t.Fatalf("uris want: 3, got: %d", sts.CountUris())
+	}
+
+	s := sts.Stats()[0]
+	if s.Method != "GET" || s.Uri != "/foo/bar" {
+		t.Fatalf("stat want: GET /foo/bar, got: %s %s", s.Method, s.Uri)
+	}
+
+	if s.Cnt != 4 || s.Status2xx != 3 || s.Status5xx != 1 {
+		t.Errorf("count want: 4 (2xx: 3, 5xx: 1), got: %d (2xx: %d, 5xx: %d)", s.Cnt, s.Status2xx, s.Status5xx)
+	}
+
+	if s.MinResponseTime() != 0.05 || s.MaxResponseTime() != 0.4 {
+		t.Errorf("min/max want: 0.05/0.4, got: %f/%f", s.MinResponseTime(), s.MaxResponseTime())
+	}
+
+	if got := s.SumResponseTime(); got != 0.75 {
+		t.Errorf("sum want: 0.75, got: %f", got)
+	}
+
+	// the percentiles are calculated from the samples of both dumps
+	if got := s.PNResponseTime(50); got != 0.1 {
+		t.Errorf("p50 want: 0.1, got: %f", got)
+	}
+
+	if got := s.PNResponseTime(100); got != 0.4 {
+		t.Errorf("p100 want: 0.4, got: %f", got)
+	}
+
+	// the percentiles are not calculated if a dump does not retain the samples
+	dump3 := bytes.NewBufferString(`- uri: /baz
+  count: 1
+  status2xx: 1
+  method: GET
+  response_time:
+    max: 0.7
+    min: 0.7
+    sum: 0.7
+    usepercentile: false
+    percentiles: []
+`)
+	if err := sts.LoadStats(dump3); err != nil {
+		t.Fatal(err)
+	}
+
+	s = sts.Stats()[1]
+	if s.Cnt != 2 {
+		t.Errorf("count want: 2, got: %d", s.Cnt)
+	}
+
+	if got := s.PNResponseTime(99); got != 0 {
+		t.Errorf("p99 want: 0, got: %f", got)
+	}
+}
+
+func TestLoadStatsDump(t *testing.T) {
+	opts := options.NewOptions(
+		options.Location("UTC"),
+		options.MatchingGroups([]*options.MatchingGroup{{Name: "/foo/:id", Pattern: "^/foo/[0-9]+$"}}),
+		options.Follow(true),
+		options.MetricsListen(":9100"),
+	)
+
+	sts := NewHTTPStats(true, false, false)
+	if err := sts.InitFilter(opts); err != nil {
+		t.Fatal(err)
+	}
+	sts.SetOptions(opts)
+	sts.SetParser("ltsv")
+	sts.EnableLogTimeRange()
+	sts.SkipLine()
+
+	sts.logSummary.Lines = 2
+	for _, tm := range []string{"2026-01-02T03:04:05Z", "2026-01-02T03:00:00Z"} {
+		if err := sts.SetWithTime("/foo/1", "GET", tm, nil, 200, 0.1, 100, 10); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	buf := new(bytes.Buffer)
+	if err := sts.DumpStats(buf); err != nil {
+		t.Fatal(err)
+	}
+
+	// only the options of the grouping and the parser are dumped
+	dumped := buf.String()
+	for _, want := range []string{"matching_groups:", "percentiles:", "location: UTC", "ltsv:"} {
+		if !strings.Contains(dumped, want) {
+			t.Errorf("want: %s, got: %s", want, dumped)
+		}
+	}
+	for _, notWant := range []string{"follow", "metrics_listen", "histogram", "pos_file", "dump", "json:", "regexp:"} {
+		if strings.Contains(dumped, notWant) {
+			t.Errorf("the option must not be dumped: %s, got: %s", notWant, dumped)
+		}
+	}
+
+	loaded := NewHTTPStats(true, false, false)
+	if err := loaded.LoadStats(buf); err != nil {
+		t.Fatal(err)
+	}
+
+	md := loaded.Metadata()
+	if md.Parser != "ltsv" {
+		t.Errorf("parser want: ltsv, got: %s", md.Parser)
+	}
+
+	if md.Options == nil || len(md.Options.MatchingGroups) != 1 || md.Options.MatchingGroups[0].Name != "/foo/:id" {
+		t.Errorf("matching groups are not loaded: %+v", md.Options)
+	}
+
+	if md.Log.Lines != 2 || md.Log.SkippedLines != 1 {
+		t.Errorf("lines want: 2 (skipped: 1), got: %d (skipped: %d)", md.Log.Lines, md.Log.SkippedLines)
+	}
+
+	if got := md.Log.FirstTime.Format("15:04:05"); got != "03:00:00" {
+		t.Errorf("first time want: 03:00:00, got: %s", got)
+	}
+
+	if got := md.Log.LastTime.Format("15:04:05"); got != "03:04:05" {
+		t.Errorf("last time want: 03:04:05, got: %s", got)
+	}
+
+	// the dumps with the version are not migrated
+	s := loaded.Stats()[0]
+	if s.RequestBodyBytes.Max != 10 || s.ResponseBodyBytes.Max != 100 {
+		t.Errorf("body bytes want: 10/100, got: %f/%f", s.RequestBodyBytes.Max, s.ResponseBodyBytes.Max)
+	}
+
+	// the grouping of the same options is compatible
+	if warnings := GroupingWarnings(sts.Metadata(), md); len(warnings) != 0 {
+		t.Errorf("warnings want: none, got: %v", warnings)
+	}
+
+	other := &DumpMetadata{Options: &DumpOptions{GroupBy: []string{"host"}}}
+	if warnings := GroupingWarnings(md, other); len(warnings) != 2 {
+		t.Errorf("warnings want: matching groups and group by, got: %v", warnings)
+	}
+}
+
+func TestLoadStatsWithWarnings(t *testing.T) {
+	dump := func(opts *options.Options) *bytes.Buffer {
+		sts := NewHTTPStats(true, false, false)
+		sts.SetOptions(opts)
+		sts.Set("/foo/1", "GET", 200, 0.1, 0, 0)
+
+		buf := new(bytes.Buffer)
+		if err := sts.DumpStats(buf); err != nil {
+			t.Fatal(err)
+		}
+
+		return buf
+	}
+
+	grouped := options.NewOptions(options.MatchingGroups([]*options.MatchingGroup{{Pattern: "^/foo/[0-9]+$"}}))
+
+	tests := []struct {
+		name string
+		opts []*options.Options
+		want []string
+	}{
+		{
+			name: "same options",
+			opts: []*options.Options{grouped, grouped},
+			want: nil,
+		},
+		{
+			name: "different options",
+			opts: []*options.Options{
+				grouped,
+				options.NewOptions(options.GroupBy([]string{"host"}), options.QueryString(true)),
+			},
+			want: []string{
+				"the matching groups are different: [^/foo/[0-9]+$] and []",
+				"the group by keys are different: [] and [host]",
+				"the query strings are aggregated differently",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sts := NewHTTPStats(true, false, false)
+
+			var got []string
+			for _, opts := range tt.opts {
+				warnings, err := sts.LoadStatsWithWarnings(dump(opts))
+				if err != nil {
+					t.Fatal(err)
+				}
+				got = append(got, warnings...)
+			}
+
+			if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("want: %v, got: %v", tt.want, got)
+			}
+
+			// the options of the first dump are kept
+			if md := sts.Metadata(); len(md.Options.MatchingGroups) != 1 {
+				t.Errorf("want the options of the first dump, got: %+v", md.Options)
+			}
+		})
+	}
+}
+
+func TestLoadStatsInvalid(t *testing.T) {
+	tests := []struct {
+		data string
+		err  string
+	}{
+		{
+			data: "version: 2\nstats: []\n",
+			err:  "unsupported dump version 2",
+		},
+		{
+			data: "format: json\nsort: count\n",
+			err:  "the version is missing",
+		},
+		{
+			data: "version: 1\nstats:\n- uri: /foo\n  method: GET\n  count: 1\n",
+			err:  "response_time is missing",
+		},
+		{
+			data: `version: 1
+stats:
+- uri: /foo
+  method: GET
+  count: 2
+  response_time:
+    max: 0.1
+    min: 0.1
+    sum: 0.1
+    usepercentile: true
+    percentiles:
+    - 0.1
+`,
+			err: "the number of the percentiles does not match the count",
+		},
+	}
+
+	for _, tt := range tests {
+		sts := NewHTTPStats(true, false, false)
+		err := sts.LoadStats(strings.NewReader(tt.data))
+		if err == nil || !strings.Contains(err.Error(), tt.err) {
+			t.Errorf("error want: %s, got: %v", tt.err, err)
+		}
+	}
+}
+
+func TestLoadStatsStream(t *testing.T) {
+	sts := NewHTTPStats(true, false, false)
+	sts.Set("/foo", "GET", 200, 0.1, 10, 0)
+	sts.Set("/foo", "GET", 200, 0.3, 20, 0)
+
+	yml := new(bytes.Buffer)
+	if err := sts.DumpStatsWithFormat(yml, DumpFormatYAML); err != nil {
+		t.Fatal(err)
+	}
+
+	gzipped := new(bytes.Buffer)
+	gw := gzip.NewWriter(gzipped)
+	if _, err := gw.Write(yml.Bytes()); err != nil {
+		t.Fatal(err)
+	}
+	if err := gw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	bin := new(bytes.Buffer)
+	if err := sts.DumpStatsWithFormat(bin, DumpFormatBinary); err != nil {
+		t.Fatal(err)
+	}
+
+	legacy := "# the dump of the older versions\n- uri: /foo\n  method: GET\n  count: 2\n  response_time:\n    max: 0.3\n    min: 0.1\n    sum: 0.4\n"
+
+	tests := []struct {
+		name string
+		data []byte
+		cnt  int
+	}{
+		{name: "yaml", data: yml.Bytes(), cnt: 2},
+		{name: "gzip", data: gzipped.Bytes(), cnt: 2},
+		{name: "binary", data: bin.Bytes(), cnt: 2},
+		{name: "legacy", data: []byte(legacy), cnt: 2},
+		{name: "empty", data: []byte("# no stats\n\n")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// the format is detected by the magic bytes, and the dump is decoded from the reader of one byte at a time
+			loaded := NewHTTPStats(true, false, false)
+			if err := loaded.LoadStats(iotest.OneByteReader(bytes.NewReader(tt.data))); err != nil {
+				t.Fatal(err)
+			}
+
+			if tt.cnt == 0 {
+				if len(loaded.Stats()) != 0 {
+					t.Errorf("want no stats, got: %d", len(loaded.Stats()))
+				}
+				return
+			}
+
+			if len(loaded.Stats()) != 1 || loaded.Stats()[0].Cnt != tt.cnt {
+				t.Fatalf("want the count %d of /foo, got: %+v", tt.cnt, loaded.Stats())
+			}
+			if got := loaded.Stats()[0].MaxResponseTime(); got != 0.3 {
+				t.Errorf("max want: 0.3, got: %f", got)
+			}
+		})
+	}
+}
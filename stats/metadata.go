@@ -0,0 +1,225 @@
+package stats
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/tkuchiki/alp/options"
+)
+
+// DumpMetadata describes how the dumped stats were created
+type DumpMetadata struct {
+	CreatedAt time.Time `yaml:"created_at"`
+	// Parser is the format of the profiled logs, e.g. json, ltsv, regexp and pcap
+	Parser  string       `yaml:"parser,omitempty"`
+	Options *DumpOptions `yaml:"options,omitempty"`
+	Log     *LogSummary  `yaml:"log,omitempty"`
+}
+
+// DumpOptions is the subset of the options that affect how the logs are parsed and grouped into the stats.
+// The options only for running alp, such as --follow and the output paths, are not dumped.
+type DumpOptions struct {
+	MatchingGroups          []*options.MatchingGroup `yaml:"matching_groups,omitempty"`
+	GroupBy                 []string                 `yaml:"group_by,omitempty"`
+	Filters                 string                   `yaml:"filters,omitempty"`
+	Percentiles             []int                    `yaml:"percentiles,omitempty"`
+	PercentileEstimator     string                   `yaml:"percentile_estimator,omitempty"`
+	PercentileAccuracy      float64                  `yaml:"percentile_accuracy,omitempty"`
+	StatusCodes             []int                    `yaml:"status_codes,omitempty"`
+	ApdexThreshold          float64                  `yaml:"apdex_threshold,omitempty"`
+	QueryString             bool                     `yaml:"query_string"`
+	QueryStringIgnoreValues bool                     `yaml:"query_string_ignore_values"`
+	DecodeUri               bool                     `yaml:"decode_uri"`
+	Location                string                   `yaml:"location,omitempty"`
+	OpenAPI                 string                   `yaml:"openapi,omitempty"`
+	LTSV                    *options.LTSVOptions     `yaml:"ltsv,omitempty"`
+	Regexp                  *options.RegexpOptions   `yaml:"regexp,omitempty"`
+	JSON                    *options.JSONOptions     `yaml:"json,omitempty"`
+	Pcap                    *options.PcapOptions     `yaml:"pcap,omitempty"`
+}
+
+// newDumpOptions returns the dumped options of opts, and only the options of the parser are dumped among the parsers
+func newDumpOptions(parser string, opts *options.Options) *DumpOptions {
+	if opts == nil {
+		return nil
+	}
+
+	do := &DumpOptions{
+		MatchingGroups:          opts.MatchingGroups,
+		GroupBy:                 opts.GroupBy,
+		Filters:                 opts.Filters,
+		Percentiles:             opts.Percentiles,
+		PercentileEstimator:     opts.PercentileEstimator,
+		PercentileAccuracy:      opts.PercentileAccuracy,
+		StatusCodes:             opts.StatusCodes,
+		ApdexThreshold:          opts.ApdexThreshold,
+		QueryString:             opts.QueryString,
+		QueryStringIgnoreValues: opts.QueryStringIgnoreValues,
+		DecodeUri:               opts.DecodeUri,
+		Location:                opts.Location,
+		OpenAPI:                 opts.OpenAPI,
+	}
+
+	switch parser {
+	case "ltsv":
+		do.LTSV = opts.LTSV
+	case "regexp":
+		do.Regexp = opts.Regexp
+	case "json":
+		do.JSON = opts.JSON
+	case "pcap":
+		do.Pcap = opts.Pcap
+	}
+
+	return do
+}
+
+// LogSummary is the time range and the line counts of the profiled logs.
+// The time range is recorded only if the time of the logs is parsed, see HTTPStats.EnableLogTimeRange.
+type LogSummary struct {
+	FirstTime time.Time `yaml:"first_time,omitempty"`
+	LastTime  time.Time `yaml:"last_time,omitempty"`
+	// Lines is the number of the parsed lines, including the filtered lines
+	Lines         int `yaml:"lines"`
+	SkippedLines  int `yaml:"skipped_lines"`
+	FilteredLines int `yaml:"filtered_lines"`
+	// requests is the number of the requests in each second for the HTML report, and is not dumped
+	requests map[int64]int
+}
+
+func (ls *LogSummary) setTime(t time.Time) {
+	if ls.FirstTime.IsZero() || t.Before(ls.FirstTime) {
+		ls.FirstTime = t
+	}
+
+	if t.After(ls.LastTime) {
+		ls.LastTime = t
+	}
+}
+
+func (ls *LogSummary) countRequest(t time.Time) {
+	if ls.requests == nil {
+		ls.requests = make(map[int64]int)
+	}
+
+	ls.requests[t.Unix()]++
+}
+
+func (ls *LogSummary) merge(other *LogSummary) {
+	if other == nil {
+		return
+	}
+
+	if !other.FirstTime.IsZero() {
+		ls.setTime(other.FirstTime)
+		ls.setTime(other.LastTime)
+	}
+
+	ls.Lines += other.Lines
+	ls.SkippedLines += other.SkippedLines
+	ls.FilteredLines += other.FilteredLines
+
+	for sec, cnt := range other.requests {
+		if ls.requests == nil {
+			ls.requests = make(map[int64]int)
+		}
+		ls.requests[sec] += cnt
+	}
+}
+
+// merge merges the metadata of another dump loaded into the same stats.
+// The options of the first dump are kept, and the log summaries are summed up.
+func (md *DumpMetadata) merge(other *DumpMetadata) {
+	if other == nil {
+		return
+	}
+
+	if other.CreatedAt.After(md.CreatedAt) {
+		md.CreatedAt = other.CreatedAt
+	}
+
+	if md.Parser == "" {
+		md.Parser = other.Parser
+	}
+
+	if md.Options == nil {
+		md.Options = other.Options
+	}
+
+	if other.Log != nil {
+		if md.Log == nil {
+			md.Log = &LogSummary{}
+		}
+		md.Log.merge(other.Log)
+	}
+}
+
+// Metadata returns the metadata of the loaded dumps, or the metadata of the profiled logs if no dump is loaded
+func (hs *HTTPStats) Metadata() *DumpMetadata {
+	if hs.metadata != nil {
+		return hs.metadata
+	}
+
+	return &DumpMetadata{
+		Parser:  hs.parser,
+		Options: newDumpOptions(hs.parser, hs.options),
+		Log:     hs.logSummary,
+	}
+}
+
+// GroupingWarnings returns the warnings if the two results are aggregated by the different URIs or keys,
+// because the differences between them are not meaningful. The dumps without the options are not compared.
+func GroupingWarnings(from, to *DumpMetadata) []string {
+	if from == nil || to == nil || from.Options == nil || to.Options == nil {
+		return nil
+	}
+
+	fromOpts := from.Options
+	toOpts := to.Options
+	warnings := make([]string, 0)
+
+	if !equalMatchingGroups(fromOpts.MatchingGroups, toOpts.MatchingGroups) {
+		warnings = append(warnings, fmt.Sprintf("the matching groups are different: [%s] and [%s]",
+			matchingGroupsString(fromOpts.MatchingGroups), matchingGroupsString(toOpts.MatchingGroups)))
+	}
+
+	if strings.Join(fromOpts.GroupBy, ",") != strings.Join(toOpts.GroupBy, ",") {
+		warnings = append(warnings, fmt.Sprintf("the group by keys are different: [%s] and [%s]",
+			strings.Join(fromOpts.GroupBy, ","), strings.Join(toOpts.GroupBy, ",")))
+	}
+
+	if fromOpts.QueryString != toOpts.QueryString || fromOpts.QueryStringIgnoreValues != toOpts.QueryStringIgnoreValues {
+		warnings = append(warnings, "the query strings are aggregated differently")
+	}
+
+	if fromOpts.OpenAPI != toOpts.OpenAPI {
+		warnings = append(warnings, fmt.Sprintf("the OpenAPI documents are different: '%s' and '%s'", fromOpts.OpenAPI, toOpts.OpenAPI))
+	}
+
+	return warnings
+}
+
+func equalMatchingGroups(a, b []*options.MatchingGroup) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i := range a {
+		if a[i].Name != b[i].Name || a[i].Pattern != b[i].Pattern ||
+			strings.Join(a[i].Methods, ",") != strings.Join(b[i].Methods, ",") {
+			return false
+		}
+	}
+
+	return true
+}
+
+func matchingGroupsString(groups []*options.MatchingGroup) string {
+	patterns := make([]string, 0, len(groups))
+	for _, g := range groups {
+		patterns = append(patterns, g.Pattern)
+	}
+
+	return strings.Join(patterns, ",")
+}
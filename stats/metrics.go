@@ -0,0 +1,208 @@
+package stats
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	metricsPrefix = "alp_http_"
+
+	// PrometheusContentType is the content type of the text format of Prometheus
+	PrometheusContentType = "text/plain; version=0.0.4; charset=utf-8"
+	// OpenMetricsContentType is the content type of the text format of OpenMetrics
+	OpenMetricsContentType = "application/openmetrics-text; version=1.0.0; charset=utf-8"
+)
+
+// bucketCounter counts the values in the buckets of the upper bounds, like the histogram of Prometheus.
+// counts are not cumulative, and the last count is the bucket of +Inf.
+type bucketCounter struct {
+	bounds []float64
+	counts []int
+	sum    float64
+}
+
+func newBucketCounter(bounds []float64) *bucketCounter {
+	return &bucketCounter{
+		bounds: bounds,
+		counts: make([]int, len(bounds)+1),
+	}
+}
+
+func (bc *bucketCounter) observe(val float64) {
+	// the bucket of the smallest upper bound that is greater than or equal to val
+	bc.counts[sort.SearchFloat64s(bc.bounds, val)]++
+	bc.sum += val
+}
+
+func (bc *bucketCounter) merge(other *bucketCounter) error {
+	if len(bc.counts) != len(other.counts) {
+		return fmt.Errorf("cannot merge the buckets of the different bounds")
+	}
+
+	for i, c := range other.counts {
+		bc.counts[i] += c
+	}
+	bc.sum += other.sum
+
+	return nil
+}
+
+var (
+	labelValueReplacer = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+	invalidLabelNameRe = regexp.MustCompile(`[^a-zA-Z0-9_]`)
+)
+
+// labelName replaces the characters that are not allowed in the label name with underscores
+func labelName(key string) string {
+	name := invalidLabelNameRe.ReplaceAllString(key, "_")
+	if name == "" || (name[0] >= '0' && name[0] <= '9') {
+		name = "_" + name
+	}
+
+	return name
+}
+
+// reservedLabelNames are the labels of the metrics, which the keys of group-by cannot be named
+var reservedLabelNames = []string{"method", "uri", "status_class", "le"}
+
+// ValidateMetricsLabels returns the error if the label names of the keys of group-by conflict with the labels of the metrics or each other,
+// which makes the exposition invalid
+func ValidateMetricsLabels(groupBy []string) error {
+	keys := make(map[string]string, len(reservedLabelNames)+len(groupBy))
+	for _, name := range reservedLabelNames {
+		keys[name] = name
+	}
+
+	for _, key := range groupBy {
+		name := labelName(key)
+		if strings.HasPrefix(name, "__") {
+			return fmt.Errorf("the label name of the group-by key is reserved by Prometheus: %s", key)
+		}
+
+		if other, ok := keys[name]; ok {
+			return fmt.Errorf("the label name of the group-by key conflicts with %s: %s", other, key)
+		}
+		keys[name] = key
+	}
+
+	return nil
+}
+
+// statLabels returns the labels of the method, the URI and the keys of group-by of s, followed by extra
+func (hs *HTTPStats) statLabels(s *HTTPStat, extra ...string) string {
+	labels := make([]string, 0, 4+len(hs.groupBy)*2+len(extra))
+	labels = append(labels, "method", s.Method, "uri", s.Uri)
+	for i, key := range hs.groupBy {
+		labels = append(labels, labelName(key), s.Group(i))
+	}
+
+	return formatLabels(append(labels, extra...)...)
+}
+
+func formatLabels(labels ...string) string {
+	pairs := make([]string, 0, len(labels)/2)
+	for i := 0; i+1 < len(labels); i += 2 {
+		pairs = append(pairs, fmt.Sprintf(`%s="%s"`, labels[i], labelValueReplacer.Replace(labels[i+1])))
+	}
+
+	return fmt.Sprintf("{%s}", strings.Join(pairs, ","))
+}
+
+func formatMetricValue(val float64) string {
+	if math.IsInf(val, 1) {
+		return "+Inf"
+	}
+
+	return strconv.FormatFloat(val, 'g', -1, 64)
+}
+
+type metricsWriter struct {
+	w           *bufio.Writer
+	openMetrics bool
+}
+
+// family writes the HELP and TYPE of the metric family.
+// The name of the counter family does not have the suffix _total in OpenMetrics.
+func (mw *metricsWriter) family(name, typ, help string) {
+	if mw.openMetrics && typ == "counter" {
+		name = strings.TrimSuffix(name, "_total")
+	}
+
+	fmt.Fprintf(mw.w, "# HELP %s%s %s\n", metricsPrefix, name, help)
+	fmt.Fprintf(mw.w, "# TYPE %s%s %s\n", metricsPrefix, name, typ)
+}
+
+func (mw *metricsWriter) sample(name, labels string, val float64) {
+	fmt.Fprintf(mw.w, "%s%s%s %s\n", metricsPrefix, name, labels, formatMetricValue(val))
+}
+
+// WriteMetrics writes the stats in the text format of Prometheus, or OpenMetrics if openMetrics is true.
+// The histogram of the response times is written only if SetResponseTimeBuckets is called.
+func (hs *HTTPStats) WriteMetrics(w io.Writer, openMetrics bool) error {
+	mw := &metricsWriter{
+		w:           bufio.NewWriter(w),
+		openMetrics: openMetrics,
+	}
+
+	mw.family("requests_total", "counter", "The number of requests by method and URI.")
+	for _, s := range hs.stats {
+		mw.sample("requests_total", hs.statLabels(s), float64(s.Cnt))
+	}
+
+	mw.family("responses_total", "counter", "The number of responses by method, URI and status class.")
+	for _, s := range hs.stats {
+		classes := []struct {
+			name string
+			cnt  int
+		}{
+			{"1xx", s.Status1xx},
+			{"2xx", s.Status2xx},
+			{"3xx", s.Status3xx},
+			{"4xx", s.Status4xx},
+			{"5xx", s.Status5xx},
+		}
+
+		for _, c := range classes {
+			mw.sample("responses_total", hs.statLabels(s, "status_class", c.name), float64(c.cnt))
+		}
+	}
+
+	if len(hs.responseTimeBuckets) > 0 {
+		mw.family("response_time_seconds", "histogram", "The response time in seconds by method and URI.")
+		for _, s := range hs.stats {
+			bc := s.responseTimeBuckets
+			if bc == nil {
+				continue
+			}
+
+			var cumulative int
+			for i, c := range bc.counts {
+				cumulative += c
+
+				bound := math.Inf(1)
+				if i < len(bc.bounds) {
+					bound = bc.bounds[i]
+				}
+
+				mw.sample("response_time_seconds_bucket", hs.statLabels(s, "le", formatMetricValue(bound)), float64(cumulative))
+			}
+
+			labels := hs.statLabels(s)
+			mw.sample("response_time_seconds_sum", labels, bc.sum)
+			mw.sample("response_time_seconds_count", labels, float64(cumulative))
+		}
+	}
+
+	if openMetrics {
+		fmt.Fprintln(mw.w, "# EOF")
+	}
+
+	return mw.w.Flush()
+}
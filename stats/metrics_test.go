@@ -0,0 +1,101 @@
+package stats
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/tkuchiki/alp/options"
+)
+
+func TestWriteMetrics(t *testing.T) {
+	hs := NewHTTPStats(true, false, false)
+	if err := hs.SetResponseTimeBuckets("0.1,1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := hs.SetURIMatchingGroups(options.NewMatchingGroups([]string{`/foo/\d+`})); err != nil {
+		t.Fatal(err)
+	}
+
+	hs.Set("/foo/1", "GET", 200, 0.05, 10, 0)
+	hs.Set("/foo/2", "GET", 500, 0.5, 10, 0)
+	hs.Set(`/bar"`, "POST", 404, 2, 10, 0)
+
+	var buf bytes.Buffer
+	if err := hs.WriteMetrics(&buf, false); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{
+		`# TYPE alp_http_requests_total counter`,
+		`alp_http_requests_total{method="GET",uri="/foo/\\d+"} 2`,
+		`alp_http_requests_total{method="POST",uri="/bar\""} 1`,
+		`alp_http_responses_total{method="GET",uri="/foo/\\d+",status_class="2xx"} 1`,
+		`alp_http_responses_total{method="GET",uri="/foo/\\d+",status_class="5xx"} 1`,
+		`alp_http_responses_total{method="POST",uri="/bar\"",status_class="4xx"} 1`,
+		`# TYPE alp_http_response_time_seconds histogram`,
+		`alp_http_response_time_seconds_bucket{method="GET",uri="/foo/\\d+",le="0.1"} 1`,
+		`alp_http_response_time_seconds_bucket{method="GET",uri="/foo/\\d+",le="1"} 2`,
+		`alp_http_response_time_seconds_bucket{method="GET",uri="/foo/\\d+",le="+Inf"} 2`,
+		`alp_http_response_time_seconds_sum{method="GET",uri="/foo/\\d+"} 0.55`,
+		`alp_http_response_time_seconds_count{method="GET",uri="/foo/\\d+"} 2`,
+		`alp_http_response_time_seconds_bucket{method="POST",uri="/bar\"",le="1"} 0`,
+		`alp_http_response_time_seconds_bucket{method="POST",uri="/bar\"",le="+Inf"} 1`,
+	}
+
+	got := buf.String()
+	for _, w := range want {
+		if !strings.Contains(got, w+"\n") {
+			t.Errorf("want: %s, got:\n%s", w, got)
+		}
+	}
+
+	buf.Reset()
+	if err := hs.WriteMetrics(&buf, true); err != nil {
+		t.Fatal(err)
+	}
+
+	got = buf.String()
+	if !strings.Contains(got, "# TYPE alp_http_requests counter\n") {
+		t.Errorf("want the counter family without _total, got:\n%s", got)
+	}
+	if !strings.HasSuffix(got, "# EOF\n") {
+		t.Errorf("want # EOF at the end, got:\n%s", got)
+	}
+}
+
+func TestSetResponseTimeBuckets(t *testing.T) {
+	hs := NewHTTPStats(true, false, false)
+	if err := hs.SetResponseTimeBuckets("1,0.5"); err == nil {
+		t.Error("want error for the buckets in decreasing order")
+	}
+	if err := hs.SetResponseTimeBuckets("0.1,a"); err == nil {
+		t.Error("want error for the invalid bucket")
+	}
+}
+
+func TestValidateMetricsLabels(t *testing.T) {
+	tests := []struct {
+		groupBy []string
+		valid   bool
+	}{
+		{groupBy: nil, valid: true},
+		{groupBy: []string{"host", "user-agent"}, valid: true},
+		{groupBy: []string{"method"}},
+		{groupBy: []string{"uri"}},
+		{groupBy: []string{"status_class"}},
+		{groupBy: []string{"le"}},
+		{groupBy: []string{"__name"}},
+		// both are the label user_agent
+		{groupBy: []string{"user-agent", "user_agent"}},
+	}
+
+	for _, tt := range tests {
+		err := ValidateMetricsLabels(tt.groupBy)
+		if tt.valid && err != nil {
+			t.Errorf("%v want no error, got: %v", tt.groupBy, err)
+		} else if !tt.valid && err == nil {
+			t.Errorf("%v want an error", tt.groupBy)
+		}
+	}
+}
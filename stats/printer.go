@@ -4,15 +4,56 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"slices"
+	"strconv"
 	"strings"
 
 	"github.com/olekukonko/tablewriter"
 	"github.com/tkuchiki/alp/convert"
 	"github.com/tkuchiki/alp/helpers"
-	"github.com/tkuchiki/alp/html"
 )
 
-func keywords(percentiles []int) []string {
+// undocumentedMark is appended to the URIs that match no route of the OpenAPI document
+const undocumentedMark = " (undocumented)"
+
+// reservedKeywords are the keywords of the columns that are not always in the headers, such as the time of the timeline.
+// They cannot be the keys of group-by, because the columns take precedence over the keys.
+var reservedKeywords = []string{"time", "other"}
+
+const (
+	// bodyPercentileSuffix is the suffix of the keywords of the response body bytes percentiles such as p90_body
+	bodyPercentileSuffix = "_body"
+	// reqBodyPercentileSuffix is the suffix of the keywords of the request body bytes percentiles such as p90_req_body
+	reqBodyPercentileSuffix = "_req_body"
+)
+
+// parsePercentileKeyword parses the keyword of the percentile such as p90, p90_body and p90_req_body
+func parsePercentileKeyword(key string) (n int, suffix string, ok bool) {
+	if !strings.HasPrefix(key, "p") {
+		return 0, "", false
+	}
+
+	suffix = strings.TrimLeft(key[1:], "0123456789")
+	n, err := strconv.Atoi(key[1 : len(key)-len(suffix)])
+	if err != nil {
+		return 0, "", false
+	}
+
+	return n, suffix, true
+}
+
+// ParsePercentileKeyword parses the keyword of the percentile such as p90, p90_body and p90_req_body.
+// suffix is "", "_body" or "_req_body" if the keyword is valid
+func ParsePercentileKeyword(key string) (n int, suffix string, ok bool) {
+	n, suffix, ok = parsePercentileKeyword(key)
+	if !ok || (suffix != "" && suffix != bodyPercentileSuffix && suffix != reqBodyPercentileSuffix) {
+		return 0, "", false
+	}
+
+	return n, suffix, true
+}
+
+func keywords(percentiles, statusCodes []int, groupBy []string) []string {
 	s1 := []string{
 		"count",
 		"1xx",
@@ -20,13 +61,19 @@ func keywords(percentiles []int) []string {
 		"3xx",
 		"4xx",
 		"5xx",
+	}
+	s1 = append(s1, statusCodeKeywords(statusCodes)...)
+	s1 = append(s1,
 		"method",
 		"uri",
+	)
+	s1 = append(s1, groupBy...)
+	s1 = append(s1,
 		"min",
 		"max",
 		"sum",
 		"avg",
-	}
+	)
 
 	s2 := []string{
 		"stddev",
@@ -49,7 +96,27 @@ func keywords(percentiles []int) []string {
 	return s
 }
 
-func defaultHeaders(percentiles []int) []string {
+// Keywords returns all the keywords that can be specified by --output
+func Keywords(percentiles, statusCodes []int, groupBy []string) []string {
+	return keywords(percentiles, statusCodes, groupBy)
+}
+
+// statusCodeKeywords returns the keywords of the exact status codes and the other status codes.
+// They are displayed only if the status codes are specified.
+func statusCodeKeywords(statusCodes []int) []string {
+	if len(statusCodes) == 0 {
+		return []string{}
+	}
+
+	s := make([]string, 0, len(statusCodes)+1)
+	for _, code := range statusCodes {
+		s = append(s, strconv.Itoa(code))
+	}
+
+	return append(s, "other")
+}
+
+func defaultHeaders(percentiles, statusCodes []int, groupBy []string) []string {
 	s1 := []string{
 		"Count",
 		"1xx",
@@ -57,13 +124,25 @@ func defaultHeaders(percentiles []int) []string {
 		"3xx",
 		"4xx",
 		"5xx",
+	}
+	for _, key := range statusCodeKeywords(statusCodes) {
+		if key == "other" {
+			s1 = append(s1, "Other")
+			continue
+		}
+		s1 = append(s1, key)
+	}
+	s1 = append(s1,
 		"Method",
 		"Uri",
+	)
+	s1 = append(s1, groupBy...)
+	s1 = append(s1,
 		"Min",
 		"Max",
 		"Sum",
 		"Avg",
-	}
+	)
 
 	s2 := []string{
 		"Stddev",
@@ -86,7 +165,7 @@ func defaultHeaders(percentiles []int) []string {
 	return s
 }
 
-func headersMap(percentiles []int) map[string]string {
+func headersMap(percentiles, statusCodes []int, groupBy []string) map[string]string {
 	headers := map[string]string{
 		"count":    "Count",
 		"1xx":      "1xx",
@@ -105,12 +184,46 @@ func headersMap(percentiles []int) map[string]string {
 		"max_body": "Max(Body)",
 		"sum_body": "Sum(Body)",
 		"avg_body": "Avg(Body)",
+		// the following keywords are not displayed by default, because they retain every body size
+		"stddev_body": "Stddev(Body)",
+		// the request body bytes are not displayed by default
+		"min_req_body":    "Min(ReqBody)",
+		"max_req_body":    "Max(ReqBody)",
+		"sum_req_body":    "Sum(ReqBody)",
+		"avg_req_body":    "Avg(ReqBody)",
+		"stddev_req_body": "Stddev(ReqBody)",
+		// the derived values are not displayed by default
+		"4xx_rate": "4xx(%)",
+		"5xx_rate": "5xx(%)",
+		"rps":      "Req/s",
+		"apdex":    "Apdex",
+		// the statistics of the difference are printed only by diff
+		"change":       "Change(Avg)",
+		"ci":           "CI(Shift)",
+		"p_value":      "P-value",
+		"significance": "Significance",
 	}
 
 	for _, p := range percentiles {
 		key := fmt.Sprintf("p%d", p)
 		val := fmt.Sprintf("P%d", p)
 		headers[key] = val
+
+		headers[key+bodyPercentileSuffix] = fmt.Sprintf("%s(Body)", val)
+		headers[key+reqBodyPercentileSuffix] = fmt.Sprintf("%s(ReqBody)", val)
+	}
+
+	for _, key := range statusCodeKeywords(statusCodes) {
+		if key == "other" {
+			headers[key] = "Other"
+			continue
+		}
+		headers[key] = key
+	}
+
+	// the keys of group-by are displayed as they are
+	for _, key := range groupBy {
+		headers[key] = key
 	}
 
 	return headers
@@ -121,58 +234,97 @@ type PrintOptions struct {
 	showFooters     bool
 	decodeUri       bool
 	paginationLimit int
+	timeline        bool
 }
 
-func NewPrintOptions(noHeaders, showFooters, decodeUri bool, paginationLimit int) *PrintOptions {
+func NewPrintOptions(noHeaders, showFooters, decodeUri bool, paginationLimit int, timeline bool) *PrintOptions {
 	return &PrintOptions{
 		noHeaders:       noHeaders,
 		showFooters:     showFooters,
 		decodeUri:       decodeUri,
 		paginationLimit: paginationLimit,
+		timeline:        timeline,
 	}
 }
 
 type Printer struct {
-	keywords     []string
-	format       string
-	percentiles  []int
+	keywords    []string
+	format      string
+	percentiles []int
+	statusCodes []int
+	groupBy     []string
+	// groupIndex is the index of each key of groupBy
+	groupIndex   map[string]int
 	printOptions *PrintOptions
 	headers      []string
 	headersMap   map[string]string
 	writer       io.Writer
 	all          bool
+	// significanceLevel is the significance level of the statistical test of diff
+	significanceLevel float64
+	// histogram prints the histograms of the response times instead of the profile results if it is set
+	histogram *histogramOptions
 }
 
-func NewPrinter(w io.Writer, val, format string, percentiles []int, printOptions *PrintOptions) *Printer {
+func NewPrinter(w io.Writer, val, format string, percentiles, statusCodes []int, groupBy []string, printOptions *PrintOptions) *Printer {
+	groupIndex := make(map[string]int, len(groupBy))
+	for i, key := range groupBy {
+		groupIndex[key] = i
+	}
+
 	p := &Printer{
 		format:       format,
 		percentiles:  percentiles,
-		headersMap:   headersMap(percentiles),
+		statusCodes:  statusCodes,
+		groupBy:      groupBy,
+		groupIndex:   groupIndex,
+		headersMap:   headersMap(percentiles, statusCodes, groupBy),
 		writer:       w,
 		printOptions: printOptions,
+
+		significanceLevel: DefaultSignificanceLevel,
 	}
 
 	if val == "all" {
-		p.keywords = keywords(percentiles)
-		p.headers = defaultHeaders(percentiles)
+		p.keywords = keywords(percentiles, statusCodes, groupBy)
+		p.headers = defaultHeaders(percentiles, statusCodes, groupBy)
 		p.all = true
 	} else {
 		p.keywords = helpers.SplitCSV(val)
 		for _, key := range p.keywords {
 			p.headers = append(p.headers, p.headersMap[key])
 			if key == "all" {
-				p.keywords = keywords(percentiles)
-				p.headers = defaultHeaders(percentiles)
+				p.keywords = keywords(percentiles, statusCodes, groupBy)
+				p.headers = defaultHeaders(percentiles, statusCodes, groupBy)
 				p.all = true
 				break
 			}
 		}
 	}
 
+	if printOptions.timeline {
+		p.headersMap["time"] = "Time"
+		if !slices.Contains(p.keywords, "time") {
+			p.keywords = append([]string{"time"}, p.keywords...)
+			p.headers = append([]string{"Time"}, p.headers...)
+		}
+	}
+
 	return p
 }
 
 func (p *Printer) Validate() error {
+	if err := p.validateHistogram(); err != nil {
+		return err
+	}
+
+	builtins := headersMap(p.percentiles, p.statusCodes, nil)
+	for _, key := range p.groupBy {
+		if _, ok := builtins[key]; ok || slices.Contains(reservedKeywords, key) {
+			return fmt.Errorf("the group-by key conflicts with the output keyword: %s", key)
+		}
+	}
+
 	if p.all {
 		return nil
 	}
@@ -191,22 +343,44 @@ func (p *Printer) Validate() error {
 	return nil
 }
 
+// group returns the value of the group-by key of s
+func (p *Printer) group(s *HTTPStat, key string, quote bool) string {
+	val := s.Group(p.groupIndex[key])
+	if quote && strings.Contains(val, ",") {
+		return fmt.Sprintf(`"%s"`, val)
+	}
+
+	return val
+}
+
+// uri returns the URI of s, and marks it if s matches no route of the OpenAPI document
+func (p *Printer) uri(s *HTTPStat, quoteUri bool) string {
+	var mark string
+	if s.Undocumented {
+		mark = undocumentedMark
+	}
+
+	if quoteUri && strings.Contains(s.Uri, ",") {
+		return fmt.Sprintf(`"%s%s"`, s.Uri, mark)
+	}
+
+	return s.UriWithOptions(p.printOptions.decodeUri) + mark
+}
+
 func (p *Printer) GenerateLine(s *HTTPStat, quoteUri bool) []string {
 	keyLen := len(p.keywords)
 	line := make([]string, 0, keyLen)
 
 	for i := 0; i < keyLen; i++ {
 		switch p.keywords[i] {
+		case "time":
+			line = append(line, s.Time)
 		case "count":
 			line = append(line, s.StrCount())
 		case "method":
 			line = append(line, s.Method)
 		case "uri":
-			uri := s.UriWithOptions(p.printOptions.decodeUri)
-			if quoteUri && strings.Contains(s.Uri, ",") {
-				uri = fmt.Sprintf(`"%s"`, s.Uri)
-			}
-			line = append(line, uri)
+			line = append(line, p.uri(s, quoteUri))
 		case "1xx":
 			line = append(line, s.StrStatus1xx())
 		case "2xx":
@@ -217,6 +391,19 @@ func (p *Printer) GenerateLine(s *HTTPStat, quoteUri bool) []string {
 			line = append(line, s.StrStatus4xx())
 		case "5xx":
 			line = append(line, s.StrStatus5xx())
+		case "other":
+			line = append(line, s.StrStatusOther(p.statusCodes))
+		case "4xx_rate":
+			line = append(line, round(s.Status4xxRate()))
+		case "5xx_rate":
+			line = append(line, round(s.Status5xxRate()))
+		case "rps":
+			line = append(line, round(s.RequestsPerSecond()))
+		case "apdex":
+			line = append(line, round(s.Apdex()))
+		case "change", "ci", "p_value", "significance":
+			// there is nothing to compare with
+			line = append(line, "-")
 		case "min":
 			line = append(line, round(s.MinResponseTime()))
 		case "max":
@@ -235,13 +422,42 @@ func (p *Printer) GenerateLine(s *HTTPStat, quoteUri bool) []string {
 			line = append(line, round(s.SumResponseBodyBytes()))
 		case "avg_body":
 			line = append(line, round(s.AvgResponseBodyBytes()))
-		default: // percentile
-			var n int
-			_, err := fmt.Sscanf(p.keywords[i], "p%d", &n)
-			if err != nil {
+		case "stddev_body":
+			line = append(line, round(s.StddevResponseBodyBytes()))
+		case "min_req_body":
+			line = append(line, round(s.MinRequestBodyBytes()))
+		case "max_req_body":
+			line = append(line, round(s.MaxRequestBodyBytes()))
+		case "sum_req_body":
+			line = append(line, round(s.SumRequestBodyBytes()))
+		case "avg_req_body":
+			line = append(line, round(s.AvgRequestBodyBytes()))
+		case "stddev_req_body":
+			line = append(line, round(s.StddevRequestBodyBytes()))
+		default: // group-by key, status code or percentile
+			if _, ok := p.groupIndex[p.keywords[i]]; ok {
+				line = append(line, p.group(s, p.keywords[i], quoteUri))
+				continue
+			}
+
+			if code, err := strconv.Atoi(p.keywords[i]); err == nil {
+				line = append(line, s.StrStatusCode(code))
+				continue
+			}
+
+			n, suffix, ok := parsePercentileKeyword(p.keywords[i])
+			if !ok {
 				continue
 			}
-			line = append(line, round(s.PNResponseTime(n)))
+
+			switch suffix {
+			case "":
+				line = append(line, round(s.PNResponseTime(n)))
+			case bodyPercentileSuffix:
+				line = append(line, round(s.PNResponseBodyBytes(n)))
+			case reqBodyPercentileSuffix:
+				line = append(line, round(s.PNRequestBodyBytes(n)))
+			}
 		}
 	}
 
@@ -263,16 +479,14 @@ func (p *Printer) GenerateLineWithDiff(from, to *HTTPStat, quoteUri bool) []stri
 
 	for i := 0; i < keyLen; i++ {
 		switch p.keywords[i] {
+		case "time":
+			line = append(line, to.Time)
 		case "count":
 			line = append(line, formattedLineWithDiff(to.StrCount(), differ.DiffCnt()))
 		case "method":
 			line = append(line, to.Method)
 		case "uri":
-			uri := to.UriWithOptions(p.printOptions.decodeUri)
-			if quoteUri && strings.Contains(to.Uri, ",") {
-				uri = fmt.Sprintf(`"%s"`, to.Uri)
-			}
-			line = append(line, uri)
+			line = append(line, p.uri(to, quoteUri))
 		case "1xx":
 			line = append(line, formattedLineWithDiff(to.StrStatus1xx(), differ.DiffStatus1xx()))
 		case "2xx":
@@ -283,6 +497,24 @@ func (p *Printer) GenerateLineWithDiff(from, to *HTTPStat, quoteUri bool) []stri
 			line = append(line, formattedLineWithDiff(to.StrStatus4xx(), differ.DiffStatus4xx()))
 		case "5xx":
 			line = append(line, formattedLineWithDiff(to.StrStatus5xx(), differ.DiffStatus5xx()))
+		case "other":
+			line = append(line, formattedLineWithDiff(to.StrStatusOther(p.statusCodes), differ.DiffStatusOther(p.statusCodes)))
+		case "4xx_rate":
+			line = append(line, formattedLineWithDiff(round(to.Status4xxRate()), differ.DiffStatus4xxRate()))
+		case "5xx_rate":
+			line = append(line, formattedLineWithDiff(round(to.Status5xxRate()), differ.DiffStatus5xxRate()))
+		case "rps":
+			line = append(line, formattedLineWithDiff(round(to.RequestsPerSecond()), differ.DiffRequestsPerSecond()))
+		case "apdex":
+			line = append(line, formattedLineWithDiff(round(to.Apdex()), differ.DiffApdex()))
+		case "change":
+			line = append(line, differ.DiffChange())
+		case "ci":
+			line = append(line, differ.ConfidenceInterval(p.significanceLevel))
+		case "p_value":
+			line = append(line, differ.StrPValue())
+		case "significance":
+			line = append(line, differ.Significance(p.significanceLevel))
 		case "min":
 			line = append(line, formattedLineWithDiff(round(to.MinResponseTime()), differ.DiffMinResponseTime()))
 		case "max":
@@ -301,22 +533,70 @@ func (p *Printer) GenerateLineWithDiff(from, to *HTTPStat, quoteUri bool) []stri
 			line = append(line, formattedLineWithDiff(round(to.SumResponseBodyBytes()), differ.DiffSumResponseBodyBytes()))
 		case "avg_body":
 			line = append(line, formattedLineWithDiff(round(to.AvgResponseBodyBytes()), differ.DiffAvgResponseBodyBytes()))
-		default: // percentile
-			var n int
-			_, err := fmt.Sscanf(p.keywords[i], "p%d", &n)
-			if err != nil {
+		case "stddev_body":
+			line = append(line, formattedLineWithDiff(round(to.StddevResponseBodyBytes()), differ.DiffStddevResponseBodyBytes()))
+		case "min_req_body":
+			line = append(line, formattedLineWithDiff(round(to.MinRequestBodyBytes()), differ.DiffMinRequestBodyBytes()))
+		case "max_req_body":
+			line = append(line, formattedLineWithDiff(round(to.MaxRequestBodyBytes()), differ.DiffMaxRequestBodyBytes()))
+		case "sum_req_body":
+			line = append(line, formattedLineWithDiff(round(to.SumRequestBodyBytes()), differ.DiffSumRequestBodyBytes()))
+		case "avg_req_body":
+			line = append(line, formattedLineWithDiff(round(to.AvgRequestBodyBytes()), differ.DiffAvgRequestBodyBytes()))
+		case "stddev_req_body":
+			line = append(line, formattedLineWithDiff(round(to.StddevRequestBodyBytes()), differ.DiffStddevRequestBodyBytes()))
+		default: // group-by key, status code or percentile
+			if _, ok := p.groupIndex[p.keywords[i]]; ok {
+				line = append(line, p.group(to, p.keywords[i], quoteUri))
 				continue
 			}
-			line = append(line, formattedLineWithDiff(round(to.PNResponseTime(n)), differ.DiffPNResponseTime(n)))
+
+			if code, err := strconv.Atoi(p.keywords[i]); err == nil {
+				line = append(line, formattedLineWithDiff(to.StrStatusCode(code), differ.DiffStatusCode(code)))
+				continue
+			}
+
+			n, suffix, ok := parsePercentileKeyword(p.keywords[i])
+			if !ok {
+				continue
+			}
+
+			switch suffix {
+			case "":
+				line = append(line, formattedLineWithDiff(round(to.PNResponseTime(n)), differ.DiffPNResponseTime(n)))
+			case bodyPercentileSuffix:
+				line = append(line, formattedLineWithDiff(round(to.PNResponseBodyBytes(n)), differ.DiffPNResponseBodyBytes(n)))
+			case reqBodyPercentileSuffix:
+				line = append(line, formattedLineWithDiff(round(to.PNRequestBodyBytes(n)), differ.DiffPNRequestBodyBytes(n)))
+			}
 		}
 	}
 
 	return line
 }
 
+// countStatusOther returns the copy of counts that has the number of the other status codes
+func (p *Printer) countStatusOther(counts map[string]int) map[string]int {
+	c := make(map[string]int, len(counts)+1)
+	for key, cnt := range counts {
+		c[key] = cnt
+	}
+
+	codes := make(map[int]int, len(counts))
+	for key, cnt := range counts {
+		if code, err := strconv.Atoi(key); err == nil {
+			codes[code] = cnt
+		}
+	}
+	c["other"] = counts["count"] - sumStatusCodes(codes, p.statusCodes)
+
+	return c
+}
+
 func (p *Printer) GenerateFooter(counts map[string]int) []string {
 	keyLen := len(p.keywords)
 	line := make([]string, 0, keyLen)
+	counts = p.countStatusOther(counts)
 
 	for i := 0; i < keyLen; i++ {
 		switch p.keywords[i] {
@@ -332,7 +612,13 @@ func (p *Printer) GenerateFooter(counts map[string]int) []string {
 			line = append(line, fmt.Sprint(counts["4xx"]))
 		case "5xx":
 			line = append(line, fmt.Sprint(counts["5xx"]))
+		case "other":
+			line = append(line, fmt.Sprint(counts["other"]))
 		default:
+			if _, err := strconv.Atoi(p.keywords[i]); err == nil {
+				line = append(line, fmt.Sprint(counts[p.keywords[i]]))
+				continue
+			}
 			line = append(line, "")
 		}
 	}
@@ -343,6 +629,8 @@ func (p *Printer) GenerateFooter(counts map[string]int) []string {
 func (p *Printer) GenerateFooterWithDiff(countsFrom, countsTo map[string]int) []string {
 	keyLen := len(p.keywords)
 	line := make([]string, 0, keyLen)
+	countsFrom = p.countStatusOther(countsFrom)
+	countsTo = p.countStatusOther(countsTo)
 	counts := DiffCountAll(countsFrom, countsTo)
 
 	for i := 0; i < keyLen; i++ {
@@ -359,7 +647,17 @@ func (p *Printer) GenerateFooterWithDiff(countsFrom, countsTo map[string]int) []
 			line = append(line, formattedLineWithDiff(fmt.Sprint(countsTo["4xx"]), counts["4xx"]))
 		case "5xx":
 			line = append(line, formattedLineWithDiff(fmt.Sprint(countsTo["5xx"]), counts["5xx"]))
+		case "other":
+			line = append(line, formattedLineWithDiff(fmt.Sprint(countsTo["other"]), counts["other"]))
 		default:
+			if _, err := strconv.Atoi(p.keywords[i]); err == nil {
+				diff, ok := counts[p.keywords[i]]
+				if !ok {
+					diff = "+0"
+				}
+				line = append(line, formattedLineWithDiff(fmt.Sprint(countsTo[p.keywords[i]]), diff))
+				continue
+			}
 			line = append(line, "")
 		}
 	}
@@ -367,6 +665,47 @@ func (p *Printer) GenerateFooterWithDiff(countsFrom, countsTo map[string]int) []
 	return line
 }
 
+// UseRequestBodyBytesPercentile reports whether the percentiles or the stddev of the request body bytes are displayed
+func (p *Printer) UseRequestBodyBytesPercentile() bool {
+	return p.useBodyBytesPercentile("stddev_req_body", reqBodyPercentileSuffix)
+}
+
+// UseResponseBodyBytesPercentile reports whether the percentiles or the stddev of the response body bytes are displayed
+func (p *Printer) UseResponseBodyBytesPercentile() bool {
+	return p.useBodyBytesPercentile("stddev_body", bodyPercentileSuffix)
+}
+
+// UseRequestsPerSecond reports whether the requests per second are displayed
+func (p *Printer) UseRequestsPerSecond() bool {
+	return slices.Contains(p.keywords, "rps")
+}
+
+func (p *Printer) useBodyBytesPercentile(stddevKey, percentileSuffix string) bool {
+	for _, key := range p.keywords {
+		if key == stddevKey {
+			return true
+		}
+
+		if _, suffix, ok := parsePercentileKeyword(key); ok && suffix == percentileSuffix {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (p *Printer) Keywords() []string {
+	return p.keywords
+}
+
+func (p *Printer) Headers() []string {
+	return p.headers
+}
+
+func (p *Printer) SetSignificanceLevel(level float64) {
+	p.significanceLevel = level
+}
+
 func (p *Printer) SetFormat(format string) {
 	p.format = format
 }
@@ -380,6 +719,15 @@ func (p *Printer) SetWriter(w io.Writer) {
 }
 
 func (p *Printer) Print(hs, hsTo *HTTPStats) {
+	if p.histogram != nil {
+		p.printHistograms(hs)
+		return
+	}
+
+	if p.printOptions.timeline && hsTo == nil {
+		hs = hs.Timeline()
+	}
+
 	switch p.format {
 	case "table":
 		p.printTable(hs, hsTo)
@@ -401,12 +749,7 @@ func round(num float64) string {
 }
 
 func findHTTPStatFrom(hsFrom *HTTPStats, hsTo *HTTPStat) *HTTPStat {
-	for _, sFrom := range hsFrom.stats {
-		if sFrom.Uri == hsTo.Uri && sFrom.Method == hsTo.Method {
-			return sFrom
-		}
-	}
-	return nil
+	return hsFrom.Find(hsTo)
 }
 
 func (p *Printer) printTable(hsFrom, hsTo *HTTPStats) {
@@ -534,28 +877,6 @@ func (p *Printer) printCSV(hsFrom, hsTo *HTTPStats) {
 	}
 }
 
-func (p *Printer) printHTML(hsFrom, hsTo *HTTPStats) {
-	var data [][]string
-
-	if hsTo == nil {
-		for _, s := range hsFrom.stats {
-			data = append(data, p.GenerateLine(s, true))
-		}
-	} else {
-		for _, to := range hsTo.stats {
-			from := findHTTPStatFrom(hsFrom, to)
-
-			if from == nil {
-				data = append(data, p.GenerateLine(to, false))
-			} else {
-				data = append(data, p.GenerateLineWithDiff(from, to, false))
-			}
-		}
-	}
-	content, _ := html.RenderTableWithGridJS("alp", p.headers, data, p.printOptions.paginationLimit)
-	fmt.Println(content)
-}
-
 func (p *Printer) printJSON(hsFrom, hsTo *HTTPStats) {
 	var data [][]string
 
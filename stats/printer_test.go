@@ -0,0 +1,51 @@
+package stats
+
+import (
+	"io"
+	"testing"
+)
+
+func TestPrinterValidateGroupBy(t *testing.T) {
+	tests := []struct {
+		name    string
+		groupBy []string
+		wantErr bool
+	}{
+		{
+			name:    "log key",
+			groupBy: []string{"host", "upstream_addr"},
+		},
+		{
+			name:    "output keyword",
+			groupBy: []string{"host", "uri"},
+			wantErr: true,
+		},
+		{
+			name:    "percentile",
+			groupBy: []string{"p99"},
+			wantErr: true,
+		},
+		{
+			name:    "time of the timeline",
+			groupBy: []string{"time"},
+			wantErr: true,
+		},
+		{
+			name:    "other status codes",
+			groupBy: []string{"other"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			printer := NewPrinter(io.Discard, "all", "table", []int{90, 95, 99}, nil, tt.groupBy, NewPrintOptions(false, false, false, 0, false))
+			err := printer.Validate()
+			if tt.wantErr && err == nil {
+				t.Errorf("want the error of %v", tt.groupBy)
+			} else if !tt.wantErr && err != nil {
+				t.Errorf("want no error, got: %v", err)
+			}
+		})
+	}
+}
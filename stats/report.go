@@ -0,0 +1,263 @@
+package stats
+
+import (
+	"fmt"
+	htmltemplate "html/template"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/tkuchiki/alp/html"
+)
+
+// maxReportCharts is the number of the endpoints that have the charts in the HTML report, to keep the report small
+const maxReportCharts = 50
+
+// reportHistogramBuckets is the number of the buckets of the histograms of the response times in the HTML report
+const reportHistogramBuckets = 20
+
+// maxReportPoints is the maximum number of the points of the requests over time
+const maxReportPoints = 120
+
+// reportIntervals are the candidates of the interval of the requests over time
+var reportIntervals = []time.Duration{
+	time.Second,
+	5 * time.Second,
+	10 * time.Second,
+	30 * time.Second,
+	time.Minute,
+	5 * time.Minute,
+	10 * time.Minute,
+	30 * time.Minute,
+	time.Hour,
+	3 * time.Hour,
+	6 * time.Hour,
+	12 * time.Hour,
+	24 * time.Hour,
+}
+
+// UseRequestsOverTime returns whether the requests in each second are counted for the HTML report
+func (p *Printer) UseRequestsOverTime() bool {
+	return p.format == "html"
+}
+
+// reportValue returns the value of the keyword that is compared to find the regressions
+func reportValue(s *HTTPStat, keyword string) (float64, bool) {
+	switch keyword {
+	case "min":
+		return s.MinResponseTime(), true
+	case "max":
+		return s.MaxResponseTime(), true
+	case "avg":
+		return s.AvgResponseTime(), true
+	case "stddev":
+		return s.StddevResponseTime(), true
+	case "4xx_rate":
+		return s.Status4xxRate(), true
+	case "5xx_rate":
+		return s.Status5xxRate(), true
+	case "apdex":
+		// the larger Apdex score is better unlike the others
+		return -s.Apdex(), true
+	}
+
+	n, suffix, ok := parsePercentileKeyword(keyword)
+	if ok && suffix == "" {
+		return s.PNResponseTime(n), true
+	}
+
+	return 0, false
+}
+
+// diffClasses returns the CSS classes of the cells of the diff.
+// The increases of the response times and the error rates are the regressions, and the decreases are the improvements.
+// The columns of the statistical significance are colored only if the change is significant.
+func (p *Printer) diffClasses(from, to *HTTPStat) []string {
+	classes := make([]string, len(p.keywords))
+	differ := NewDiffer(from, to)
+
+	for i, keyword := range p.keywords {
+		var delta float64
+		switch keyword {
+		case "change":
+			delta = differ.Change()
+		case "ci", "p_value", "significance":
+			if !differ.Significant(p.significanceLevel) {
+				continue
+			}
+			delta, _, _, _ = differ.Shift(p.significanceLevel)
+		default:
+			fromValue, ok := reportValue(from, keyword)
+			if !ok {
+				continue
+			}
+			toValue, _ := reportValue(to, keyword)
+			delta = toValue - fromValue
+		}
+
+		// the differences are printed in 3 decimal places
+		if delta >= 0.0005 {
+			classes[i] = html.ClassRegression
+		} else if delta <= -0.0005 {
+			classes[i] = html.ClassImprovement
+		}
+	}
+
+	return classes
+}
+
+func reportLabel(s *HTTPStat) string {
+	label := fmt.Sprintf("%s %s", s.Method, s.Uri)
+	if len(s.Groups) > 0 {
+		label = fmt.Sprintf("%s [%s]", label, strings.Join(s.Groups, ", "))
+	}
+
+	return label
+}
+
+// formatInterval formats the interval without the zero units, e.g. 30s, 5m and 1h
+func formatInterval(d time.Duration) string {
+	switch {
+	case d%time.Hour == 0:
+		return fmt.Sprintf("%dh", d/time.Hour)
+	case d%time.Minute == 0:
+		return fmt.Sprintf("%dm", d/time.Minute)
+	}
+
+	return fmt.Sprintf("%ds", d/time.Second)
+}
+
+// requestsOverTime returns the chart of the requests counted in the interval that is at most maxReportPoints points
+func requestsOverTime(ls *LogSummary) *html.Section {
+	section := &html.Section{
+		Title: "Requests over time",
+	}
+
+	if len(ls.requests) == 0 {
+		section.Note = "No requests have the time that can be parsed, or the results are loaded from the dump."
+		return section
+	}
+
+	first, last := int64(math.MaxInt64), int64(math.MinInt64)
+	for sec := range ls.requests {
+		first = min(first, sec)
+		last = max(last, sec)
+	}
+
+	// the logs over a long period are counted in the days
+	days := (last-first)/int64(maxReportPoints*24*time.Hour/time.Second) + 1
+	interval := time.Duration(days) * 24 * time.Hour
+	for _, d := range reportIntervals {
+		if (last-first)/int64(d/time.Second) < maxReportPoints {
+			interval = d
+			break
+		}
+	}
+
+	step := int64(interval / time.Second)
+	start := first - first%step
+	n := (last-start)/step + 1
+
+	counts := make([]float64, n)
+	for sec, cnt := range ls.requests {
+		counts[(sec-start)/step] += float64(cnt)
+	}
+
+	labels := make([]string, n)
+	for i := range labels {
+		labels[i] = time.Unix(start+int64(i)*step, 0).In(ls.FirstTime.Location()).Format("2006-01-02 15:04:05")
+	}
+
+	section.Charts = []htmltemplate.HTML{
+		html.LineChart(fmt.Sprintf("Requests per %s", formatInterval(interval)), labels, counts),
+	}
+
+	return section
+}
+
+// reportHistogramBins divides the response times into the bins of the histogram in the same way as --histogram
+func reportHistogramBins(samples []float64) []html.HistogramBin {
+	buckets := NewHistogramBuckets(samples, reportHistogramBuckets, HistogramScaleLinear)
+	bins := make([]html.HistogramBin, 0, len(buckets))
+	for _, b := range buckets {
+		bins = append(bins, html.HistogramBin{Min: b.Min, Max: b.Max, Count: b.Count})
+	}
+
+	return bins
+}
+
+// reportSections returns the charts of the endpoints of hs, in the order of the table
+func reportSections(hs *HTTPStats) []*html.Section {
+	stats := hs.stats
+	var note string
+	if len(stats) > maxReportCharts {
+		stats = stats[:maxReportCharts]
+		note = fmt.Sprintf("The first %d of %d endpoints are shown.", maxReportCharts, len(hs.stats))
+	}
+
+	labels := make([]string, 0, len(stats))
+	counts := make([][]int, 0, len(stats))
+	histograms := make([]htmltemplate.HTML, 0, len(stats))
+	for _, s := range stats {
+		labels = append(labels, reportLabel(s))
+		counts = append(counts, []int{s.Status1xx, s.Status2xx, s.Status3xx, s.Status4xx, s.Status5xx})
+
+		if samples := s.ResponseTimeSamples(); samples != nil {
+			histograms = append(histograms, html.Histogram(reportLabel(s), reportHistogramBins(samples)))
+		}
+	}
+
+	status := &html.Section{
+		Title: "Status classes",
+		Note:  note,
+		Charts: []htmltemplate.HTML{
+			html.StackedBars("Responses by status class", labels,
+				[]string{"1xx", "2xx", "3xx", "4xx", "5xx"},
+				[]string{"status-1xx", "status-2xx", "status-3xx", "status-4xx", "status-5xx"},
+				counts),
+		},
+	}
+
+	latency := &html.Section{
+		Title:  "Response time histograms",
+		Note:   note,
+		Charts: histograms,
+	}
+	if len(histograms) < len(stats) {
+		latency.Note = strings.TrimSpace(note + " The response times estimated by the sketch are not shown.")
+	}
+
+	return []*html.Section{requestsOverTime(hs.logSummary), status, latency}
+}
+
+func (p *Printer) printHTML(hsFrom, hsTo *HTTPStats) {
+	report := &html.Report{
+		Title:           "alp",
+		Columns:         p.headers,
+		PaginationLimit: p.printOptions.paginationLimit,
+	}
+
+	if hsTo == nil {
+		for _, s := range hsFrom.stats {
+			report.Rows = append(report.Rows, p.GenerateLine(s, true))
+		}
+		report.Sections = reportSections(hsFrom)
+	} else {
+		report.Title = "alp diff"
+		for _, to := range hsTo.stats {
+			from := findHTTPStatFrom(hsFrom, to)
+
+			if from == nil {
+				report.Rows = append(report.Rows, p.GenerateLine(to, false))
+				report.Classes = append(report.Classes, nil)
+			} else {
+				report.Rows = append(report.Rows, p.GenerateLineWithDiff(from, to, false))
+				report.Classes = append(report.Classes, p.diffClasses(from, to))
+			}
+		}
+		report.Sections = reportSections(hsTo)
+	}
+
+	content, _ := report.Render()
+	fmt.Println(content)
+}
@@ -0,0 +1,107 @@
+package stats
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/tkuchiki/alp/html"
+	"github.com/tkuchiki/alp/options"
+)
+
+func TestRequestsOverTime(t *testing.T) {
+	hs := NewHTTPStats(true, false, false)
+	if err := hs.InitFilter(options.NewOptions(options.Location("UTC"))); err != nil {
+		t.Fatal(err)
+	}
+	hs.EnableRequestsOverTime()
+
+	// an hour is too long for the intervals up to 30s, and the empty time is not counted
+	for _, tm := range []string{"2015-09-06T05:58:05Z", "2015-09-06T05:58:20Z", "2015-09-06T07:00:01Z", ""} {
+		if err := hs.SetWithTime("/foo", "GET", tm, nil, 200, 0.1, 0, 0); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	section := requestsOverTime(hs.LogSummary())
+	if section.Note != "" || len(section.Charts) != 1 {
+		t.Fatalf("want a chart, got: %+v", section)
+	}
+
+	chart := string(section.Charts[0])
+	for _, want := range []string{
+		"Requests per 1m",
+		"<title>2015-09-06 05:58:00: 2</title>",
+		"<title>2015-09-06 07:00:00: 1</title>",
+	} {
+		if !strings.Contains(chart, want) {
+			t.Errorf("want: %s, got:\n%s", want, chart)
+		}
+	}
+
+	if section := requestsOverTime(&LogSummary{}); section.Note == "" || len(section.Charts) != 0 {
+		t.Errorf("want the note instead of the chart, got: %+v", section)
+	}
+}
+
+func TestPrinterDiffClasses(t *testing.T) {
+	from := NewHTTPStats(true, false, false)
+	to := NewHTTPStats(true, false, false)
+
+	for i := 0; i < 20; i++ {
+		from.Set("/foo", "GET", 200, 0.1, 0, 0)
+		to.Set("/foo", "GET", 200, 0.2, 0, 0)
+		from.Set("/bar", "GET", 500, 0.3, 0, 0)
+		to.Set("/bar", "GET", 200, 0.3, 0, 0)
+	}
+
+	printer := NewPrinter(nil, "count,uri,avg,5xx_rate,change,significance", "html", nil, nil, nil, NewPrintOptions(false, false, false, 0, false))
+	if err := printer.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	printer.SetSignificanceLevel(DefaultSignificanceLevel)
+
+	tests := []struct {
+		uri  string
+		want []string
+	}{
+		{
+			uri:  "/foo",
+			want: []string{"", "", html.ClassRegression, "", html.ClassRegression, html.ClassRegression},
+		},
+		{
+			uri:  "/bar",
+			want: []string{"", "", "", html.ClassImprovement, "", ""},
+		},
+	}
+
+	for _, tt := range tests {
+		s := &HTTPStat{Uri: tt.uri, Method: "GET"}
+		got := printer.diffClasses(from.Find(s), to.Find(s))
+		if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+			t.Errorf("%s want: %v, got: %v", tt.uri, tt.want, got)
+		}
+	}
+}
+
+func TestReportHistogramBins(t *testing.T) {
+	samples := []float64{0.1, 0.2, 0.2, 0.3, 1.1}
+
+	// the report bins the response times in the same way as --histogram
+	bins := reportHistogramBins(samples)
+	buckets := NewHistogramBuckets(samples, reportHistogramBuckets, HistogramScaleLinear)
+	if len(bins) != len(buckets) {
+		t.Fatalf("bins want: %d, got: %d", len(buckets), len(bins))
+	}
+
+	for i, b := range buckets {
+		want := html.HistogramBin{Min: b.Min, Max: b.Max, Count: b.Count}
+		if bins[i] != want {
+			t.Errorf("bin %d want: %+v, got: %+v", i, want, bins[i])
+		}
+	}
+
+	chart := string(html.Histogram("GET /foo", bins))
+	if !strings.Contains(chart, "<title>1.050 - 1.100: 1</title>") {
+		t.Errorf("want the last bin that includes the maximum, got: %s", chart)
+	}
+}
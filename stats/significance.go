@@ -0,0 +1,199 @@
+package stats
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+)
+
+const DefaultSignificanceLevel = 0.05
+
+// mannWhitneyU returns the two-sided p-value of the Mann-Whitney U test of x and y.
+// The p-value is approximated by the normal distribution with the tie and the continuity corrections,
+// and is NaN if x or y is empty.
+func mannWhitneyU(x, y []float64) float64 {
+	n1 := float64(len(x))
+	n2 := float64(len(y))
+	if n1 == 0 || n2 == 0 {
+		return math.NaN()
+	}
+
+	type sample struct {
+		val  float64
+		from bool
+	}
+
+	samples := make([]sample, 0, len(x)+len(y))
+	for _, v := range x {
+		samples = append(samples, sample{val: v, from: true})
+	}
+	for _, v := range y {
+		samples = append(samples, sample{val: v})
+	}
+
+	sort.Slice(samples, func(i, j int) bool {
+		return samples[i].val < samples[j].val
+	})
+
+	// the tied values have the average of their ranks
+	var rankSum, tieSum float64
+	for i := 0; i < len(samples); {
+		j := i
+		for j < len(samples) && samples[j].val == samples[i].val {
+			j++
+		}
+
+		rank := float64(i+j+1) / 2
+		for k := i; k < j; k++ {
+			if samples[k].from {
+				rankSum += rank
+			}
+		}
+
+		t := float64(j - i)
+		tieSum += t*t*t - t
+		i = j
+	}
+
+	n := n1 + n2
+	u := rankSum - n1*(n1+1)/2
+	mean := n1 * n2 / 2
+	variance := n1 * n2 / 12 * ((n + 1) - tieSum/(n*(n-1)))
+	if variance <= 0 {
+		// every value is the same
+		return 1
+	}
+
+	z := math.Max(math.Abs(u-mean)-0.5, 0) / math.Sqrt(variance)
+
+	return math.Erfc(z / math.Sqrt2)
+}
+
+// shiftCI returns the Hodges-Lehmann estimate of the shift of y from x, which is the median of the differences y[j] - x[i],
+// and its confidence interval at the confidence level.
+// The interval is inverted from the Mann-Whitney U test with the same normal approximation as mannWhitneyU,
+// so that it excludes 0 when the test is significant at the significance level of 1 - the confidence level.
+// ok is false if x or y is empty, or if they are too small for the confidence level.
+func shiftCI(x, y []float64, level float64) (shift, lower, upper float64, ok bool) {
+	n1 := float64(len(x))
+	n2 := float64(len(y))
+	if n1 == 0 || n2 == 0 {
+		return 0, 0, 0, false
+	}
+
+	pooled := make([]float64, 0, len(x)+len(y))
+	pooled = append(append(pooled, x...), y...)
+	n := n1 + n2
+	mean := n1 * n2 / 2
+	variance := n1 * n2 / 12 * ((n + 1) - tieSum(pooled)/(n*(n-1)))
+
+	// the shifts that the test does not reject are between the k-th smallest and the k-th largest differences
+	z := math.Sqrt2 * math.Erfinv(level)
+	k := int(math.Floor(mean-z*math.Sqrt(variance)-0.5)) + 1
+	if k < 1 {
+		return 0, 0, 0, false
+	}
+
+	sx := append([]float64{}, x...)
+	sy := append([]float64{}, y...)
+	sort.Float64s(sx)
+	sort.Float64s(sy)
+
+	total := len(x) * len(y)
+	shift = kthDiff(sx, sy, (total+1)/2)
+	if total%2 == 0 {
+		shift = (shift + kthDiff(sx, sy, total/2+1)) / 2
+	}
+
+	return shift, kthDiff(sx, sy, k), kthDiff(sx, sy, total+1-k), true
+}
+
+// tieSum returns the sum of t^3 - t of the tied values of vals, where t is the number of the values tied with each other
+func tieSum(vals []float64) float64 {
+	sorted := append([]float64{}, vals...)
+	sort.Float64s(sorted)
+
+	var sum float64
+	for i := 0; i < len(sorted); {
+		j := i
+		for j < len(sorted) && sorted[j] == sorted[i] {
+			j++
+		}
+
+		t := float64(j - i)
+		sum += t*t*t - t
+		i = j
+	}
+
+	return sum
+}
+
+// kthDiff returns the k-th smallest (1-indexed) of the differences y[j] - x[i] of the sorted x and y,
+// without holding every difference, because there are len(x) * len(y) differences.
+// The candidates are narrowed by the random pivot in each row of x, as the quickselect does.
+func kthDiff(x, y []float64, k int) float64 {
+	rnd := rand.New(rand.NewSource(1))
+
+	// the candidates of the i-th row are y[lo[i]:hi[i]]
+	lo := make([]int, len(x))
+	hi := make([]int, len(x))
+	for i := range hi {
+		hi[i] = len(y)
+	}
+
+	less := make([]int, len(x))
+	lessOrEqual := make([]int, len(x))
+	for {
+		candidates := 0
+		for i := range x {
+			candidates += hi[i] - lo[i]
+		}
+
+		r := rnd.Intn(candidates)
+		var pivot float64
+		for i := range x {
+			if r < hi[i]-lo[i] {
+				pivot = y[lo[i]+r] - x[i]
+				break
+			}
+			r -= hi[i] - lo[i]
+		}
+
+		// the differences of each row decrease as x[i] increases, so the counts of the rows increase
+		nl, nle := 0, 0
+		jl, jle := 0, 0
+		for i := range x {
+			for jl < len(y) && y[jl]-x[i] < pivot {
+				jl++
+			}
+			for jle < len(y) && y[jle]-x[i] <= pivot {
+				jle++
+			}
+			less[i], lessOrEqual[i] = jl, jle
+			nl += jl
+			nle += jle
+		}
+
+		switch {
+		case k <= nl:
+			for i := range x {
+				hi[i] = min(hi[i], less[i])
+			}
+		case k <= nle:
+			return pivot
+		default:
+			for i := range x {
+				lo[i] = max(lo[i], lessOrEqual[i])
+			}
+		}
+	}
+}
+
+// relativeChange returns the change from from to to in percent, and 0 if from is 0
+func relativeChange(from, to float64) float64 {
+	if from == 0 {
+		return 0
+	}
+
+	return (to - from) / from * 100
+}
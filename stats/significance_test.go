@@ -0,0 +1,147 @@
+package stats
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+func TestMannWhitneyU(t *testing.T) {
+	tests := []struct {
+		x, y []float64
+		want string
+	}{
+		{[]float64{1, 2, 3, 4, 5}, []float64{6, 7, 8, 9, 10}, "0.0122"},
+		{[]float64{1, 2, 3, 4, 5}, []float64{1, 2, 3, 4, 5}, "1.0000"},
+		{[]float64{1, 1, 1}, []float64{1, 1}, "1.0000"},
+		{[]float64{1, 3, 5, 7}, []float64{2, 4, 6, 8}, "0.6650"},
+	}
+
+	for _, tt := range tests {
+		if got := fmt.Sprintf("%.4f", mannWhitneyU(tt.x, tt.y)); got != tt.want {
+			t.Errorf("%v, %v: want %s, got %s", tt.x, tt.y, tt.want, got)
+		}
+	}
+
+	if got := mannWhitneyU(nil, []float64{1}); !math.IsNaN(got) {
+		t.Errorf("want NaN, got %v", got)
+	}
+}
+
+func TestShiftCI(t *testing.T) {
+	tests := []struct {
+		x, y []float64
+		want string
+	}{
+		{[]float64{1, 2, 3, 4, 5}, []float64{6, 7, 8, 9, 10}, "5.000 [2.000, 8.000]"},
+		{[]float64{1, 3, 5, 7}, []float64{2, 4, 6, 8}, "1.000 [-5.000, 7.000]"},
+		{[]float64{0.1, 0.1, 0.2, 0.3, 0.3, 0.4}, []float64{0.1, 0.2, 0.2, 0.3, 0.5, 0.6}, "0.100 [-0.100, 0.300]"},
+	}
+
+	for _, tt := range tests {
+		shift, lower, upper, ok := shiftCI(tt.x, tt.y, 0.95)
+		if !ok {
+			t.Fatalf("%v, %v: want ok", tt.x, tt.y)
+		}
+
+		if got := fmt.Sprintf("%.3f [%.3f, %.3f]", shift, lower, upper); got != tt.want {
+			t.Errorf("%v, %v: want %s, got %s", tt.x, tt.y, tt.want, got)
+		}
+	}
+
+	// the samples are too small for the confidence level
+	if _, _, _, ok := shiftCI([]float64{1, 2}, []float64{3, 4}, 0.95); ok {
+		t.Error("want not ok")
+	}
+
+	if _, _, _, ok := shiftCI(nil, []float64{1, 2}, 0.95); ok {
+		t.Error("want not ok")
+	}
+}
+
+func TestKthDiff(t *testing.T) {
+	rnd := rand.New(rand.NewSource(2))
+	for n := 0; n < 20; n++ {
+		x := make([]float64, 1+rnd.Intn(30))
+		y := make([]float64, 1+rnd.Intn(30))
+		// the rounded values have the ties
+		for i := range x {
+			x[i] = math.Round(rnd.Float64()*100) / 10
+		}
+		for i := range y {
+			y[i] = math.Round(rnd.Float64()*100) / 10
+		}
+		sort.Float64s(x)
+		sort.Float64s(y)
+
+		diffs := make([]float64, 0, len(x)*len(y))
+		for _, xi := range x {
+			for _, yj := range y {
+				diffs = append(diffs, yj-xi)
+			}
+		}
+		sort.Float64s(diffs)
+
+		for k := 1; k <= len(diffs); k++ {
+			if got := kthDiff(x, y, k); got != diffs[k-1] {
+				t.Fatalf("%v, %v: want the %d-th difference %v, got %v", x, y, k, diffs[k-1], got)
+			}
+		}
+	}
+}
+
+// TestShiftCIConsistency tests that the confidence interval excludes 0 when the test is significant
+func TestShiftCIConsistency(t *testing.T) {
+	rnd := rand.New(rand.NewSource(3))
+	for n := 0; n < 200; n++ {
+		x := make([]float64, 5+rnd.Intn(50))
+		y := make([]float64, 5+rnd.Intn(50))
+		shift := rnd.Float64() * 0.5
+		for i := range x {
+			x[i] = rnd.ExpFloat64()
+		}
+		for i := range y {
+			y[i] = rnd.ExpFloat64() + shift
+		}
+
+		_, lower, upper, ok := shiftCI(x, y, 0.95)
+		if !ok {
+			t.Fatalf("%d, %d: want ok", len(x), len(y))
+		}
+
+		significant := mannWhitneyU(x, y) < 0.05
+		if excluded := lower > 0 || upper < 0; excluded != significant {
+			t.Errorf("want the interval [%v, %v] to exclude 0: %v, got: %v", lower, upper, significant, excluded)
+		}
+	}
+}
+
+func TestHTTPStatsSortChange(t *testing.T) {
+	from := NewHTTPStats(true, false, false)
+	to := NewHTTPStats(true, false, false)
+	for _, s := range []struct {
+		uri      string
+		from, to float64
+	}{
+		{"/slower", 0.1, 0.2},
+		{"/faster", 0.2, 0.1},
+		{"/same", 0.1, 0.1},
+	} {
+		from.Set(s.uri, "GET", 200, s.from, 0, 0)
+		to.Set(s.uri, "GET", 200, s.to, 0, 0)
+	}
+
+	to.SetDiffFrom(from)
+	to.SortChange(true)
+
+	var got []string
+	for _, s := range to.Stats() {
+		got = append(got, s.Uri)
+	}
+
+	if fmt.Sprint(got) != "[/slower /same /faster]" {
+		t.Errorf("want [/slower /same /faster], got %v", got)
+	}
+}
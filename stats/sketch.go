@@ -0,0 +1,226 @@
+package stats
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+const (
+	PercentileEstimatorExact  = "exact"
+	PercentileEstimatorSketch = "sketch"
+
+	DefaultSketchRelativeAccuracy = 0.01
+	DefaultSketchMaxBins          = 2048
+
+	// values smaller than this are counted in the zero bucket
+	sketchMinIndexableValue = 1e-9
+)
+
+// percentileEstimator holds how responseTime and bodyBytes estimate percentiles.
+type percentileEstimator struct {
+	name             string
+	relativeAccuracy float64
+	maxBins          int
+}
+
+func newPercentileEstimator(name string, relativeAccuracy float64) (*percentileEstimator, error) {
+	if name == "" {
+		name = PercentileEstimatorExact
+	}
+
+	if name != PercentileEstimatorExact && name != PercentileEstimatorSketch {
+		return nil, fmt.Errorf("enum value must be one of exact,sketch, got '%s'", name)
+	}
+
+	if relativeAccuracy == 0 {
+		relativeAccuracy = DefaultSketchRelativeAccuracy
+	}
+
+	if relativeAccuracy <= 0 || relativeAccuracy >= 1 {
+		return nil, fmt.Errorf("percentile accuracy must be greater than 0 and less than 1, got %v", relativeAccuracy)
+	}
+
+	return &percentileEstimator{
+		name:             name,
+		relativeAccuracy: relativeAccuracy,
+		maxBins:          DefaultSketchMaxBins,
+	}, nil
+}
+
+func (pe *percentileEstimator) newSketch() *sketch {
+	if pe == nil || pe.name != PercentileEstimatorSketch {
+		return nil
+	}
+
+	return newSketch(pe.relativeAccuracy, pe.maxBins)
+}
+
+// sketch is a log-bucketed quantile sketch (DDSketch).
+//
+// A value v is counted in the bin i = ceil(log_gamma(v)), gamma = (1+a)/(1-a),
+// and is estimated by 2*gamma^i/(gamma+1). Every estimated quantile is within
+// the relative error a of the exact value, as long as the bin has not been
+// collapsed. Memory is bounded by MaxBins; when exceeded, the lowest bins are
+// merged, so only the lowest quantiles lose accuracy.
+type sketch struct {
+	RelativeAccuracy float64     `yaml:"relative_accuracy"`
+	MaxBins          int         `yaml:"max_bins"`
+	Count            int         `yaml:"count"`
+	ZeroCount        int         `yaml:"zero_count"`
+	Bins             map[int]int `yaml:"bins"`
+	gamma            float64
+	logGamma         float64
+	keys             []int
+}
+
+func newSketch(relativeAccuracy float64, maxBins int) *sketch {
+	return &sketch{
+		RelativeAccuracy: relativeAccuracy,
+		MaxBins:          maxBins,
+		Bins:             make(map[int]int),
+	}
+}
+
+func (s *sketch) init() {
+	if s.logGamma != 0 {
+		return
+	}
+
+	if s.Bins == nil {
+		s.Bins = make(map[int]int)
+	}
+
+	s.gamma = (1 + s.RelativeAccuracy) / (1 - s.RelativeAccuracy)
+	s.logGamma = math.Log(s.gamma)
+}
+
+func (s *sketch) index(val float64) int {
+	return int(math.Ceil(math.Log(val) / s.logGamma))
+}
+
+func (s *sketch) value(idx int) float64 {
+	return 2 * math.Pow(s.gamma, float64(idx)) / (s.gamma + 1)
+}
+
+func (s *sketch) Add(val float64) {
+	s.init()
+	s.Count++
+	s.keys = nil
+
+	if val < sketchMinIndexableValue {
+		s.ZeroCount++
+		return
+	}
+
+	s.Bins[s.index(val)]++
+
+	if s.MaxBins > 0 && len(s.Bins) > s.MaxBins {
+		s.collapse()
+	}
+}
+
+// Merge adds all counts of other into s. Both sketches must use the same relative accuracy.
+func (s *sketch) Merge(other *sketch) error {
+	if other == nil {
+		return nil
+	}
+
+	if s.RelativeAccuracy != other.RelativeAccuracy {
+		return fmt.Errorf("cannot merge sketches with different relative accuracy (%v, %v)", s.RelativeAccuracy, other.RelativeAccuracy)
+	}
+
+	s.init()
+	s.keys = nil
+	s.Count += other.Count
+	s.ZeroCount += other.ZeroCount
+	for idx, cnt := range other.Bins {
+		s.Bins[idx] += cnt
+	}
+
+	for s.MaxBins > 0 && len(s.Bins) > s.MaxBins {
+		s.collapse()
+	}
+
+	return nil
+}
+
+// collapse merges the two lowest bins
+func (s *sketch) collapse() {
+	keys := s.sortedKeys()
+	if len(keys) < 2 {
+		return
+	}
+
+	s.Bins[keys[1]] += s.Bins[keys[0]]
+	delete(s.Bins, keys[0])
+	s.keys = nil
+}
+
+func (s *sketch) sortedKeys() []int {
+	if s.keys != nil {
+		return s.keys
+	}
+
+	keys := make([]int, 0, len(s.Bins))
+	for k := range s.Bins {
+		keys = append(keys, k)
+	}
+	sort.Ints(keys)
+	s.keys = keys
+
+	return keys
+}
+
+// Quantile returns the estimated value whose rank (0-origin) is rank
+func (s *sketch) Quantile(rank int) float64 {
+	s.init()
+
+	if s.Count == 0 {
+		return 0.0
+	}
+
+	if rank < s.ZeroCount {
+		return 0.0
+	}
+
+	cum := s.ZeroCount
+	keys := s.sortedKeys()
+	for _, k := range keys {
+		cum += s.Bins[k]
+		if cum > rank {
+			return s.value(k)
+		}
+	}
+
+	return s.value(keys[len(keys)-1])
+}
+
+// Stddev is calculated from the representative value of each bin
+func (s *sketch) Stddev(avg, min, max float64) float64 {
+	s.init()
+
+	if s.Count == 0 {
+		return 0.0
+	}
+
+	stdd := float64(s.ZeroCount) * avg * avg
+	for k, cnt := range s.Bins {
+		v := clamp(s.value(k), min, max)
+		stdd += float64(cnt) * (v - avg) * (v - avg)
+	}
+
+	return math.Sqrt(stdd / float64(s.Count))
+}
+
+func clamp(val, min, max float64) float64 {
+	if val < min {
+		return min
+	}
+
+	if val > max {
+		return max
+	}
+
+	return val
+}
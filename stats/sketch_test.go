@@ -0,0 +1,81 @@
+package stats
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+func TestSketchQuantile(t *testing.T) {
+	accuracy := 0.01
+	s := newSketch(accuracy, DefaultSketchMaxBins)
+
+	r := rand.New(rand.NewSource(1))
+	n := 10000
+	values := make([]float64, 0, n)
+	for i := 0; i < n; i++ {
+		v := r.ExpFloat64() * 0.1
+		values = append(values, v)
+		s.Add(v)
+	}
+	sort.Float64s(values)
+
+	for _, p := range []int{1, 50, 90, 95, 99, 100} {
+		p := p
+		t.Run(fmt.Sprintf("p%d", p), func(t *testing.T) {
+			rank := percentRank(n, p)
+			want := values[rank]
+			got := s.Quantile(rank)
+			if math.Abs(got-want) > want*accuracy {
+				t.Errorf("want: %f (±%.0f%%), got: %f", want, accuracy*100, got)
+			}
+		})
+	}
+}
+
+func TestSketchMaxBins(t *testing.T) {
+	s := newSketch(0.01, 10)
+	for i := 1; i <= 1000; i++ {
+		s.Add(float64(i))
+	}
+
+	if len(s.Bins) > 10 {
+		t.Errorf("bins want: <= 10, got: %d", len(s.Bins))
+	}
+
+	if s.Count != 1000 {
+		t.Errorf("count want: 1000, got: %d", s.Count)
+	}
+
+	got := s.Quantile(percentRank(1000, 100))
+	if math.Abs(got-1000) > 1000*0.01 {
+		t.Errorf("p100 want: 1000 (±1%%), got: %f", got)
+	}
+}
+
+func TestHTTPStatsWithSketch(t *testing.T) {
+	stats := NewHTTPStats(true, false, false)
+	if err := stats.SetPercentileEstimator(PercentileEstimatorSketch, 0.01); err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 1; i <= 100; i++ {
+		stats.Set("/foo", "GET", 200, float64(i), 0, 0)
+	}
+
+	s := stats.Stats()[0]
+	if len(s.ResponseTime.Percentiles) != 0 {
+		t.Errorf("percentiles want: 0 samples, got: %d", len(s.ResponseTime.Percentiles))
+	}
+
+	got := s.PNResponseTime(90)
+	if math.Abs(got-90) > 90*0.01 {
+		t.Errorf("p90 want: 90 (±1%%), got: %f", got)
+	}
+
+	if err := stats.SetPercentileEstimator("unknown", 0.01); err == nil {
+		t.Errorf("want: error, got: nil")
+	}
+}
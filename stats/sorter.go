@@ -3,6 +3,7 @@ package stats
 import (
 	"fmt"
 	"sort"
+	"strconv"
 )
 
 const (
@@ -27,12 +28,24 @@ const (
 	SortAvgResponseBodyBytes    = "AvgResponseBodyBytes"
 	SortPNResponseBodyBytes     = "PNResponseBodyBytes"
 	SortStddevResponseBodyBytes = "StddevResponseBodyBytes"
+	SortStatusCode              = "StatusCode"
+	SortStatusOther             = "StatusOther"
+	SortStatus4xxRate           = "Status4xxRate"
+	SortStatus5xxRate           = "Status5xxRate"
+	SortRequestsPerSecond       = "RequestsPerSecond"
+	SortApdex                   = "Apdex"
+	SortGroup                   = "Group"
+	SortChange                  = "Change"
 )
 
 type SortOptions struct {
 	options    map[string]string
 	sortType   string
 	percentile int
+	statusCode int
+	// groupIndex is the index of each key of group-by
+	groupIndex map[string]int
+	group      int
 }
 
 func NewSortOptions() *SortOptions {
@@ -50,6 +63,25 @@ func NewSortOptions() *SortOptions {
 		"sum-body": SortSumResponseBodyBytes,
 		"stddev":   SortStddevResponseTime,
 		"pn":       SortPNResponseTime,
+		// response body bytes
+		"stddev-body": SortStddevResponseBodyBytes,
+		"pn-body":     SortPNResponseBodyBytes,
+		// request body bytes
+		"max-req-body":    SortMaxRequestBodyBytes,
+		"min-req-body":    SortMinRequestBodyBytes,
+		"avg-req-body":    SortAvgRequestBodyBytes,
+		"sum-req-body":    SortSumRequestBodyBytes,
+		"stddev-req-body": SortStddevRequestBodyBytes,
+		"pn-req-body":     SortPNRequestBodyBytes,
+		// status codes
+		"other": SortStatusOther,
+		// derived values
+		"4xx-rate": SortStatus4xxRate,
+		"5xx-rate": SortStatus5xxRate,
+		"rps":      SortRequestsPerSecond,
+		"apdex":    SortApdex,
+		// diff
+		"change": SortChange,
 	}
 
 	return &SortOptions{
@@ -57,6 +89,14 @@ func NewSortOptions() *SortOptions {
 	}
 }
 
+// SetGroupBy makes the keys of group-by available as the sort keys, and must be called before SetAndValidate
+func (so *SortOptions) SetGroupBy(keys []string) {
+	so.groupIndex = make(map[string]int, len(keys))
+	for i, key := range keys {
+		so.groupIndex[key] = i
+	}
+}
+
 func (so *SortOptions) SetAndValidate(opt string) error {
 	_, ok := so.options[opt]
 	if ok {
@@ -64,17 +104,24 @@ func (so *SortOptions) SetAndValidate(opt string) error {
 		return nil
 	}
 
-	var n int
-	_, err := fmt.Sscanf(opt, "p%d", &n)
-	if err != nil {
-		return err
+	if i, ok := so.groupIndex[opt]; ok {
+		so.sortType = SortGroup
+		so.group = i
+		return nil
+	}
+
+	if code, err := strconv.Atoi(opt); err == nil && code >= 100 && code <= 599 {
+		so.sortType = SortStatusCode
+		so.statusCode = code
+		return nil
 	}
 
-	if n < 0 && n > 100 {
-		return fmt.Errorf("enum value must be one of max,min,avg,sum,count,uri,method,max-body,min-body,avg-body,sum-body,pN(N = 0 ~ 100),stddev, got '%s'", opt)
+	n, suffix, ok := parsePercentileKeyword(opt)
+	if !ok || n < 0 || n > 100 || (suffix != "" && suffix != "-body" && suffix != "-req-body") {
+		return fmt.Errorf("enum value must be one of max,min,avg,sum,count,uri,method,max-body,min-body,avg-body,sum-body,pN-body,stddev-body,pN(N = 0 ~ 100),stddev,max-req-body,min-req-body,avg-req-body,sum-req-body,pN-req-body,stddev-req-body,NNN(status code),other,4xx-rate,5xx-rate,rps,apdex,change,the keys of group-by, got '%s'", opt)
 	}
 
-	so.sortType = so.options["pn"]
+	so.sortType = so.options["pn"+suffix]
 	so.percentile = n
 
 	return nil
@@ -88,6 +135,15 @@ func (so *SortOptions) Percentile() int {
 	return so.percentile
 }
 
+func (so *SortOptions) StatusCode() int {
+	return so.statusCode
+}
+
+// Group returns the index of the group-by key to sort by
+func (so *SortOptions) Group() int {
+	return so.group
+}
+
 func (hs *HTTPStats) Sort(sortOptions *SortOptions, reverse bool) {
 	switch sortOptions.sortType {
 	case SortCount:
@@ -135,6 +191,26 @@ func (hs *HTTPStats) Sort(sortOptions *SortOptions, reverse bool) {
 		hs.SortPNResponseBodyBytes(reverse)
 	case SortStddevResponseBodyBytes:
 		hs.SortStddevResponseBodyBytes(reverse)
+	// status codes
+	case SortStatusCode:
+		hs.SortStatusCode(reverse)
+	case SortStatusOther:
+		hs.SortStatusOther(reverse)
+	// derived values
+	case SortStatus4xxRate:
+		hs.SortStatus4xxRate(reverse)
+	case SortStatus5xxRate:
+		hs.SortStatus5xxRate(reverse)
+	case SortRequestsPerSecond:
+		hs.SortRequestsPerSecond(reverse)
+	case SortApdex:
+		hs.SortApdex(reverse)
+	// group-by
+	case SortGroup:
+		hs.SortGroup(reverse)
+	// diff
+	case SortChange:
+		hs.SortChange(reverse)
 	default:
 		hs.SortCount(reverse)
 	}
@@ -393,3 +469,114 @@ func (hs *HTTPStats) SortStddevResponseBodyBytes(reverse bool) {
 		})
 	}
 }
+
+func (hs *HTTPStats) SortStatusCode(reverse bool) {
+	if reverse {
+		sort.Slice(hs.stats, func(i, j int) bool {
+			return hs.stats[i].StatusCode(hs.sortOptions.statusCode) > hs.stats[j].StatusCode(hs.sortOptions.statusCode)
+		})
+	} else {
+		sort.Slice(hs.stats, func(i, j int) bool {
+			return hs.stats[i].StatusCode(hs.sortOptions.statusCode) < hs.stats[j].StatusCode(hs.sortOptions.statusCode)
+		})
+	}
+}
+
+func (hs *HTTPStats) SortStatusOther(reverse bool) {
+	var codes []int
+	if hs.options != nil {
+		codes = hs.options.StatusCodes
+	}
+
+	if reverse {
+		sort.Slice(hs.stats, func(i, j int) bool {
+			return hs.stats[i].StatusOther(codes) > hs.stats[j].StatusOther(codes)
+		})
+	} else {
+		sort.Slice(hs.stats, func(i, j int) bool {
+			return hs.stats[i].StatusOther(codes) < hs.stats[j].StatusOther(codes)
+		})
+	}
+}
+
+func (hs *HTTPStats) SortStatus4xxRate(reverse bool) {
+	if reverse {
+		sort.Slice(hs.stats, func(i, j int) bool {
+			return hs.stats[i].Status4xxRate() > hs.stats[j].Status4xxRate()
+		})
+	} else {
+		sort.Slice(hs.stats, func(i, j int) bool {
+			return hs.stats[i].Status4xxRate() < hs.stats[j].Status4xxRate()
+		})
+	}
+}
+
+func (hs *HTTPStats) SortStatus5xxRate(reverse bool) {
+	if reverse {
+		sort.Slice(hs.stats, func(i, j int) bool {
+			return hs.stats[i].Status5xxRate() > hs.stats[j].Status5xxRate()
+		})
+	} else {
+		sort.Slice(hs.stats, func(i, j int) bool {
+			return hs.stats[i].Status5xxRate() < hs.stats[j].Status5xxRate()
+		})
+	}
+}
+
+func (hs *HTTPStats) SortRequestsPerSecond(reverse bool) {
+	if reverse {
+		sort.Slice(hs.stats, func(i, j int) bool {
+			return hs.stats[i].RequestsPerSecond() > hs.stats[j].RequestsPerSecond()
+		})
+	} else {
+		sort.Slice(hs.stats, func(i, j int) bool {
+			return hs.stats[i].RequestsPerSecond() < hs.stats[j].RequestsPerSecond()
+		})
+	}
+}
+
+func (hs *HTTPStats) SortApdex(reverse bool) {
+	if reverse {
+		sort.Slice(hs.stats, func(i, j int) bool {
+			return hs.stats[i].Apdex() > hs.stats[j].Apdex()
+		})
+	} else {
+		sort.Slice(hs.stats, func(i, j int) bool {
+			return hs.stats[i].Apdex() < hs.stats[j].Apdex()
+		})
+	}
+}
+
+func (hs *HTTPStats) SortGroup(reverse bool) {
+	if reverse {
+		sort.Slice(hs.stats, func(i, j int) bool {
+			return hs.stats[i].Group(hs.sortOptions.group) > hs.stats[j].Group(hs.sortOptions.group)
+		})
+	} else {
+		sort.Slice(hs.stats, func(i, j int) bool {
+			return hs.stats[i].Group(hs.sortOptions.group) < hs.stats[j].Group(hs.sortOptions.group)
+		})
+	}
+}
+
+// change returns the relative change of the average response time from the stats of diff, and 0 if there is no stat to compare with
+func (hs *HTTPStats) change(s *HTTPStat) float64 {
+	from, ok := hs.diffFrom[s.key()]
+	if !ok {
+		return 0
+	}
+
+	return NewDiffer(from, s).Change()
+}
+
+func (hs *HTTPStats) SortChange(reverse bool) {
+	if reverse {
+		sort.Slice(hs.stats, func(i, j int) bool {
+			return hs.change(hs.stats[i]) > hs.change(hs.stats[j])
+		})
+	} else {
+		sort.Slice(hs.stats, func(i, j int) bool {
+			return hs.change(hs.stats[i]) < hs.change(hs.stats[j])
+		})
+	}
+}
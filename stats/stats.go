@@ -5,11 +5,16 @@ import (
 	"math"
 	"net/url"
 	"regexp"
+	"slices"
 	"sort"
+	"strconv"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/tkuchiki/alp/errors"
 	"github.com/tkuchiki/alp/helpers"
+	"github.com/tkuchiki/alp/openapi"
 	"github.com/tkuchiki/alp/options"
 	"github.com/tkuchiki/alp/parsers"
 )
@@ -47,7 +52,31 @@ type HTTPStats struct {
 	filter                         *Filter
 	options                        *options.Options
 	sortOptions                    *SortOptions
-	uriMatchingGroups              []*regexp.Regexp
+	uriMatchingGroups              []*uriMatchingGroup
+	routes                         []*openapi.Route
+	percentileEstimator            *percentileEstimator
+	timelineInterval               time.Duration
+	responseTimeBuckets            []float64
+	// apdexThreshold is the T of the Apdex score, and the Apdex score is not counted if it is 0
+	apdexThreshold float64
+	// useRequestsPerSecond keeps the times of the first and the last requests to calculate the requests per second
+	useRequestsPerSecond bool
+	// groupBy is the keys of the log entries that are aggregated in addition to the method and the URI
+	groupBy []string
+	// statusCodes is the status codes that are counted exactly, and no status code is counted if it is empty
+	statusCodes []int
+	// diffFrom is the stats to compare with by diff, and is used to sort by the relative changes
+	diffFrom map[string]*HTTPStat
+	// parser is the format of the profiled logs, and is written to the metadata of the dump
+	parser string
+	// logSummary is the time range and the line counts of the profiled logs
+	logSummary *LogSummary
+	// useLogTimeRange parses the time of each log to record the time range in logSummary
+	useLogTimeRange bool
+	// useRequestsOverTime counts the requests in each second in logSummary for the charts of the HTML report
+	useRequestsOverTime bool
+	// metadata is the metadata of the loaded dumps, and is nil if no dump is loaded
+	metadata *DumpMetadata
 }
 
 func NewHTTPStats(useResTimePercentile, useRequestBodyBytesPercentile, useResponseBodyBytesPercentile bool) *HTTPStats {
@@ -55,36 +84,332 @@ func NewHTTPStats(useResTimePercentile, useRequestBodyBytesPercentile, useRespon
 		hints:                          newHints(),
 		stats:                          make([]*HTTPStat, 0),
 		useResponseTimePercentile:      useResTimePercentile,
+		useRequestBodyBytesPercentile:  useRequestBodyBytesPercentile,
 		useResponseBodyBytesPercentile: useResponseBodyBytesPercentile,
+		logSummary:                     &LogSummary{},
 	}
 }
 
+func (hs *HTTPStats) SetPercentileEstimator(name string, relativeAccuracy float64) error {
+	pe, err := newPercentileEstimator(name, relativeAccuracy)
+	if err != nil {
+		return err
+	}
+
+	hs.percentileEstimator = pe
+
+	return nil
+}
+
+// SetResponseTimeBuckets enables the histogram of the response times for the metrics.
+// val is the upper bounds of the buckets separated by commas (e.g. 0.1,0.5,1)
+func (hs *HTTPStats) SetResponseTimeBuckets(val string) error {
+	if val == "" {
+		return nil
+	}
+
+	buckets, err := helpers.SplitCSVIntoFloat64s(val)
+	if err != nil {
+		return err
+	}
+
+	for i := 1; i < len(buckets); i++ {
+		if buckets[i-1] >= buckets[i] {
+			return fmt.Errorf("buckets must be in increasing order, got %s", val)
+		}
+	}
+
+	hs.responseTimeBuckets = buckets
+
+	return nil
+}
+
+// SetApdexThreshold sets the T of the Apdex score in seconds.
+// The requests are satisfied if the response time is T or less, and tolerating if it is 4T or less.
+func (hs *HTTPStats) SetApdexThreshold(t float64) error {
+	if t < 0 {
+		return fmt.Errorf("apdex threshold must be 0 or more, got %v", t)
+	}
+
+	hs.apdexThreshold = t
+
+	return nil
+}
+
+// SetGroupBy sets the keys of the log entries to aggregate by, in addition to the method and the URI.
+// The values are passed to SetWithTime in the same order.
+func (hs *HTTPStats) SetGroupBy(keys []string) {
+	hs.groupBy = keys
+}
+
+// SetStatusCodes sets the status codes to count exactly.
+// The other status codes are counted only by the classes such as 4xx, so that the requests allocate nothing for them.
+func (hs *HTTPStats) SetStatusCodes(codes []int) {
+	hs.statusCodes = codes
+}
+
+// CountsStatusCode reports whether the exact status code is counted by the profiled logs, or by the loaded dumps
+func (hs *HTTPStats) CountsStatusCode(code int) bool {
+	if slices.Contains(hs.statusCodes, code) {
+		return true
+	}
+
+	md := hs.Metadata()
+	return md.Options != nil && slices.Contains(md.Options.StatusCodes, code)
+}
+
+// CountsApdex reports whether the Apdex score is counted by the profiled logs, or by the loaded dumps
+func (hs *HTTPStats) CountsApdex() bool {
+	if hs.apdexThreshold > 0 {
+		return true
+	}
+
+	md := hs.Metadata()
+	return md.Options != nil && md.Options.ApdexThreshold > 0
+}
+
+// GroupBy returns the keys of the log entries to aggregate by
+func (hs *HTTPStats) GroupBy() []string {
+	return hs.groupBy
+}
+
+// SetParser sets the format of the profiled logs, e.g. json, ltsv, regexp and pcap
+func (hs *HTTPStats) SetParser(name string) {
+	hs.parser = name
+}
+
+// EnableLogTimeRange parses the time of each log to record the time range of the logs, even if the time is not used otherwise
+func (hs *HTTPStats) EnableLogTimeRange() {
+	hs.useLogTimeRange = true
+}
+
+// EnableRequestsOverTime counts the requests in each second if the time of each log can be parsed
+func (hs *HTTPStats) EnableRequestsOverTime() {
+	hs.useRequestsOverTime = true
+}
+
+// SkipLine counts the line that cannot be parsed
+func (hs *HTTPStats) SkipLine() {
+	hs.logSummary.SkippedLines++
+}
+
+// LogSummary returns the time range and the line counts of the profiled logs
+func (hs *HTTPStats) LogSummary() *LogSummary {
+	return hs.logSummary
+}
+
+// MergeLogSummary merges the time range and the line counts of the other logs, e.g. the logs profiled by another worker
+func (hs *HTTPStats) MergeLogSummary(other *LogSummary) {
+	hs.logSummary.merge(other)
+}
+
+// SetDiffFrom sets the stats to compare with by diff, to sort by the relative changes from them
+func (hs *HTTPStats) SetDiffFrom(from *HTTPStats) {
+	hs.diffFrom = make(map[string]*HTTPStat, len(from.stats))
+	for _, s := range from.stats {
+		hs.diffFrom[s.key()] = s
+	}
+}
+
+// EnableRequestsPerSecond keeps the times of the first and the last requests of each stat, and requires the time of each log
+func (hs *HTTPStats) EnableRequestsPerSecond() {
+	hs.useRequestsPerSecond = true
+}
+
+// SetTimelineInterval enables the timeline that aggregates each stat into buckets of the interval (e.g. 10s, 1m)
+func (hs *HTTPStats) SetTimelineInterval(interval string) error {
+	if interval == "" {
+		return nil
+	}
+
+	d, err := time.ParseDuration(interval)
+	if err != nil {
+		return err
+	}
+
+	if d <= 0 {
+		return fmt.Errorf("timeline interval must be greater than 0, got %s", interval)
+	}
+
+	hs.timelineInterval = d
+
+	return nil
+}
+
 func (hs *HTTPStats) Set(uri, method string, status int, restime, resBodyBytes, reqBodyBytes float64) {
-	if len(hs.uriMatchingGroups) > 0 {
-		for _, re := range hs.uriMatchingGroups {
-			if ok := re.Match([]byte(uri)); ok {
-				pattern := re.String()
-				uri = pattern
-				break
+	hs.stat(uri, method, nil).Set(status, restime, reqBodyBytes, resBodyBytes)
+}
+
+// SetWithTime is the same as Set, and also aggregates by groups, the values of the keys of SetGroupBy,
+// and counts the request in the timeline bucket that timestr belongs to
+func (hs *HTTPStats) SetWithTime(uri, method, timestr string, groups []string, status int, restime, resBodyBytes, reqBodyBytes float64) error {
+	if hs.timelineInterval == 0 && !hs.useRequestsPerSecond {
+		hs.stat(uri, method, groups).Set(status, restime, reqBodyBytes, resBodyBytes)
+
+		// the time range is recorded only if the time can be parsed, because the time is not required
+		if (hs.useLogTimeRange || hs.useRequestsOverTime) && timestr != "" {
+			if t, err := hs.filter.ParseTime(timestr); err == nil {
+				hs.logSummary.setTime(t)
+				if hs.useRequestsOverTime {
+					hs.logSummary.countRequest(t)
+				}
 			}
 		}
+
+		return nil
 	}
 
-	key := fmt.Sprintf("%s_%s", method, uri)
+	if timestr == "" {
+		return fmt.Errorf("time is empty, the timeline and the requests per second require the time of each log")
+	}
+
+	t, err := hs.filter.ParseTime(timestr)
+	if err != nil {
+		return fmt.Errorf("failed to parse time '%s': %s", timestr, err)
+	}
+
+	hs.logSummary.setTime(t)
+	if hs.useRequestsOverTime {
+		hs.logSummary.countRequest(t)
+	}
+
+	s := hs.stat(uri, method, groups)
+	s.Set(status, restime, reqBodyBytes, resBodyBytes)
+	if hs.useRequestsPerSecond {
+		s.setTime(t)
+	}
+
+	if hs.timelineInterval == 0 {
+		return nil
+	}
+
+	b := s.timelineBucket(t.Truncate(hs.timelineInterval).Format(time.RFC3339), hs.percentileEstimator)
+	b.Set(status, restime, reqBodyBytes, resBodyBytes)
+	if hs.useRequestsPerSecond {
+		b.setTime(t)
+	}
+
+	return nil
+}
+
+// uriMatchingGroup is the compiled options.MatchingGroup
+type uriMatchingGroup struct {
+	re *regexp.Regexp
+	// name is displayed as the Uri, and is the pattern if the group is unnamed
+	name    string
+	methods []string
+}
+
+func newURIMatchingGroup(re *regexp.Regexp, name string, methods []string) *uriMatchingGroup {
+	if name == "" {
+		name = re.String()
+	}
+
+	upperMethods := make([]string, 0, len(methods))
+	for _, m := range methods {
+		upperMethods = append(upperMethods, strings.ToUpper(m))
+	}
+
+	return &uriMatchingGroup{
+		re:      re,
+		name:    name,
+		methods: upperMethods,
+	}
+}
+
+func (g *uriMatchingGroup) match(uri, method string) bool {
+	if len(g.methods) > 0 && !slices.Contains(g.methods, method) {
+		return false
+	}
+
+	return g.re.MatchString(uri)
+}
+
+// MatchingUri returns the path template of the route that uri and method match, the name of the URI matching group that uri and method belong to, or uri itself.
+// documented is false if the routes are set and uri and method match none of them.
+func (hs *HTTPStats) MatchingUri(uri, method string) (matched string, documented bool) {
+	if len(hs.routes) > 0 {
+		path, query, hasQuery := strings.Cut(uri, "?")
+		for _, route := range hs.routes {
+			if !route.Match(path) {
+				continue
+			}
+
+			if hasQuery {
+				path = fmt.Sprintf("%s?%s", route.Path, query)
+			} else {
+				path = route.Path
+			}
+
+			// the path is documented, but the method is not
+			return path, route.HasMethod(method)
+		}
+	}
+
+	for _, g := range hs.uriMatchingGroups {
+		if g.match(uri, method) {
+			return g.name, len(hs.routes) == 0
+		}
+	}
+
+	return uri, len(hs.routes) == 0
+}
+
+func (hs *HTTPStats) stat(uri, method string, groups []string) *HTTPStat {
+	uri, documented := hs.MatchingUri(uri, method)
+
+	key := statKey(uri, method, groups)
 
 	idx := hs.hints.loadOrStore(key)
 
 	if idx >= len(hs.stats) {
-		hs.stats = append(hs.stats, newHTTPStat(uri, method, hs.useResponseTimePercentile, hs.useRequestBodyBytesPercentile, hs.useResponseBodyBytesPercentile))
+		s := newHTTPStat(uri, method, hs.useResponseTimePercentile, hs.useRequestBodyBytesPercentile, hs.useResponseBodyBytesPercentile, hs.percentileEstimator)
+		s.Undocumented = !documented
+		s.Groups = groups
+		s.apdexThreshold = hs.apdexThreshold
+		s.statusCodes = hs.statusCodes
+		if len(hs.responseTimeBuckets) > 0 {
+			s.responseTimeBuckets = newBucketCounter(hs.responseTimeBuckets)
+		}
+		hs.stats = append(hs.stats, s)
 	}
 
-	hs.stats[idx].Set(status, restime, resBodyBytes, reqBodyBytes)
+	return hs.stats[idx]
+}
+
+// MergeStat merges s into the stat that has the same method, URI and groups, or appends s if there is none
+func (hs *HTTPStats) MergeStat(s *HTTPStat) error {
+	idx := hs.hints.loadOrStore(s.key())
+
+	if idx >= len(hs.stats) {
+		hs.stats = append(hs.stats, s)
+		return nil
+	}
+
+	return hs.stats[idx].Merge(s)
 }
 
 func (hs *HTTPStats) Stats() []*HTTPStat {
 	return hs.stats
 }
 
+// Find returns the stat that has the same method, URI and groups as s, and nil if there is none
+func (hs *HTTPStats) Find(s *HTTPStat) *HTTPStat {
+	key := s.key()
+	for _, st := range hs.stats {
+		if st.key() == key {
+			return st
+		}
+	}
+
+	return nil
+}
+
+// ParseTime parses the time of the logs in the location of the options
+func (hs *HTTPStats) ParseTime(timestr string) (time.Time, error) {
+	return hs.filter.ParseTime(timestr)
+}
+
 func (hs *HTTPStats) CountUris() int {
 	return hs.hints.len
 }
@@ -97,25 +422,44 @@ func (hs *HTTPStats) SetSortOptions(options *SortOptions) {
 	hs.sortOptions = options
 }
 
-func (hs *HTTPStats) SetURIMatchingGroups(groups []string) error {
-	uriGroups, err := helpers.CompileUriMatchingGroups(groups)
+func (hs *HTTPStats) SetURIMatchingGroups(groups []*options.MatchingGroup) error {
+	patterns := make([]string, 0, len(groups))
+	for _, g := range groups {
+		patterns = append(patterns, g.Pattern)
+	}
+
+	res, err := helpers.CompileUriMatchingGroups(patterns)
 	if err != nil {
 		return err
 	}
 
+	uriGroups := make([]*uriMatchingGroup, 0, len(groups))
+	for i, g := range groups {
+		uriGroups = append(uriGroups, newURIMatchingGroup(res[i], g.Name, g.Methods))
+	}
+
 	hs.uriMatchingGroups = uriGroups
 
 	return nil
 }
 
+// SetRoutes sets the routes of the OpenAPI document, and the URIs are aggregated into the path templates of the routes.
+// The URIs that match no route are flagged as undocumented.
+func (hs *HTTPStats) SetRoutes(routes []*openapi.Route) {
+	hs.routes = routes
+}
+
 func (hs *HTTPStats) InitFilter(options *options.Options) error {
 	hs.filter = NewFilter(options)
 	return hs.filter.Init()
 }
 
 func (hs *HTTPStats) DoFilter(pstat *parsers.ParsedHTTPStat) (bool, error) {
+	hs.logSummary.Lines++
+
 	err := hs.filter.Do(pstat)
 	if err == errors.SkipReadLineErr {
+		hs.logSummary.FilteredLines++
 		return false, nil
 	} else if err != nil {
 		return false, err
@@ -134,39 +478,104 @@ func (hs *HTTPStats) CountAll() map[string]int {
 		counts["3xx"] += s.Status3xx
 		counts["4xx"] += s.Status4xx
 		counts["5xx"] += s.Status5xx
+		for code, cnt := range s.StatusCodes {
+			counts[strconv.Itoa(code)] += cnt
+		}
 	}
 
 	return counts
 }
 
+// Timeline returns the HTTPStats whose stats are the timeline buckets of each stat.
+// The buckets are ordered by time, and the buckets of the same time keep the order of the stats.
+func (hs *HTTPStats) Timeline() *HTTPStats {
+	type bucket struct {
+		time time.Time
+		stat *HTTPStat
+	}
+
+	buckets := make([]bucket, 0)
+	for _, s := range hs.stats {
+		for _, b := range s.Timeline {
+			t, _ := time.Parse(time.RFC3339, b.Time)
+			buckets = append(buckets, bucket{time: t, stat: b})
+		}
+	}
+
+	sort.SliceStable(buckets, func(i, j int) bool {
+		return buckets[i].time.Before(buckets[j].time)
+	})
+
+	timeline := NewHTTPStats(hs.useResponseTimePercentile, hs.useRequestBodyBytesPercentile, hs.useResponseBodyBytesPercentile)
+	timeline.options = hs.options
+	timeline.sortOptions = hs.sortOptions
+	timeline.logSummary = hs.logSummary
+	for _, b := range buckets {
+		timeline.stats = append(timeline.stats, b.stat)
+	}
+
+	return timeline
+}
+
+// Snapshot returns the HTTPStats that shares each stat with hs, but has its own order of the stats.
+// It can be sorted without breaking hs, which relies on the order of the stats to Set.
+func (hs *HTTPStats) Snapshot() *HTTPStats {
+	snapshot := NewHTTPStats(hs.useResponseTimePercentile, hs.useRequestBodyBytesPercentile, hs.useResponseBodyBytesPercentile)
+	snapshot.options = hs.options
+	snapshot.sortOptions = hs.sortOptions
+	snapshot.stats = append(snapshot.stats, hs.stats...)
+
+	return snapshot
+}
+
 func (hs *HTTPStats) SortWithOptions() {
 	hs.Sort(hs.sortOptions, hs.options.Reverse)
 }
 
 type HTTPStat struct {
-	Uri               string        `yaml:"uri"`
-	Cnt               int           `yaml:"count"`
-	Status1xx         int           `yaml:"status1xx"`
-	Status2xx         int           `yaml:"status2xx"`
-	Status3xx         int           `yaml:"status3xx"`
-	Status4xx         int           `yaml:"status4xx"`
-	Status5xx         int           `yaml:"status5xx"`
-	Method            string        `yaml:"method"`
+	Uri       string `yaml:"uri"`
+	Cnt       int    `yaml:"count"`
+	Status1xx int    `yaml:"status1xx"`
+	Status2xx int    `yaml:"status2xx"`
+	Status3xx int    `yaml:"status3xx"`
+	Status4xx int    `yaml:"status4xx"`
+	Status5xx int    `yaml:"status5xx"`
+	// StatusCodes counts the requests by the exact status code
+	StatusCodes map[int]int `yaml:"status_codes,omitempty"`
+	Method      string      `yaml:"method"`
+	// Groups are the values of the keys of HTTPStats.SetGroupBy
+	Groups            []string      `yaml:"groups,omitempty"`
 	ResponseTime      *responseTime `yaml:"response_time"`
 	RequestBodyBytes  *bodyBytes    `yaml:"request_body_bytes"`
 	ResponseBodyBytes *bodyBytes    `yaml:"response_body_bytes"`
-	Time              string
+	// Time is the start of the timeline bucket, and is empty for the stat of the whole time
+	Time     string      `yaml:"time,omitempty"`
+	Timeline []*HTTPStat `yaml:"timeline,omitempty"`
+	// Undocumented reports whether the requests match no route of the OpenAPI document
+	Undocumented bool `yaml:"undocumented,omitempty"`
+	// FirstTime and LastTime are the times of the first and the last requests, only if the requests per second are used
+	FirstTime time.Time `yaml:"first_time,omitempty"`
+	LastTime  time.Time `yaml:"last_time,omitempty"`
+	// ApdexSatisfied and ApdexTolerating count the requests for the Apdex score
+	ApdexSatisfied  int `yaml:"apdex_satisfied,omitempty"`
+	ApdexTolerating int `yaml:"apdex_tolerating,omitempty"`
+	apdexThreshold  float64
+	// statusCodes is the status codes to count in StatusCodes
+	statusCodes   []int
+	timelineHints map[string]int
+	// responseTimeBuckets counts the response times for the metrics, only if HTTPStats.SetResponseTimeBuckets is called
+	responseTimeBuckets *bucketCounter
 }
 
 type httpStats []*HTTPStat
 
-func newHTTPStat(uri, method string, useResTimePercentile, useRequestBodyBytesPercentile, useResponseBodyBytesPercentile bool) *HTTPStat {
+func newHTTPStat(uri, method string, useResTimePercentile, useRequestBodyBytesPercentile, useResponseBodyBytesPercentile bool, pe *percentileEstimator) *HTTPStat {
 	return &HTTPStat{
 		Uri:               uri,
 		Method:            method,
-		ResponseTime:      newResponseTime(useResTimePercentile),
-		RequestBodyBytes:  newBodyBytes(useRequestBodyBytesPercentile),
-		ResponseBodyBytes: newBodyBytes(useResponseBodyBytesPercentile),
+		ResponseTime:      newResponseTime(useResTimePercentile, pe),
+		RequestBodyBytes:  newBodyBytes(useRequestBodyBytesPercentile, pe),
+		ResponseBodyBytes: newBodyBytes(useResponseBodyBytesPercentile, pe),
 	}
 }
 
@@ -176,6 +585,144 @@ func (hs *HTTPStat) Set(status int, restime, reqBodyBytes, resBodyBytes float64)
 	hs.ResponseTime.Set(restime)
 	hs.RequestBodyBytes.Set(reqBodyBytes)
 	hs.ResponseBodyBytes.Set(resBodyBytes)
+
+	if hs.apdexThreshold > 0 {
+		if restime <= hs.apdexThreshold {
+			hs.ApdexSatisfied++
+		} else if restime <= hs.apdexThreshold*4 {
+			hs.ApdexTolerating++
+		}
+	}
+
+	if hs.responseTimeBuckets != nil {
+		hs.responseTimeBuckets.observe(restime)
+	}
+}
+
+func (hs *HTTPStat) Merge(other *HTTPStat) error {
+	if other.Cnt == 0 {
+		return nil
+	}
+
+	empty := hs.Cnt == 0
+
+	hs.Cnt += other.Cnt
+	hs.Status1xx += other.Status1xx
+	hs.Status2xx += other.Status2xx
+	hs.Status3xx += other.Status3xx
+	hs.Status4xx += other.Status4xx
+	hs.Status5xx += other.Status5xx
+	for code, cnt := range other.StatusCodes {
+		if hs.StatusCodes == nil {
+			hs.StatusCodes = make(map[int]int, len(other.StatusCodes))
+		}
+		hs.StatusCodes[code] += cnt
+	}
+	hs.ApdexSatisfied += other.ApdexSatisfied
+	hs.ApdexTolerating += other.ApdexTolerating
+
+	if !other.FirstTime.IsZero() {
+		hs.setTime(other.FirstTime)
+		hs.setTime(other.LastTime)
+	}
+
+	if err := hs.ResponseTime.Merge(other.ResponseTime, empty); err != nil {
+		return err
+	}
+
+	if err := hs.RequestBodyBytes.Merge(other.RequestBodyBytes, empty); err != nil {
+		return err
+	}
+
+	if err := hs.ResponseBodyBytes.Merge(other.ResponseBodyBytes, empty); err != nil {
+		return err
+	}
+
+	if hs.responseTimeBuckets != nil && other.responseTimeBuckets != nil {
+		if err := hs.responseTimeBuckets.merge(other.responseTimeBuckets); err != nil {
+			return err
+		}
+	} else if empty {
+		hs.responseTimeBuckets = other.responseTimeBuckets
+	}
+
+	for _, ob := range other.Timeline {
+		idx, ok := hs.timelineIndex(ob.Time)
+		if !ok {
+			hs.timelineHints[ob.Time] = len(hs.Timeline)
+			hs.Timeline = append(hs.Timeline, ob)
+			continue
+		}
+
+		if err := hs.Timeline[idx].Merge(ob); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (hs *HTTPStat) timelineIndex(timestr string) (int, bool) {
+	if hs.timelineHints == nil {
+		hs.timelineHints = make(map[string]int, len(hs.Timeline))
+		for i, b := range hs.Timeline {
+			hs.timelineHints[b.Time] = i
+		}
+	}
+
+	idx, ok := hs.timelineHints[timestr]
+
+	return idx, ok
+}
+
+// timelineBucket returns the bucket of timestr, and creates it if there is none
+func (hs *HTTPStat) timelineBucket(timestr string, pe *percentileEstimator) *HTTPStat {
+	idx, ok := hs.timelineIndex(timestr)
+	if ok {
+		return hs.Timeline[idx]
+	}
+
+	b := newHTTPStat(hs.Uri, hs.Method, hs.ResponseTime.UsePercentile, hs.RequestBodyBytes.UsePercentile, hs.ResponseBodyBytes.UsePercentile, pe)
+	b.Time = timestr
+	b.Undocumented = hs.Undocumented
+	b.Groups = hs.Groups
+	b.apdexThreshold = hs.apdexThreshold
+	b.statusCodes = hs.statusCodes
+	hs.timelineHints[timestr] = len(hs.Timeline)
+	hs.Timeline = append(hs.Timeline, b)
+
+	return b
+}
+
+func (hs *HTTPStat) setTime(t time.Time) {
+	if hs.FirstTime.IsZero() || t.Before(hs.FirstTime) {
+		hs.FirstTime = t
+	}
+
+	if t.After(hs.LastTime) {
+		hs.LastTime = t
+	}
+}
+
+func (hs *HTTPStat) key() string {
+	return statKey(hs.Uri, hs.Method, hs.Groups)
+}
+
+func statKey(uri, method string, groups []string) string {
+	if len(groups) == 0 {
+		return fmt.Sprintf("%s_%s", method, uri)
+	}
+
+	return fmt.Sprintf("%s_%s_%s", method, uri, strings.Join(groups, "\t"))
+}
+
+// Group returns the i-th value of the groups, and returns empty if there is none
+func (hs *HTTPStat) Group(i int) string {
+	if i >= len(hs.Groups) {
+		return ""
+	}
+
+	return hs.Groups[i]
 }
 
 func (hs *HTTPStat) setStatus(status int) {
@@ -190,6 +737,15 @@ func (hs *HTTPStat) setStatus(status int) {
 	} else if status >= 500 && status <= 599 {
 		hs.Status5xx++
 	}
+
+	if !slices.Contains(hs.statusCodes, status) {
+		return
+	}
+
+	if hs.StatusCodes == nil {
+		hs.StatusCodes = make(map[int]int, len(hs.statusCodes))
+	}
+	hs.StatusCodes[status]++
 }
 
 func (hs *HTTPStat) UriWithOptions(decode bool) string {
@@ -233,6 +789,81 @@ func (hs *HTTPStat) StrStatus5xx() string {
 	return fmt.Sprint(hs.Status5xx)
 }
 
+// Status4xxRate returns the percentage of the requests whose status code is 4xx
+func (hs *HTTPStat) Status4xxRate() float64 {
+	if hs.Cnt == 0 {
+		return 0
+	}
+
+	return float64(hs.Status4xx) / float64(hs.Cnt) * 100
+}
+
+// Status5xxRate returns the percentage of the requests whose status code is 5xx
+func (hs *HTTPStat) Status5xxRate() float64 {
+	if hs.Cnt == 0 {
+		return 0
+	}
+
+	return float64(hs.Status5xx) / float64(hs.Cnt) * 100
+}
+
+// RequestsPerSecond returns the number of the requests per second from the first to the last request.
+// The time span is at least one second, because the time of the logs is usually in seconds.
+func (hs *HTTPStat) RequestsPerSecond() float64 {
+	if hs.FirstTime.IsZero() {
+		return 0
+	}
+
+	span := hs.LastTime.Sub(hs.FirstTime).Seconds()
+	if span < 1 {
+		span = 1
+	}
+
+	return float64(hs.Cnt) / span
+}
+
+// Apdex returns the Apdex score, (satisfied + tolerating / 2) / count
+func (hs *HTTPStat) Apdex() float64 {
+	if hs.Cnt == 0 {
+		return 0
+	}
+
+	return (float64(hs.ApdexSatisfied) + float64(hs.ApdexTolerating)/2) / float64(hs.Cnt)
+}
+
+// StatusCode returns the number of the requests whose status code is code
+func (hs *HTTPStat) StatusCode(code int) int {
+	return hs.StatusCodes[code]
+}
+
+func (hs *HTTPStat) StrStatusCode(code int) string {
+	return fmt.Sprint(hs.StatusCode(code))
+}
+
+// StatusOther returns the number of the requests whose status code is none of codes
+func (hs *HTTPStat) StatusOther(codes []int) int {
+	return hs.Cnt - sumStatusCodes(hs.StatusCodes, codes)
+}
+
+func (hs *HTTPStat) StrStatusOther(codes []int) string {
+	return fmt.Sprint(hs.StatusOther(codes))
+}
+
+// sumStatusCodes sums the counts of the distinct codes
+func sumStatusCodes(counts map[int]int, codes []int) int {
+	var sum int
+	seen := make(map[int]struct{}, len(codes))
+	for _, code := range codes {
+		if _, ok := seen[code]; ok {
+			continue
+		}
+		seen[code] = struct{}{}
+		sum += counts[code]
+	}
+
+	return sum
+}
+
 func (hs *HTTPStat) Count() int {
 	return hs.Cnt
 }
@@ -265,6 +896,20 @@ func (hs *HTTPStat) StddevResponseTime() float64 {
 	return hs.ResponseTime.Stddev(hs.Cnt)
 }
 
+// HasResponseTimeSamples reports whether the percentiles and the standard deviation of the response times are calculated
+func (hs *HTTPStat) HasResponseTimeSamples() bool {
+	return hasSamples(hs.ResponseTime.UsePercentile, hs.ResponseTime.Sketch, len(hs.ResponseTime.Percentiles))
+}
+
+// ResponseTimeSamples returns every response time, and nil if they are not retained or are estimated by sketch
+func (hs *HTTPStat) ResponseTimeSamples() []float64 {
+	if !hs.ResponseTime.UsePercentile || hs.ResponseTime.Sketch != nil || len(hs.ResponseTime.Percentiles) != hs.Cnt {
+		return nil
+	}
+
+	return hs.ResponseTime.Percentiles
+}
+
 // request
 func (hs *HTTPStat) MaxRequestBodyBytes() float64 {
 	return hs.RequestBodyBytes.Max
@@ -290,29 +935,44 @@ func (hs *HTTPStat) StddevRequestBodyBytes() float64 {
 	return hs.RequestBodyBytes.Stddev(hs.Cnt)
 }
 
+// HasRequestBodyBytesSamples reports whether the percentiles and the standard deviation of the request body bytes are calculated
+func (hs *HTTPStat) HasRequestBodyBytesSamples() bool {
+	return hasSamples(hs.RequestBodyBytes.UsePercentile, hs.RequestBodyBytes.Sketch, len(hs.RequestBodyBytes.Percentiles))
+}
+
 // response
 func (hs *HTTPStat) MaxResponseBodyBytes() float64 {
-	return hs.RequestBodyBytes.Max
+	return hs.ResponseBodyBytes.Max
 }
 
 func (hs *HTTPStat) MinResponseBodyBytes() float64 {
-	return hs.RequestBodyBytes.Min
+	return hs.ResponseBodyBytes.Min
 }
 
 func (hs *HTTPStat) SumResponseBodyBytes() float64 {
-	return hs.RequestBodyBytes.Sum
+	return hs.ResponseBodyBytes.Sum
 }
 
 func (hs *HTTPStat) AvgResponseBodyBytes() float64 {
-	return hs.RequestBodyBytes.Avg(hs.Cnt)
+	return hs.ResponseBodyBytes.Avg(hs.Cnt)
 }
 
 func (hs *HTTPStat) PNResponseBodyBytes(n int) float64 {
-	return hs.RequestBodyBytes.PN(hs.Cnt, n)
+	return hs.ResponseBodyBytes.PN(hs.Cnt, n)
 }
 
 func (hs *HTTPStat) StddevResponseBodyBytes() float64 {
-	return hs.RequestBodyBytes.Stddev(hs.Cnt)
+	return hs.ResponseBodyBytes.Stddev(hs.Cnt)
+}
+
+// HasResponseBodyBytesSamples reports whether the percentiles and the standard deviation of the response body bytes are calculated
+func (hs *HTTPStat) HasResponseBodyBytesSamples() bool {
+	return hasSamples(hs.ResponseBodyBytes.UsePercentile, hs.ResponseBodyBytes.Sketch, len(hs.ResponseBodyBytes.Percentiles))
+}
+
+// hasSamples reports whether the samples or the sketch are retained for the percentiles
+func hasSamples(usePercentile bool, sk *sketch, n int) bool {
+	return usePercentile && (sk != nil || n > 0)
 }
 
 func percentRank(n int, pi int) int {
@@ -338,13 +998,22 @@ type responseTime struct {
 	Sum           float64 `yaml:"sum"`
 	UsePercentile bool
 	Percentiles   []float64 `yaml:"percentiles"`
+	Sketch        *sketch   `yaml:"sketch,omitempty"`
+	sorted        bool
+	hasMin        bool
 }
 
-func newResponseTime(usePercentile bool) *responseTime {
-	return &responseTime{
+func newResponseTime(usePercentile bool, pe *percentileEstimator) *responseTime {
+	res := &responseTime{
 		UsePercentile: usePercentile,
 		Percentiles:   make([]float64, 0),
 	}
+
+	if usePercentile {
+		res.Sketch = pe.newSketch()
+	}
+
+	return res
 }
 
 func (res *responseTime) Set(val float64) {
@@ -352,15 +1021,71 @@ func (res *responseTime) Set(val float64) {
 		res.Max = val
 	}
 
-	if res.Min >= val || res.Min == 0 {
+	if !res.hasMin || res.Min > val {
 		res.Min = val
+		res.hasMin = true
 	}
 
 	res.Sum += val
 
-	if res.UsePercentile {
-		res.Percentiles = append(res.Percentiles, val)
+	if !res.UsePercentile {
+		return
+	}
+
+	if res.Sketch != nil {
+		res.Sketch.Add(val)
+		return
 	}
+
+	res.Percentiles = append(res.Percentiles, val)
+	res.sorted = false
+}
+
+// Merge merges other into res. empty means that res has no values yet.
+func (res *responseTime) Merge(other *responseTime, empty bool) error {
+	if other == nil {
+		return nil
+	}
+
+	if res.Max < other.Max {
+		res.Max = other.Max
+	}
+
+	if empty || res.Min > other.Min {
+		res.Min = other.Min
+		res.hasMin = true
+	}
+
+	res.Sum += other.Sum
+
+	// the percentiles cannot be calculated from a part of the samples,
+	// e.g. when the dumps with and without the samples are merged
+	if !other.UsePercentile {
+		res.UsePercentile = false
+		res.Percentiles = nil
+		res.Sketch = nil
+	}
+
+	if !res.UsePercentile {
+		return nil
+	}
+
+	if res.Sketch != nil {
+		for _, v := range other.Percentiles {
+			res.Sketch.Add(v)
+		}
+
+		return res.Sketch.Merge(other.Sketch)
+	}
+
+	if other.Sketch != nil {
+		return fmt.Errorf("cannot merge the percentiles estimated by sketch into the exact percentiles")
+	}
+
+	res.Percentiles = append(res.Percentiles, other.Percentiles...)
+	res.sorted = false
+
+	return nil
 }
 
 func (res *responseTime) Avg(cnt int) float64 {
@@ -373,6 +1098,10 @@ func (res *responseTime) PN(cnt, n int) float64 {
 	}
 
 	plen := percentRank(cnt, n)
+	if res.Sketch != nil {
+		return clamp(res.Sketch.Quantile(plen), res.Min, res.Max)
+	}
+
 	res.Sort()
 	return res.Percentiles[plen]
 }
@@ -382,8 +1111,12 @@ func (res *responseTime) Stddev(cnt int) float64 {
 		return 0.0
 	}
 
-	var stdd float64
 	avg := res.Avg(cnt)
+	if res.Sketch != nil {
+		return res.Sketch.Stddev(avg, res.Min, res.Max)
+	}
+
+	var stdd float64
 	n := float64(cnt)
 
 	for _, v := range res.Percentiles {
@@ -394,6 +1127,11 @@ func (res *responseTime) Stddev(cnt int) float64 {
 }
 
 func (res *responseTime) Sort() {
+	if res.sorted {
+		return
+	}
+
+	res.sorted = true
 	sort.Slice(res.Percentiles, func(i, j int) bool {
 		return res.Percentiles[i] < res.Percentiles[j]
 	})
@@ -405,13 +1143,22 @@ type bodyBytes struct {
 	Sum           float64 `yaml:"sum"`
 	UsePercentile bool
 	Percentiles   []float64 `yaml:"percentiles"`
+	Sketch        *sketch   `yaml:"sketch,omitempty"`
+	sorted        bool
+	hasMin        bool
 }
 
-func newBodyBytes(usePercentile bool) *bodyBytes {
-	return &bodyBytes{
+func newBodyBytes(usePercentile bool, pe *percentileEstimator) *bodyBytes {
+	body := &bodyBytes{
 		UsePercentile: usePercentile,
 		Percentiles:   make([]float64, 0),
 	}
+
+	if usePercentile {
+		body.Sketch = pe.newSketch()
+	}
+
+	return body
 }
 
 func (body *bodyBytes) Set(val float64) {
@@ -419,15 +1166,71 @@ func (body *bodyBytes) Set(val float64) {
 		body.Max = val
 	}
 
-	if body.Min >= val || body.Min == 0.0 {
+	if !body.hasMin || body.Min > val {
 		body.Min = val
+		body.hasMin = true
 	}
 
 	body.Sum += val
 
-	if body.UsePercentile {
-		body.Percentiles = append(body.Percentiles, val)
+	if !body.UsePercentile {
+		return
+	}
+
+	if body.Sketch != nil {
+		body.Sketch.Add(val)
+		return
+	}
+
+	body.Percentiles = append(body.Percentiles, val)
+	body.sorted = false
+}
+
+// Merge merges other into body. empty means that body has no values yet.
+func (body *bodyBytes) Merge(other *bodyBytes, empty bool) error {
+	if other == nil {
+		return nil
+	}
+
+	if body.Max < other.Max {
+		body.Max = other.Max
+	}
+
+	if empty || body.Min > other.Min {
+		body.Min = other.Min
+		body.hasMin = true
+	}
+
+	body.Sum += other.Sum
+
+	// the percentiles cannot be calculated from a part of the samples,
+	// e.g. when the dumps with and without the samples are merged
+	if !other.UsePercentile {
+		body.UsePercentile = false
+		body.Percentiles = nil
+		body.Sketch = nil
+	}
+
+	if !body.UsePercentile {
+		return nil
 	}
+
+	if body.Sketch != nil {
+		for _, v := range other.Percentiles {
+			body.Sketch.Add(v)
+		}
+
+		return body.Sketch.Merge(other.Sketch)
+	}
+
+	if other.Sketch != nil {
+		return fmt.Errorf("cannot merge the percentiles estimated by sketch into the exact percentiles")
+	}
+
+	body.Percentiles = append(body.Percentiles, other.Percentiles...)
+	body.sorted = false
+
+	return nil
 }
 
 func (body *bodyBytes) Avg(cnt int) float64 {
@@ -440,6 +1243,10 @@ func (body *bodyBytes) PN(cnt, n int) float64 {
 	}
 
 	plen := percentRank(cnt, n)
+	if body.Sketch != nil {
+		return clamp(body.Sketch.Quantile(plen), body.Min, body.Max)
+	}
+
 	body.Sort()
 	return body.Percentiles[plen]
 }
@@ -449,8 +1256,12 @@ func (body *bodyBytes) Stddev(cnt int) float64 {
 		return 0.0
 	}
 
-	var stdd float64
 	avg := body.Avg(cnt)
+	if body.Sketch != nil {
+		return body.Sketch.Stddev(avg, body.Min, body.Max)
+	}
+
+	var stdd float64
 	n := float64(cnt)
 
 	for _, v := range body.Percentiles {
@@ -461,6 +1272,11 @@ func (body *bodyBytes) Stddev(cnt int) float64 {
 }
 
 func (body *bodyBytes) Sort() {
+	if body.sorted {
+		return
+	}
+
+	body.sorted = true
 	sort.Slice(body.Percentiles, func(i, j int) bool {
 		return body.Percentiles[i] < body.Percentiles[j]
 	})
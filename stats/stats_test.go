@@ -2,7 +2,11 @@ package stats
 
 import (
 	"fmt"
+	"reflect"
 	"testing"
+
+	"github.com/tkuchiki/alp/openapi"
+	"github.com/tkuchiki/alp/options"
 )
 
 func Test_percentRank(t *testing.T) {
@@ -24,3 +28,362 @@ func Test_percentRank(t *testing.T) {
 		})
 	}
 }
+
+func TestHTTPStatsMergeStat(t *testing.T) {
+	restimes := []float64{0.5, 0, 0.3, 0.1, 0.9, 0.2}
+
+	want := NewHTTPStats(true, false, false)
+	for _, v := range restimes {
+		want.Set("/foo", "GET", 200, v, 10, 0)
+	}
+
+	shards := []*HTTPStats{
+		NewHTTPStats(true, false, false),
+		NewHTTPStats(true, false, false),
+	}
+	for i, v := range restimes {
+		shards[i%2].Set("/foo", "GET", 200, v, 10, 0)
+	}
+
+	got := NewHTTPStats(true, false, false)
+	for _, sh := range shards {
+		for _, s := range sh.Stats() {
+			if err := got.MergeStat(s); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	if got.CountUris() != 1 {
+		t.Fatalf("want: 1 uri, got: %d", got.CountUris())
+	}
+
+	w, g := want.Stats()[0], got.Stats()[0]
+	if w.Count() != g.Count() {
+		t.Errorf("count want: %d, got: %d", w.Count(), g.Count())
+	}
+
+	if g.MinResponseTime() != 0 || w.MinResponseTime() != 0 {
+		t.Errorf("min want: 0, got: %v (sequential: %v)", g.MinResponseTime(), w.MinResponseTime())
+	}
+
+	if w.MaxResponseTime() != g.MaxResponseTime() {
+		t.Errorf("max want: %v, got: %v", w.MaxResponseTime(), g.MaxResponseTime())
+	}
+
+	for _, n := range []int{50, 90, 99} {
+		if w.PNResponseTime(n) != g.PNResponseTime(n) {
+			t.Errorf("p%d want: %v, got: %v", n, w.PNResponseTime(n), g.PNResponseTime(n))
+		}
+	}
+}
+
+func TestHTTPStatsTimeline(t *testing.T) {
+	hs := NewHTTPStats(true, false, false)
+	if err := hs.InitFilter(options.NewOptions(options.Location("UTC"))); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := hs.SetTimelineInterval("1m"); err != nil {
+		t.Fatal(err)
+	}
+
+	logs := []struct {
+		uri  string
+		time string
+	}{
+		{"/foo", "2015-09-06T05:58:05Z"},
+		{"/bar", "2015-09-06T06:00:01Z"},
+		{"/foo", "2015-09-06T05:59:59Z"},
+		{"/foo", "2015-09-06T05:58:59Z"},
+		{"/bar", "2015-09-06T05:58:30Z"},
+	}
+
+	for _, l := range logs {
+		if err := hs.SetWithTime(l.uri, "GET", l.time, nil, 200, 0.1, 0, 0); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	want := []string{
+		"2015-09-06T05:58:00Z GET /foo 2",
+		"2015-09-06T05:58:00Z GET /bar 1",
+		"2015-09-06T05:59:00Z GET /foo 1",
+		"2015-09-06T06:00:00Z GET /bar 1",
+	}
+
+	timeline := hs.Timeline()
+	if len(timeline.Stats()) != len(want) {
+		t.Fatalf("want: %d buckets, got: %d", len(want), len(timeline.Stats()))
+	}
+
+	for i, s := range timeline.Stats() {
+		got := fmt.Sprintf("%s %s %s %d", s.Time, s.Method, s.Uri, s.Count())
+		if got != want[i] {
+			t.Errorf("want: %s, got: %s", want[i], got)
+		}
+	}
+
+	if err := hs.SetWithTime("/foo", "GET", "", nil, 200, 0.1, 0, 0); err == nil {
+		t.Error("want: error, got: nil")
+	}
+}
+
+func TestHTTPStatsSetRoutes(t *testing.T) {
+	routes, err := openapi.Parse([]byte(`
+swagger: "2.0"
+basePath: /api
+paths:
+  /users/{id}:
+    get: {}
+  /users/me:
+    get: {}
+    put: {}
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	hs := NewHTTPStats(true, false, false)
+	hs.SetRoutes(routes)
+
+	logs := []struct {
+		uri    string
+		method string
+	}{
+		{"/api/users/1", "GET"},
+		{"/api/users/2?page=1", "GET"},
+		{"/api/users/me", "PUT"},
+		{"/api/users/1", "DELETE"},
+		{"/api/posts/1", "GET"},
+	}
+
+	for _, l := range logs {
+		hs.Set(l.uri, l.method, 200, 0.1, 0, 0)
+	}
+
+	want := []string{
+		"GET /api/users/{id} 1 false",
+		"GET /api/users/{id}?page=1 1 false",
+		"PUT /api/users/me 1 false",
+		"DELETE /api/users/{id} 1 true",
+		"GET /api/posts/1 1 true",
+	}
+
+	if len(hs.Stats()) != len(want) {
+		t.Fatalf("want: %d stats, got: %d", len(want), len(hs.Stats()))
+	}
+
+	for i, s := range hs.Stats() {
+		got := fmt.Sprintf("%s %s %d %t", s.Method, s.Uri, s.Count(), s.Undocumented)
+		if got != want[i] {
+			t.Errorf("want: %s, got: %s", want[i], got)
+		}
+	}
+}
+
+func TestHTTPStatsSetURIMatchingGroups(t *testing.T) {
+	hs := NewHTTPStats(true, false, false)
+	err := hs.SetURIMatchingGroups([]*options.MatchingGroup{
+		{
+			Name:    "update user",
+			Pattern: `^/users/[0-9]+$`,
+			Methods: []string{"put", "PATCH"},
+		},
+		{
+			Pattern: `^/users/[0-9]+$`,
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	logs := []struct {
+		uri    string
+		method string
+	}{
+		{"/users/1", "PUT"},
+		{"/users/2", "PATCH"},
+		{"/users/1", "GET"},
+		{"/users/me", "GET"},
+	}
+
+	for _, l := range logs {
+		hs.Set(l.uri, l.method, 200, 0.1, 0, 0)
+	}
+
+	want := []string{
+		"PUT update user 1",
+		"PATCH update user 1",
+		"GET ^/users/[0-9]+$ 1",
+		"GET /users/me 1",
+	}
+
+	if len(hs.Stats()) != len(want) {
+		t.Fatalf("want: %d stats, got: %d", len(want), len(hs.Stats()))
+	}
+
+	for i, s := range hs.Stats() {
+		got := fmt.Sprintf("%s %s %d", s.Method, s.Uri, s.Count())
+		if got != want[i] {
+			t.Errorf("want: %s, got: %s", want[i], got)
+		}
+	}
+}
+
+func TestHTTPStatsBodyBytes(t *testing.T) {
+	hs := NewHTTPStats(true, false, true)
+	for i := 1; i <= 10; i++ {
+		hs.Set("/foo", "POST", 200, 0.1, float64(i*100), 5)
+	}
+
+	s := hs.Stats()[0]
+
+	if s.MaxResponseBodyBytes() != 1000 || s.MinResponseBodyBytes() != 100 || s.SumResponseBodyBytes() != 5500 {
+		t.Errorf("want: max 1000, min 100, sum 5500, got: max %v, min %v, sum %v",
+			s.MaxResponseBodyBytes(), s.MinResponseBodyBytes(), s.SumResponseBodyBytes())
+	}
+
+	if s.MaxRequestBodyBytes() != 5 || s.SumRequestBodyBytes() != 50 {
+		t.Errorf("want: request max 5, sum 50, got: max %v, sum %v", s.MaxRequestBodyBytes(), s.SumRequestBodyBytes())
+	}
+
+	if got := s.PNResponseBodyBytes(90); got != 900 {
+		t.Errorf("want: p90 900, got: %v", got)
+	}
+
+	if got := fmt.Sprintf("%.3f", s.StddevResponseBodyBytes()); got != "287.228" {
+		t.Errorf("want: stddev 287.228, got: %s", got)
+	}
+
+	// the percentiles of the request body bytes are not retained
+	if got := s.PNRequestBodyBytes(90); got != 0 {
+		t.Errorf("want: request p90 0, got: %v", got)
+	}
+}
+
+func TestHTTPStatsDerivedValues(t *testing.T) {
+	hs := NewHTTPStats(true, false, false)
+	if err := hs.InitFilter(options.NewOptions()); err != nil {
+		t.Fatal(err)
+	}
+	if err := hs.SetApdexThreshold(0.5); err != nil {
+		t.Fatal(err)
+	}
+	hs.EnableRequestsPerSecond()
+
+	logs := []struct {
+		time    string
+		status  int
+		restime float64
+	}{
+		{"2015-09-06T05:58:00+09:00", 200, 0.1},
+		{"2015-09-06T05:58:02+09:00", 404, 0.5},
+		{"2015-09-06T05:58:04+09:00", 200, 1.5},
+		{"2015-09-06T05:58:06+09:00", 502, 2.5},
+		{"2015-09-06T05:58:10+09:00", 200, 0.2},
+	}
+
+	for _, l := range logs {
+		if err := hs.SetWithTime("/foo", "GET", l.time, nil, l.status, l.restime, 0, 0); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	s := hs.Stats()[0]
+
+	tests := []struct {
+		name string
+		want float64
+		got  float64
+	}{
+		{"4xx rate", 20, s.Status4xxRate()},
+		{"5xx rate", 20, s.Status5xxRate()},
+		{"requests per second", 0.5, s.RequestsPerSecond()},
+		// 3 satisfied, 1 tolerating and 1 frustrated
+		{"apdex", 0.7, s.Apdex()},
+	}
+
+	for _, tt := range tests {
+		if fmt.Sprintf("%.3f", tt.got) != fmt.Sprintf("%.3f", tt.want) {
+			t.Errorf("%s want: %v, got: %v", tt.name, tt.want, tt.got)
+		}
+	}
+}
+
+func TestHTTPStatsGroupBy(t *testing.T) {
+	hs := NewHTTPStats(true, false, false)
+	if err := hs.InitFilter(options.NewOptions()); err != nil {
+		t.Fatal(err)
+	}
+	hs.SetGroupBy([]string{"host"})
+
+	logs := []struct {
+		host string
+		uri  string
+	}{
+		{"a.example.com", "/foo"},
+		{"b.example.com", "/foo"},
+		{"a.example.com", "/foo"},
+		{"a.example.com", "/bar"},
+	}
+
+	for _, l := range logs {
+		if err := hs.SetWithTime(l.uri, "GET", "", []string{l.host}, 200, 0.1, 0, 0); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if hs.CountUris() != 3 {
+		t.Fatalf("want: 3 stats, got: %d", hs.CountUris())
+	}
+
+	counts := make(map[string]int)
+	for _, s := range hs.Stats() {
+		counts[s.Uri+" "+s.Group(0)] = s.Cnt
+	}
+
+	want := map[string]int{
+		"/foo a.example.com": 2,
+		"/foo b.example.com": 1,
+		"/bar a.example.com": 1,
+	}
+	if !reflect.DeepEqual(counts, want) {
+		t.Errorf("want: %v, got: %v", want, counts)
+	}
+}
+
+func TestHTTPStatsStatusCodes(t *testing.T) {
+	statuses := []int{200, 404, 404, 499, 502}
+
+	// no status code is counted exactly without the status codes
+	hs := NewHTTPStats(true, false, false)
+	for _, status := range statuses {
+		hs.Set("/foo", "GET", status, 0.1, 0, 0)
+	}
+
+	s := hs.Stats()[0]
+	if s.StatusCodes != nil {
+		t.Errorf("want no status codes, got: %v", s.StatusCodes)
+	}
+	if s.Status4xx != 3 {
+		t.Errorf("want: 4xx 3, got: %d", s.Status4xx)
+	}
+
+	hs = NewHTTPStats(true, false, false)
+	hs.SetStatusCodes([]int{404, 504})
+	for _, status := range statuses {
+		hs.Set("/foo", "GET", status, 0.1, 0, 0)
+	}
+
+	s = hs.Stats()[0]
+	if want := map[int]int{404: 2}; !reflect.DeepEqual(s.StatusCodes, want) {
+		t.Errorf("want: %v, got: %v", want, s.StatusCodes)
+	}
+	if s.StatusCode(504) != 0 {
+		t.Errorf("want: 504 0, got: %d", s.StatusCode(504))
+	}
+	if got := s.StatusOther([]int{404, 504}); got != 3 {
+		t.Errorf("want: other 3, got: %d", got)
+	}
+}
@@ -0,0 +1,289 @@
+package suggester
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/tkuchiki/alp/errors"
+	"github.com/tkuchiki/alp/helpers"
+	"github.com/tkuchiki/alp/options"
+	"github.com/tkuchiki/alp/parsers"
+	"github.com/tkuchiki/alp/stats"
+)
+
+// wildcardSegment matches any path segment, and is used for the segments that have many distinct values
+const wildcardSegment = `[^/]+`
+
+// segmentPatterns are the patterns of the variable path segments, in the order of the priority
+var segmentPatterns = []struct {
+	re      *regexp.Regexp
+	pattern string
+}{
+	{
+		re:      regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`),
+		pattern: `[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}`,
+	},
+	{
+		re:      regexp.MustCompile(`^[0-9]{4}-[0-9]{2}-[0-9]{2}$`),
+		pattern: `[0-9]{4}-[0-9]{2}-[0-9]{2}`,
+	},
+	{
+		re:      regexp.MustCompile(`^[0-9]+$`),
+		pattern: `[0-9]+`,
+	},
+	{
+		// hashes such as MD5, SHA-1 and SHA-256
+		re:      regexp.MustCompile(`^[0-9a-fA-F]{16,}$`),
+		pattern: `[0-9a-fA-F]+`,
+	},
+}
+
+type Suggester struct {
+	outWriter io.Writer
+	errWriter io.Writer
+	inReader  *os.File
+	parser    parsers.Parser
+	options   *options.Options
+}
+
+// Group is the suggested URI matching group
+type Group struct {
+	Pattern string
+	// the number of the distinct URIs that the pattern matches
+	Uris    int
+	Example string
+}
+
+func NewSuggester(outw, errw io.Writer, opts *options.Options) *Suggester {
+	return &Suggester{
+		outWriter: outw,
+		errWriter: errw,
+		inReader:  os.Stdin,
+		options:   opts,
+	}
+}
+
+func (s *Suggester) SetParser(parser parsers.Parser) {
+	s.parser = parser
+}
+
+func (s *Suggester) SetInReader(f *os.File) {
+	s.inReader = f
+}
+
+func (s *Suggester) Open(filename string) (io.ReadCloser, error) {
+	if filename != "" {
+		return helpers.OpenFiles(filename)
+	}
+
+	return helpers.NewDecompressReader(s.inReader), nil
+}
+
+// node is the node of the tree of the path segments
+type node struct {
+	children map[string]*node
+	// literal reports whether the segment is the literal, not the pattern
+	literal bool
+	// the number of the distinct URIs that end at the node
+	uris    int
+	example string
+}
+
+func newNode(literal bool) *node {
+	return &node{
+		children: make(map[string]*node),
+		literal:  literal,
+	}
+}
+
+func segmentPattern(segment string) (string, bool) {
+	for _, sp := range segmentPatterns {
+		if sp.re.MatchString(segment) {
+			return sp.pattern, false
+		}
+	}
+
+	return regexp.QuoteMeta(segment), true
+}
+
+func (n *node) insert(uri string) {
+	cur := n
+	for _, segment := range strings.Split(uri, "/") {
+		pattern, literal := segmentPattern(segment)
+
+		child, ok := cur.children[pattern]
+		if !ok {
+			child = newNode(literal)
+			cur.children[pattern] = child
+		}
+		cur = child
+	}
+
+	cur.uris++
+	if cur.example == "" {
+		cur.example = uri
+	}
+}
+
+func (n *node) merge(other *node) {
+	n.uris += other.uris
+	if n.example == "" {
+		n.example = other.example
+	}
+
+	for pattern, oc := range other.children {
+		child, ok := n.children[pattern]
+		if !ok {
+			n.children[pattern] = oc
+			continue
+		}
+		child.merge(oc)
+	}
+}
+
+// collapse merges the literal children into the wildcard if there are minCardinality or more of them
+func (n *node) collapse(minCardinality int) {
+	literals := make([]string, 0)
+	for pattern, child := range n.children {
+		if child.literal {
+			literals = append(literals, pattern)
+		}
+	}
+
+	if len(literals) >= minCardinality {
+		wildcard, ok := n.children[wildcardSegment]
+		if !ok {
+			wildcard = newNode(false)
+			n.children[wildcardSegment] = wildcard
+		}
+
+		// merge in the sorted order to pick the example deterministically
+		sort.Strings(literals)
+		for _, pattern := range literals {
+			wildcard.merge(n.children[pattern])
+			delete(n.children, pattern)
+		}
+	}
+
+	for _, child := range n.children {
+		child.collapse(minCardinality)
+	}
+}
+
+// groups returns the groups of the paths that have at least one variable segment
+func (n *node) groups(patterns []string, variable bool) []*Group {
+	groups := make([]*Group, 0)
+	if n.uris > 0 && variable {
+		groups = append(groups, &Group{
+			Pattern: fmt.Sprintf("^%s$", strings.Join(patterns, "/")),
+			Uris:    n.uris,
+			Example: n.example,
+		})
+	}
+
+	for pattern, child := range n.children {
+		groups = append(groups, child.groups(append(patterns[:len(patterns):len(patterns)], pattern), variable || !child.literal)...)
+	}
+
+	return groups
+}
+
+// Suggest reads the URIs, and suggests the URI matching groups for the variable path segments.
+// It also returns the number of the distinct URIs.
+func (s *Suggester) Suggest() ([]*Group, int, error) {
+	filter := stats.NewFilter(s.options)
+	err := filter.Init()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	uris := make(map[string]struct{})
+
+Loop:
+	for {
+		stat, err := s.parser.Parse()
+		if err != nil {
+			if err == io.EOF {
+				break
+			} else if err == errors.SkipReadLineErr {
+				continue Loop
+			}
+
+			return nil, 0, err
+		}
+
+		err = filter.Do(stat)
+		if err == errors.SkipReadLineErr {
+			continue Loop
+		} else if err != nil {
+			return nil, 0, err
+		}
+
+		uri, _, _ := strings.Cut(stat.Uri, "?")
+		uris[uri] = struct{}{}
+	}
+
+	// insert in the sorted order to pick the example deterministically
+	sorted := make([]string, 0, len(uris))
+	for uri := range uris {
+		sorted = append(sorted, uri)
+	}
+	sort.Strings(sorted)
+
+	root := newNode(true)
+	for _, uri := range sorted {
+		root.insert(uri)
+	}
+
+	// the root is the segment before the first slash, and is not collapsed
+	for _, child := range root.children {
+		child.collapse(s.options.SuggestGroups.MinCardinality)
+	}
+
+	var groups []*Group
+	for pattern, child := range root.children {
+		groups = append(groups, child.groups([]string{pattern}, !child.literal)...)
+	}
+
+	// the more specific patterns come first, because the first matched group is used
+	sort.SliceStable(groups, func(i, j int) bool {
+		wi := strings.Count(groups[i].Pattern, wildcardSegment)
+		wj := strings.Count(groups[j].Pattern, wildcardSegment)
+		if wi != wj {
+			return wi < wj
+		}
+
+		return groups[i].Pattern < groups[j].Pattern
+	})
+
+	return groups, len(uris), nil
+}
+
+// Print prints the groups as matching_groups of the config file
+func (s *Suggester) Print(groups []*Group, numOfUris int) {
+	grouped := 0
+	for _, g := range groups {
+		grouped += g.Uris
+	}
+
+	fmt.Fprintf(s.outWriter, "# %d of %d distinct URIs are grouped into %d groups\n", grouped, numOfUris, len(groups))
+	fmt.Fprintln(s.outWriter, "matching_groups:")
+	for _, g := range groups {
+		fmt.Fprintf(s.outWriter, "  - '%s' # %d URIs (e.g. %s)\n", strings.ReplaceAll(g.Pattern, "'", "''"), g.Uris, g.Example)
+	}
+}
+
+func (s *Suggester) SuggestAndPrint() error {
+	groups, numOfUris, err := s.Suggest()
+	if err != nil {
+		return err
+	}
+
+	s.Print(groups, numOfUris)
+
+	return nil
+}
@@ -0,0 +1,207 @@
+package suggester
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/tkuchiki/alp/options"
+	"github.com/tkuchiki/alp/parsers"
+)
+
+func TestSegmentPattern(t *testing.T) {
+	tests := []struct {
+		segment string
+		pattern string
+		literal bool
+	}{
+		{
+			segment: "123",
+			pattern: `[0-9]+`,
+		},
+		{
+			segment: "2024-01-02",
+			pattern: `[0-9]{4}-[0-9]{2}-[0-9]{2}`,
+		},
+		{
+			segment: "0f8fad5b-d9cb-469f-a165-70867728950e",
+			pattern: `[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}`,
+		},
+		{
+			segment: "d41d8cd98f00b204e9800998ecf8427e",
+			pattern: `[0-9a-fA-F]+`,
+		},
+		{
+			// the short hex is not the hash
+			segment: "cafe",
+			pattern: "cafe",
+			literal: true,
+		},
+		{
+			segment: "style.css",
+			pattern: `style\.css`,
+			literal: true,
+		},
+		{
+			segment: "",
+			pattern: "",
+			literal: true,
+		},
+	}
+
+	for _, tt := range tests {
+		pattern, literal := segmentPattern(tt.segment)
+		if pattern != tt.pattern || literal != tt.literal {
+			t.Errorf("%s want: %s %v, got: %s %v", tt.segment, tt.pattern, tt.literal, pattern, literal)
+		}
+	}
+}
+
+func TestCollapse(t *testing.T) {
+	newTree := func() *node {
+		root := newNode(true)
+		for _, uri := range []string{"/users/alice", "/users/bob", "/users/carol/posts", "/users/123"} {
+			root.insert(uri)
+		}
+
+		return root
+	}
+
+	// the 3 literals of /users are below the threshold
+	root := newTree()
+	users := root.children[""].children["users"]
+	users.collapse(4)
+	if len(users.children) != 4 {
+		t.Fatalf("want the children not to be collapsed, got: %d children", len(users.children))
+	}
+
+	root = newTree()
+	users = root.children[""].children["users"]
+	users.collapse(3)
+	if len(users.children) != 2 {
+		t.Fatalf("want the literals to be collapsed into the wildcard, got: %d children", len(users.children))
+	}
+
+	// the pattern of the number is not merged into the wildcard
+	if n := users.children[`[0-9]+`]; n == nil || n.uris != 1 {
+		t.Errorf("want the pattern of the number to be kept, got: %+v", n)
+	}
+
+	wildcard := users.children[wildcardSegment]
+	if wildcard == nil {
+		t.Fatal("want the wildcard child")
+	}
+	if wildcard.uris != 2 || wildcard.example != "/users/alice" {
+		t.Errorf("want the 2 URIs and the first example, got: %d %s", wildcard.uris, wildcard.example)
+	}
+	if posts := wildcard.children["posts"]; posts == nil || posts.uris != 1 {
+		t.Errorf("want the children of the merged literals, got: %+v", posts)
+	}
+}
+
+func ltsvUris(uris ...string) string {
+	var b strings.Builder
+	for _, uri := range uris {
+		fmt.Fprintf(&b, "time:2015-09-06T05:58:05+09:00\tmethod:GET\turi:%s\tstatus:200\tsize:10\tapptime:0.100\n", uri)
+	}
+
+	return b.String()
+}
+
+func suggest(t *testing.T, minCardinality int, log string) ([]*Group, int) {
+	t.Helper()
+
+	opts := options.NewOptions(options.SuggestGroupsMinCardinality(minCardinality))
+	label := parsers.NewLTSVLabel(opts.LTSV.UriLabel, opts.LTSV.MethodLabel, opts.LTSV.TimeLabel,
+		opts.LTSV.ApptimeLabel, opts.LTSV.ReqtimeLabel, opts.LTSV.SizeLabel, opts.LTSV.ReqsizeLabel, opts.LTSV.StatusLabel,
+	)
+
+	s := NewSuggester(io.Discard, io.Discard, opts)
+	s.SetParser(parsers.NewLTSVParser(strings.NewReader(log), label, false, false))
+
+	groups, numOfUris, err := s.Suggest()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return groups, numOfUris
+}
+
+func TestSuggest(t *testing.T) {
+	log := ltsvUris(
+		"/users/alice", "/users/bob", "/users/carol?page=1",
+		"/users/1", "/users/2/posts",
+		"/items/d41d8cd98f00b204e9800998ecf8427e",
+		// the duplicated URI is counted once
+		"/users/1",
+	)
+
+	tests := []struct {
+		name           string
+		minCardinality int
+		numOfUris      int
+		want           []Group
+	}{
+		{
+			name:           "below the threshold",
+			minCardinality: 4,
+			numOfUris:      6,
+			want: []Group{
+				{Pattern: `^/items/[0-9a-fA-F]+$`, Uris: 1, Example: "/items/d41d8cd98f00b204e9800998ecf8427e"},
+				{Pattern: `^/users/[0-9]+$`, Uris: 1, Example: "/users/1"},
+				{Pattern: `^/users/[0-9]+/posts$`, Uris: 1, Example: "/users/2/posts"},
+			},
+		},
+		{
+			// the more specific patterns come first
+			name:           "at the threshold",
+			minCardinality: 3,
+			numOfUris:      6,
+			want: []Group{
+				{Pattern: `^/items/[0-9a-fA-F]+$`, Uris: 1, Example: "/items/d41d8cd98f00b204e9800998ecf8427e"},
+				{Pattern: `^/users/[0-9]+$`, Uris: 1, Example: "/users/1"},
+				{Pattern: `^/users/[0-9]+/posts$`, Uris: 1, Example: "/users/2/posts"},
+				{Pattern: `^/users/[^/]+$`, Uris: 3, Example: "/users/alice"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			groups, numOfUris := suggest(t, tt.minCardinality, log)
+			if numOfUris != tt.numOfUris {
+				t.Errorf("want: %d URIs, got: %d", tt.numOfUris, numOfUris)
+			}
+
+			if len(groups) != len(tt.want) {
+				t.Fatalf("want: %d groups, got: %d", len(tt.want), len(groups))
+			}
+			for i, g := range groups {
+				if *g != tt.want[i] {
+					t.Errorf("group %d want: %+v, got: %+v", i, tt.want[i], *g)
+				}
+			}
+		})
+	}
+}
+
+func TestPrint(t *testing.T) {
+	var buf bytes.Buffer
+	s := NewSuggester(&buf, io.Discard, options.NewOptions())
+
+	s.Print([]*Group{
+		{Pattern: `^/users/[0-9]+$`, Uris: 2, Example: "/users/1"},
+		{Pattern: `^/it's/[^/]+$`, Uris: 3, Example: "/it's/a"},
+	}, 6)
+
+	want := `# 5 of 6 distinct URIs are grouped into 2 groups
+matching_groups:
+  - '^/users/[0-9]+$' # 2 URIs (e.g. /users/1)
+  - '^/it''s/[^/]+$' # 3 URIs (e.g. /it's/a)
+`
+	if got := buf.String(); got != want {
+		t.Errorf("want: %s, got: %s", want, got)
+	}
+}
@@ -0,0 +1,455 @@
+package tui
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/mattn/go-runewidth"
+	"github.com/tkuchiki/alp/openapi"
+	"github.com/tkuchiki/alp/options"
+	"github.com/tkuchiki/alp/parsers"
+	"github.com/tkuchiki/alp/stats"
+)
+
+type mode int
+
+const (
+	modeStats mode = iota
+	modeFilter
+	modeColumns
+	modeRequests
+)
+
+const columnGap = 2
+
+// Browser shows the HTTPStats in the terminal, and lets the user sort, filter and drill into them.
+// It keeps every request to aggregate them again when the filter is changed, and to list the requests of an endpoint.
+type Browser struct {
+	screen   tcell.Screen
+	options  *options.Options
+	requests []*parsers.ParsedHTTPStat
+	routes   []*openapi.Route
+
+	sortKeys  []string
+	sortIndex int
+	reverse   bool
+
+	keywords []string
+	visible  map[string]bool
+
+	filter      string
+	filterInput []rune
+
+	sts     *stats.HTTPStats
+	grouped map[string][]*parsers.ParsedHTTPStat
+	table   *table
+
+	selected      *stats.HTTPStat
+	requestsTable *table
+
+	mode           mode
+	statsScroll    scroller
+	columnsScroll  scroller
+	requestsScroll scroller
+	column         int
+	message        string
+}
+
+// sortKeys returns the keys of SortOptions in the order of the columns
+func sortKeys(percentiles, statusCodes []int, groupBy []string) []string {
+	keys := []string{"count"}
+	for _, code := range statusCodes {
+		keys = append(keys, strconv.Itoa(code))
+	}
+	if len(statusCodes) > 0 {
+		keys = append(keys, "other")
+	}
+
+	keys = append(keys, "method", "uri")
+	keys = append(keys, groupBy...)
+	keys = append(keys, "min", "max", "sum", "avg")
+	for _, p := range percentiles {
+		keys = append(keys, fmt.Sprintf("p%d", p))
+	}
+
+	return append(keys, "stddev", "min-body", "max-body", "sum-body", "avg-body")
+}
+
+func NewBrowser(screen tcell.Screen, opts *options.Options, sortOptions *stats.SortOptions, requests []*parsers.ParsedHTTPStat) *Browser {
+	b := &Browser{
+		screen:   screen,
+		options:  opts,
+		requests: requests,
+		sortKeys: sortKeys(opts.Percentiles, opts.StatusCodes, opts.GroupBy),
+		reverse:  opts.Reverse,
+		keywords: stats.Keywords(opts.Percentiles, opts.StatusCodes, opts.GroupBy),
+		visible:  make(map[string]bool),
+	}
+
+	for i, key := range b.sortKeys {
+		so := stats.NewSortOptions()
+		so.SetGroupBy(opts.GroupBy)
+		if err := so.SetAndValidate(key); err != nil {
+			continue
+		}
+
+		if so.SortType() == sortOptions.SortType() && so.Percentile() == sortOptions.Percentile() && so.StatusCode() == sortOptions.StatusCode() && so.Group() == sortOptions.Group() {
+			b.sortIndex = i
+			break
+		}
+	}
+
+	printer := stats.NewPrinter(io.Discard, opts.Output, "table", opts.Percentiles, opts.StatusCodes, opts.GroupBy, stats.NewPrintOptions(false, false, false, 0, false))
+	for _, key := range printer.Keywords() {
+		b.visible[key] = true
+	}
+
+	return b
+}
+
+// Run shows the results until the user quits
+func (b *Browser) Run() error {
+	err := b.aggregate()
+	if err != nil {
+		return err
+	}
+
+	err = b.screen.Init()
+	if err != nil {
+		return err
+	}
+	defer b.screen.Fini()
+
+	for {
+		b.draw()
+
+		switch ev := b.screen.PollEvent().(type) {
+		case *tcell.EventResize:
+			b.screen.Sync()
+		case *tcell.EventKey:
+			if b.handleKey(ev) {
+				return nil
+			}
+		case nil:
+			// the screen has been finalized
+			return nil
+		}
+	}
+}
+
+// SetRoutes sets the routes of the OpenAPI document to aggregate the URIs into the path templates
+func (b *Browser) SetRoutes(routes []*openapi.Route) {
+	b.routes = routes
+}
+
+func requestKey(uri, method string, groups []string) string {
+	return fmt.Sprintf("%s_%s_%s", method, uri, strings.Join(groups, "\t"))
+}
+
+// aggregate aggregates the requests that match the filter of the browser
+func (b *Browser) aggregate() error {
+	opts := *b.options
+	opts.Filters = b.filter
+
+	sts := stats.NewHTTPStats(true, false, false)
+	err := sts.InitFilter(&opts)
+	if err != nil {
+		return err
+	}
+
+	err = sts.SetPercentileEstimator(opts.PercentileEstimator, opts.PercentileAccuracy)
+	if err != nil {
+		return err
+	}
+
+	if len(opts.MatchingGroups) > 0 {
+		err = sts.SetURIMatchingGroups(opts.MatchingGroups)
+		if err != nil {
+			return err
+		}
+	}
+
+	sts.SetRoutes(b.routes)
+	sts.SetGroupBy(opts.GroupBy)
+	sts.SetStatusCodes(opts.StatusCodes)
+	sts.SetOptions(&opts)
+
+	grouped := make(map[string][]*parsers.ParsedHTTPStat)
+	for _, r := range b.requests {
+		ok, err := sts.DoFilter(r)
+		if err != nil {
+			return err
+		}
+
+		if !ok {
+			continue
+		}
+
+		groups := r.Entries.Values(opts.GroupBy)
+		err = sts.SetWithTime(r.Uri, r.Method, r.Time, groups, r.Status, r.ResponseTime, r.BodyBytes, r.RequestBodyBytes)
+		if err != nil {
+			return err
+		}
+
+		uri, _ := sts.MatchingUri(r.Uri, r.Method)
+		key := requestKey(uri, r.Method, groups)
+		grouped[key] = append(grouped[key], r)
+	}
+
+	b.sts = sts
+	b.grouped = grouped
+	b.sort()
+
+	return nil
+}
+
+func (b *Browser) sort() {
+	so := stats.NewSortOptions()
+	so.SetGroupBy(b.options.GroupBy)
+	// the keys are validated by NewBrowser
+	so.SetAndValidate(b.sortKeys[b.sortIndex])
+
+	b.sts.Sort(so, b.reverse)
+	b.table = b.statsTable()
+}
+
+func (b *Browser) visibleKeywords() []string {
+	keywords := make([]string, 0, len(b.keywords))
+	for _, key := range b.keywords {
+		if b.visible[key] {
+			keywords = append(keywords, key)
+		}
+	}
+
+	return keywords
+}
+
+func (b *Browser) statsTable() *table {
+	printOptions := stats.NewPrintOptions(false, false, b.options.DecodeUri, 0, false)
+	printer := stats.NewPrinter(io.Discard, strings.Join(b.visibleKeywords(), ","), "table", b.options.Percentiles, b.options.StatusCodes, b.options.GroupBy, printOptions)
+
+	lines := make([][]string, 0, len(b.sts.Stats()))
+	for _, s := range b.sts.Stats() {
+		lines = append(lines, printer.GenerateLine(s, false))
+	}
+
+	return newTable(printer.Headers(), lines)
+}
+
+// selectStat lists the requests of the stat under the cursor, from the slowest
+func (b *Browser) selectStat() {
+	if len(b.sts.Stats()) == 0 {
+		return
+	}
+
+	b.selected = b.sts.Stats()[b.statsScroll.cursor]
+
+	requests := append([]*parsers.ParsedHTTPStat{}, b.grouped[requestKey(b.selected.Uri, b.selected.Method, b.selected.Groups)]...)
+	sort.SliceStable(requests, func(i, j int) bool {
+		return requests[i].ResponseTime > requests[j].ResponseTime
+	})
+
+	lines := make([][]string, 0, len(requests))
+	for _, r := range requests {
+		lines = append(lines, []string{
+			r.Time,
+			strconv.Itoa(r.Status),
+			fmt.Sprintf("%.3f", r.ResponseTime),
+			fmt.Sprintf("%.3f", r.BodyBytes),
+			r.Uri,
+		})
+	}
+
+	b.requestsTable = newTable([]string{"Time", "Status", "Response Time", "Body Bytes", "Uri"}, lines)
+	b.requestsScroll = scroller{}
+	b.mode = modeRequests
+}
+
+func (b *Browser) applyFilter() {
+	prev := b.filter
+	b.filter = strings.TrimSpace(string(b.filterInput))
+
+	err := b.aggregate()
+	if err != nil {
+		// the error of expr has the position of the error in the following lines
+		b.message = fmt.Sprintf("invalid filter: %s", strings.SplitN(err.Error(), "\n", 2)[0])
+		b.filter = prev
+		b.aggregate()
+		return
+	}
+
+	b.statsScroll = scroller{}
+}
+
+func (b *Browser) toggleColumn() {
+	key := b.keywords[b.columnsScroll.cursor]
+	if b.visible[key] && len(b.visibleKeywords()) == 1 {
+		b.message = "at least one column must be shown"
+		return
+	}
+
+	b.visible[key] = !b.visible[key]
+}
+
+// handleKey handles the key event, and reports whether the user quits
+func (b *Browser) handleKey(ev *tcell.EventKey) bool {
+	b.message = ""
+
+	if ev.Key() == tcell.KeyCtrlC {
+		return true
+	}
+
+	_, height := b.screen.Size()
+
+	switch b.mode {
+	case modeFilter:
+		switch ev.Key() {
+		case tcell.KeyEnter:
+			b.mode = modeStats
+			b.applyFilter()
+		case tcell.KeyEscape:
+			b.mode = modeStats
+		case tcell.KeyBackspace, tcell.KeyBackspace2:
+			if len(b.filterInput) > 0 {
+				b.filterInput = b.filterInput[:len(b.filterInput)-1]
+			}
+		case tcell.KeyCtrlU:
+			b.filterInput = b.filterInput[:0]
+		case tcell.KeyRune:
+			b.filterInput = append(b.filterInput, ev.Rune())
+		}
+	case modeColumns:
+		if b.columnsScroll.handleKey(ev, len(b.keywords), height-2) {
+			return false
+		}
+
+		switch {
+		case ev.Key() == tcell.KeyEnter, ev.Rune() == ' ':
+			b.toggleColumn()
+		case ev.Key() == tcell.KeyEscape, ev.Rune() == 'c', ev.Rune() == 'q':
+			b.mode = modeStats
+			b.table = b.statsTable()
+			b.column = 0
+		}
+	case modeRequests:
+		if b.requestsScroll.handleKey(ev, len(b.requestsTable.lines), height-3) {
+			return false
+		}
+
+		switch {
+		case ev.Key() == tcell.KeyEscape, ev.Key() == tcell.KeyBackspace, ev.Key() == tcell.KeyBackspace2, ev.Rune() == 'q':
+			b.mode = modeStats
+		}
+	default:
+		if b.statsScroll.handleKey(ev, len(b.table.lines), height-2) {
+			return false
+		}
+
+		switch {
+		case ev.Key() == tcell.KeyEnter:
+			b.selectStat()
+		case ev.Key() == tcell.KeyLeft, ev.Rune() == 'h':
+			if b.column > 0 {
+				b.column--
+			}
+		case ev.Key() == tcell.KeyRight, ev.Rune() == 'l':
+			if b.column < len(b.table.headers)-1 {
+				b.column++
+			}
+		case ev.Rune() == 's':
+			b.sortIndex = (b.sortIndex + 1) % len(b.sortKeys)
+			b.sort()
+		case ev.Rune() == 'S':
+			b.sortIndex = (b.sortIndex + len(b.sortKeys) - 1) % len(b.sortKeys)
+			b.sort()
+		case ev.Rune() == 'r':
+			b.reverse = !b.reverse
+			b.sort()
+		case ev.Rune() == 'c':
+			b.mode = modeColumns
+		case ev.Rune() == '/':
+			b.filterInput = []rune(b.filter)
+			b.mode = modeFilter
+		case ev.Rune() == 'q':
+			return true
+		}
+	}
+
+	return false
+}
+
+func (b *Browser) draw() {
+	b.screen.Clear()
+	width, height := b.screen.Size()
+
+	headerStyle := tcell.StyleDefault.Bold(true).Reverse(true)
+
+	switch b.mode {
+	case modeColumns:
+		drawText(b.screen, 0, 0, width, padRight(" Columns", width), headerStyle)
+		b.columnsScroll.clamp(len(b.keywords), height-2)
+		for i := b.columnsScroll.offset; i < len(b.keywords) && i-b.columnsScroll.offset < height-2; i++ {
+			mark := " "
+			if b.visible[b.keywords[i]] {
+				mark = "x"
+			}
+
+			style := tcell.StyleDefault
+			if i == b.columnsScroll.cursor {
+				style = style.Reverse(true)
+			}
+			drawText(b.screen, 0, i-b.columnsScroll.offset+1, width, padRight(fmt.Sprintf(" [%s] %s", mark, b.keywords[i]), width), style)
+		}
+		b.drawStatusLine(" space:toggle  esc:back")
+	case modeRequests:
+		title := fmt.Sprintf(" %s %s (%d requests, from the slowest)", b.selected.Method, b.selected.Uri, len(b.requestsTable.lines))
+		drawText(b.screen, 0, 0, width, padRight(title, width), tcell.StyleDefault.Bold(true))
+		b.requestsScroll.clamp(len(b.requestsTable.lines), height-3)
+		b.requestsTable.draw(b.screen, 1, height-2, 0, &b.requestsScroll)
+		b.drawStatusLine(fmt.Sprintf(" %d/%d  esc:back", b.requestsScroll.cursor+1, len(b.requestsTable.lines)))
+	default:
+		b.statsScroll.clamp(len(b.table.lines), height-2)
+		b.table.draw(b.screen, 0, height-1, b.column, &b.statsScroll)
+
+		if b.mode == modeFilter {
+			b.drawStatusLine(fmt.Sprintf("/%s", string(b.filterInput)))
+			b.screen.ShowCursor(runewidth.StringWidth(string(b.filterInput))+1, height-1)
+		} else {
+			b.screen.HideCursor()
+			b.drawStatusLine(b.statusLine())
+		}
+	}
+
+	b.screen.Show()
+}
+
+func (b *Browser) statusLine() string {
+	order := "asc"
+	if b.reverse {
+		order = "desc"
+	}
+
+	filter := b.filter
+	if filter == "" {
+		filter = "-"
+	}
+
+	return fmt.Sprintf(" %d/%d  sort:%s(%s)  filter:%s  |  s/S:sort r:reverse c:columns /:filter enter:requests q:quit",
+		b.statsScroll.cursor+1, len(b.table.lines), b.sortKeys[b.sortIndex], order, filter)
+}
+
+func (b *Browser) drawStatusLine(s string) {
+	width, height := b.screen.Size()
+
+	style := tcell.StyleDefault.Reverse(true)
+	if b.message != "" {
+		s = " " + b.message
+		style = style.Foreground(tcell.ColorRed)
+	}
+
+	drawText(b.screen, 0, height-1, width, padRight(s, width), style)
+}
@@ -0,0 +1,300 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/tkuchiki/alp/options"
+	"github.com/tkuchiki/alp/parsers"
+	"github.com/tkuchiki/alp/stats"
+)
+
+func testRequests() []*parsers.ParsedHTTPStat {
+	requests := make([]*parsers.ParsedHTTPStat, 0)
+	for _, r := range []struct {
+		method string
+		uri    string
+		status int
+		rt     float64
+	}{
+		{"GET", "/foo", 200, 0.2},
+		{"GET", "/foo", 200, 0.3},
+		{"GET", "/foo", 200, 0.1},
+		{"POST", "/bar", 500, 1.0},
+		{"GET", "/baz", 200, 0.5},
+		{"GET", "/baz", 200, 0.6},
+	} {
+		requests = append(requests, &parsers.ParsedHTTPStat{
+			Method:       r.method,
+			Uri:          r.uri,
+			Status:       r.status,
+			ResponseTime: r.rt,
+			Time:         "2015-09-06T05:58:05+09:00",
+		})
+	}
+
+	return requests
+}
+
+// newTestBrowser returns the browser drawn in the simulation screen, sorted by the count
+func newTestBrowser(t *testing.T) (*Browser, tcell.SimulationScreen) {
+	t.Helper()
+
+	screen := tcell.NewSimulationScreen("UTF-8")
+	if err := screen.Init(); err != nil {
+		t.Fatal(err)
+	}
+	screen.SetSize(160, 20)
+	t.Cleanup(screen.Fini)
+
+	opts := options.NewOptions(options.Output("count,method,uri,max"))
+	b := NewBrowser(screen, opts, stats.NewSortOptions(), testRequests())
+	if err := b.aggregate(); err != nil {
+		t.Fatal(err)
+	}
+	b.draw()
+
+	return b, screen
+}
+
+// press handles the keys, and draws the screen as Run does
+func press(b *Browser, keys ...*tcell.EventKey) {
+	for _, ev := range keys {
+		b.handleKey(ev)
+		b.draw()
+	}
+}
+
+func key(k tcell.Key) *tcell.EventKey {
+	return tcell.NewEventKey(k, 0, tcell.ModNone)
+}
+
+func runes(s string) []*tcell.EventKey {
+	keys := make([]*tcell.EventKey, 0, len(s))
+	for _, r := range s {
+		keys = append(keys, tcell.NewEventKey(tcell.KeyRune, r, tcell.ModNone))
+	}
+
+	return keys
+}
+
+// screenLines returns the text of each line of the screen without the trailing spaces
+func screenLines(screen tcell.SimulationScreen) []string {
+	cells, width, height := screen.GetContents()
+
+	lines := make([]string, height)
+	for y := 0; y < height; y++ {
+		var sb strings.Builder
+		for x := 0; x < width; x++ {
+			cell := cells[y*width+x]
+			if len(cell.Runes) == 0 {
+				sb.WriteRune(' ')
+				continue
+			}
+			sb.WriteString(string(cell.Runes))
+		}
+		lines[y] = strings.TrimRight(sb.String(), " ")
+	}
+
+	return lines
+}
+
+// uriOrder returns the URIs of the table in the order of the lines of the screen
+func uriOrder(screen tcell.SimulationScreen) []string {
+	lines := screenLines(screen)
+
+	// the last line is the status line
+	uris := make([]string, 0)
+	for _, line := range lines[:len(lines)-1] {
+		for _, field := range strings.Fields(line) {
+			if strings.HasPrefix(field, "/") {
+				uris = append(uris, field)
+			}
+		}
+	}
+
+	return uris
+}
+
+func statusLine(screen tcell.SimulationScreen) string {
+	lines := screenLines(screen)
+	return lines[len(lines)-1]
+}
+
+func assertOrder(t *testing.T, screen tcell.SimulationScreen, want ...string) {
+	t.Helper()
+
+	if got := uriOrder(screen); strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("want: %v, got: %v\n%s", want, got, strings.Join(screenLines(screen), "\n"))
+	}
+}
+
+func TestBrowserSort(t *testing.T) {
+	b, screen := newTestBrowser(t)
+
+	assertOrder(t, screen, "/bar", "/baz", "/foo")
+	if !strings.Contains(statusLine(screen), "sort:count(asc)") {
+		t.Errorf("want the sort key in the status line, got: %s", statusLine(screen))
+	}
+
+	press(b, runes("r")...)
+	assertOrder(t, screen, "/foo", "/baz", "/bar")
+	if !strings.Contains(statusLine(screen), "sort:count(desc)") {
+		t.Errorf("want the reversed order in the status line, got: %s", statusLine(screen))
+	}
+
+	// the next keys of count are method and uri
+	press(b, runes("rss")...)
+	assertOrder(t, screen, "/bar", "/baz", "/foo")
+	if !strings.Contains(statusLine(screen), "sort:uri(asc)") {
+		t.Errorf("want the sort key of uri, got: %s", statusLine(screen))
+	}
+
+	press(b, runes("SS")...)
+	if !strings.Contains(statusLine(screen), "sort:count(asc)") {
+		t.Errorf("want the previous sort key, got: %s", statusLine(screen))
+	}
+}
+
+func TestBrowserColumns(t *testing.T) {
+	b, screen := newTestBrowser(t)
+
+	header := screenLines(screen)[0]
+	for _, want := range []string{"Count", "Method", "Uri", "Max"} {
+		if !strings.Contains(header, want) {
+			t.Fatalf("want %s in the header, got: %s", want, header)
+		}
+	}
+
+	// count is the first column of the list
+	press(b, runes("c")...)
+	if lines := screenLines(screen); lines[0] != " Columns" || lines[1] != " [x] count" {
+		t.Fatalf("want the list of the columns, got:\n%s", strings.Join(lines, "\n"))
+	}
+
+	press(b, runes(" ")...)
+	if line := screenLines(screen)[1]; line != " [ ] count" {
+		t.Errorf("want count to be hidden, got: %s", line)
+	}
+
+	press(b, key(tcell.KeyEscape))
+	if header = screenLines(screen)[0]; strings.Contains(header, "Count") || !strings.Contains(header, "Uri") {
+		t.Errorf("want the header without Count, got: %s", header)
+	}
+
+	// the last visible column cannot be hidden
+	press(b, runes("c")...)
+	for _, k := range []string{"method", "uri", "max"} {
+		for b.keywords[b.columnsScroll.cursor] != k {
+			press(b, runes("j")...)
+		}
+		press(b, runes(" ")...)
+	}
+	if !strings.Contains(statusLine(screen), "at least one column must be shown") {
+		t.Errorf("want the message, got: %s", statusLine(screen))
+	}
+}
+
+func TestBrowserFilter(t *testing.T) {
+	b, screen := newTestBrowser(t)
+
+	press(b, runes("/")...)
+	press(b, runes("Uri == '/foo'")...)
+	if line := statusLine(screen); line != "/Uri == '/foo'" {
+		t.Errorf("want the filter input, got: %s", line)
+	}
+
+	press(b, key(tcell.KeyEnter))
+	assertOrder(t, screen, "/foo")
+	if !strings.Contains(statusLine(screen), "filter:Uri == '/foo'") {
+		t.Errorf("want the filter in the status line, got: %s", statusLine(screen))
+	}
+
+	// the invalid filter keeps the previous filter
+	press(b, runes("/")...)
+	press(b, key(tcell.KeyCtrlU))
+	press(b, runes("Uri ==")...)
+	press(b, key(tcell.KeyEnter))
+	if !strings.Contains(statusLine(screen), "invalid filter") {
+		t.Errorf("want the error message, got: %s", statusLine(screen))
+	}
+	assertOrder(t, screen, "/foo")
+
+	// the empty filter clears the filter
+	press(b, runes("/")...)
+	press(b, key(tcell.KeyCtrlU), key(tcell.KeyEnter))
+	assertOrder(t, screen, "/bar", "/baz", "/foo")
+}
+
+func TestBrowserRequests(t *testing.T) {
+	b, screen := newTestBrowser(t)
+
+	// the last line is /foo
+	press(b, runes("G")...)
+	press(b, key(tcell.KeyEnter))
+
+	lines := screenLines(screen)
+	if lines[0] != " GET /foo (3 requests, from the slowest)" {
+		t.Fatalf("want the title of the requests, got:\n%s", strings.Join(lines, "\n"))
+	}
+
+	want := []string{"0.300", "0.200", "0.100"}
+	for i, rt := range want {
+		if !strings.Contains(lines[2+i], rt) {
+			t.Errorf("want the response time %s in line %d, got: %s", rt, 2+i, lines[2+i])
+		}
+	}
+
+	press(b, key(tcell.KeyEscape))
+	assertOrder(t, screen, "/bar", "/baz", "/foo")
+}
+
+// initScreen notifies that the screen is initialized, so that the keys can be injected
+type initScreen struct {
+	tcell.SimulationScreen
+	ready chan struct{}
+}
+
+func (s *initScreen) Init() error {
+	err := s.SimulationScreen.Init()
+	close(s.ready)
+
+	return err
+}
+
+func TestBrowserRun(t *testing.T) {
+	screen := &initScreen{
+		SimulationScreen: tcell.NewSimulationScreen("UTF-8"),
+		ready:            make(chan struct{}),
+	}
+	b := NewBrowser(screen, options.NewOptions(), stats.NewSortOptions(), testRequests())
+
+	done := make(chan error, 1)
+	go func() {
+		done <- b.Run()
+	}()
+
+	select {
+	case <-screen.ready:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Run does not initialize the screen")
+	}
+	for _, r := range "rsq" {
+		screen.InjectKey(tcell.KeyRune, r, tcell.ModNone)
+	}
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("q does not quit")
+	}
+
+	if !b.reverse || b.sortKeys[b.sortIndex] != "method" {
+		t.Errorf("want the keys before q to be handled, got: reverse=%v sort=%s", b.reverse, b.sortKeys[b.sortIndex])
+	}
+}
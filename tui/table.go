@@ -0,0 +1,129 @@
+package tui
+
+import (
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/mattn/go-runewidth"
+)
+
+type table struct {
+	headers []string
+	lines   [][]string
+	widths  []int
+}
+
+func newTable(headers []string, lines [][]string) *table {
+	widths := make([]int, len(headers))
+	for i, h := range headers {
+		widths[i] = runewidth.StringWidth(h)
+	}
+
+	for _, line := range lines {
+		for i, v := range line {
+			if w := runewidth.StringWidth(v); i < len(widths) && w > widths[i] {
+				widths[i] = w
+			}
+		}
+	}
+
+	return &table{
+		headers: headers,
+		lines:   lines,
+		widths:  widths,
+	}
+}
+
+func (t *table) row(values []string, column int) string {
+	var sb strings.Builder
+	for i := column; i < len(values) && i < len(t.widths); i++ {
+		sb.WriteString(" ")
+		sb.WriteString(padRight(values[i], t.widths[i]+columnGap-1))
+	}
+
+	return sb.String()
+}
+
+// draw draws the header at the line y, and the lines from the next line to the line bottom (exclusive).
+// The columns before column are skipped to scroll horizontally.
+func (t *table) draw(screen tcell.Screen, y, bottom, column int, s *scroller) {
+	width, _ := screen.Size()
+
+	drawText(screen, 0, y, width, padRight(t.row(t.headers, column), width), tcell.StyleDefault.Bold(true).Reverse(true))
+
+	for i := s.offset; i < len(t.lines) && y+1+i-s.offset < bottom; i++ {
+		style := tcell.StyleDefault
+		if i == s.cursor {
+			style = style.Reverse(true)
+		}
+
+		drawText(screen, 0, y+1+i-s.offset, width, padRight(t.row(t.lines[i], column), width), style)
+	}
+}
+
+// scroller keeps the cursor and the first line shown in the screen
+type scroller struct {
+	cursor int
+	offset int
+}
+
+// clamp keeps the cursor in n lines, and the cursor in the height lines from the offset
+func (s *scroller) clamp(n, height int) {
+	if s.cursor >= n {
+		s.cursor = n - 1
+	}
+	if s.cursor < 0 {
+		s.cursor = 0
+	}
+
+	if height < 1 {
+		height = 1
+	}
+
+	if s.cursor < s.offset {
+		s.offset = s.cursor
+	}
+	if s.cursor >= s.offset+height {
+		s.offset = s.cursor - height + 1
+	}
+}
+
+// handleKey moves the cursor by the key, and reports whether the key is handled
+func (s *scroller) handleKey(ev *tcell.EventKey, n, height int) bool {
+	switch {
+	case ev.Key() == tcell.KeyUp, ev.Rune() == 'k':
+		s.cursor--
+	case ev.Key() == tcell.KeyDown, ev.Rune() == 'j':
+		s.cursor++
+	case ev.Key() == tcell.KeyPgUp, ev.Key() == tcell.KeyCtrlB:
+		s.cursor -= height
+	case ev.Key() == tcell.KeyPgDn, ev.Key() == tcell.KeyCtrlF:
+		s.cursor += height
+	case ev.Key() == tcell.KeyHome, ev.Rune() == 'g':
+		s.cursor = 0
+	case ev.Key() == tcell.KeyEnd, ev.Rune() == 'G':
+		s.cursor = n - 1
+	default:
+		return false
+	}
+
+	s.clamp(n, height)
+
+	return true
+}
+
+func padRight(s string, width int) string {
+	return runewidth.FillRight(runewidth.Truncate(s, width, ""), width)
+}
+
+func drawText(screen tcell.Screen, x, y, maxX int, s string, style tcell.Style) {
+	for _, r := range s {
+		w := runewidth.RuneWidth(r)
+		if x+w > maxX {
+			return
+		}
+
+		screen.SetContent(x, y, r, nil, style)
+		x += w
+	}
+}